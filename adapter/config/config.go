@@ -2,6 +2,9 @@ package config
 
 import (
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -13,6 +16,8 @@ type (
 		ImageCloud *ImageCloud
 		Mail       *Mail
 		Token      *Token
+		Scheduler  *Scheduler
+		Report     *Report
 	}
 
 	App struct {
@@ -23,27 +28,72 @@ type (
 	}
 
 	DB struct {
-		Connection   string
-		Database     string
-		Transactions string
-		Origin       string
-		Users        string
+		Connection       string
+		Database         string
+		Transactions     string
+		Origin           string
+		Users            string
+		Tags             string
+		Attachments      string
+		ReportDeliveries string
+		ReportRuns       string
+		Outbox           string
+		Snapshots        string
+		ExpectedItems    string
+		Debts            string
+		Reconciliations  string
+		Ledger           string
+		Audit            string
 	}
 
 	ImageCloud struct {
-		Connection string
+		Provider    string
+		Connection  string
+		LocalPath   string
+		PublicUrl   string
+		SigningKey  string
+		S3Endpoint  string
+		S3Region    string
+		S3Bucket    string
+		S3AccessKey string
+		S3SecretKey string
+		UrlTTL      time.Duration
 	}
 
 	Mail struct {
-		Host     string
-		Port     string
-		Username string
-		Password string
+		Host         string
+		Port         string
+		Username     string
+		Password     string
+		TLS          bool
+		MaxAttempts  int
+		RetryBackoff time.Duration
+		BrandName    string
+		AppURL       string
+		LogoURL      string
+		TemplatesDir string
+		AttachPDF    bool
 	}
 
 	Token struct {
 		JwtSecret string
 	}
+
+	Report struct {
+		IncomeSubjects  []string
+		ExpenseSubjects []string
+	}
+
+	Scheduler struct {
+		Enabled           bool
+		Interval          time.Duration
+		ReportConcurrency int
+		ReportDay         int
+		OutboxInterval    time.Duration
+		SnapshotInterval  time.Duration
+		PurgeInterval     time.Duration
+		TrashRetention    time.Duration
+	}
 )
 
 func New() (*Container, error) {
@@ -63,33 +113,142 @@ func New() (*Container, error) {
 	}
 
 	db := &DB{
-		Connection:   os.Getenv("MONGO_CONNECTION_STRING"),
-		Database:     os.Getenv("MONGO_DATABASE_NAME"),
-		Transactions: os.Getenv("MONGO_COLLECTION_TRANSACTION"),
-		Origin:       os.Getenv("MONGO_COLLECTION_ORIGIN"),
-		Users:        os.Getenv("MONGO_COLLECTION_USER"),
+		Connection:       os.Getenv("MONGO_CONNECTION_STRING"),
+		Database:         os.Getenv("MONGO_DATABASE_NAME"),
+		Transactions:     os.Getenv("MONGO_COLLECTION_TRANSACTION"),
+		Origin:           os.Getenv("MONGO_COLLECTION_ORIGIN"),
+		Users:            os.Getenv("MONGO_COLLECTION_USER"),
+		Tags:             os.Getenv("MONGO_COLLECTION_TAG"),
+		Attachments:      os.Getenv("MONGO_COLLECTION_ATTACHMENT"),
+		ReportDeliveries: os.Getenv("MONGO_COLLECTION_REPORT_DELIVERY"),
+		ReportRuns:       os.Getenv("MONGO_COLLECTION_REPORT_RUN"),
+		Outbox:           os.Getenv("MONGO_COLLECTION_OUTBOX"),
+		Snapshots:        os.Getenv("MONGO_COLLECTION_SNAPSHOT"),
+		ExpectedItems:    os.Getenv("MONGO_COLLECTION_EXPECTED_ITEM"),
+		Debts:            os.Getenv("MONGO_COLLECTION_DEBT"),
+		Reconciliations:  os.Getenv("MONGO_COLLECTION_RECONCILIATION"),
+		Ledger:           os.Getenv("MONGO_COLLECTION_LEDGER"),
+		Audit:            os.Getenv("MONGO_COLLECTION_AUDIT"),
 	}
 
 	imageCloud := &ImageCloud{
-		Connection: os.Getenv("CLOUDINARY_URL"),
+		Provider:    os.Getenv("STORAGE_PROVIDER"),
+		Connection:  os.Getenv("CLOUDINARY_URL"),
+		LocalPath:   os.Getenv("LOCAL_STORAGE_PATH"),
+		PublicUrl:   os.Getenv("STORAGE_PUBLIC_URL"),
+		SigningKey:  os.Getenv("STORAGE_SIGNING_KEY"),
+		S3Endpoint:  os.Getenv("S3_ENDPOINT"),
+		S3Region:    os.Getenv("S3_REGION"),
+		S3Bucket:    os.Getenv("S3_BUCKET"),
+		S3AccessKey: os.Getenv("S3_ACCESS_KEY"),
+		S3SecretKey: os.Getenv("S3_SECRET_KEY"),
+		UrlTTL:      15 * time.Minute,
+	}
+
+	if imageCloud.Provider == "" {
+		imageCloud.Provider = "cloudinary"
+	}
+	if imageCloud.LocalPath == "" {
+		imageCloud.LocalPath = "uploads"
+	}
+	if imageCloud.S3Region == "" {
+		imageCloud.S3Region = "us-east-1"
+	}
+	if ttl, err := time.ParseDuration(os.Getenv("STORAGE_URL_TTL")); err == nil && ttl > 0 {
+		imageCloud.UrlTTL = ttl
 	}
 
 	mailService := &Mail{
-		Host:     os.Getenv("MAIL_SERVICE_HOST"),
-		Port:     os.Getenv("MAIL_SERVICE_PORT"),
-		Username: os.Getenv("MAIL_SERVICE_USERNAME"),
-		Password: os.Getenv("MAIL_SERVICE_PASS"),
+		Host:         os.Getenv("MAIL_SERVICE_HOST"),
+		Port:         os.Getenv("MAIL_SERVICE_PORT"),
+		Username:     os.Getenv("MAIL_SERVICE_USERNAME"),
+		Password:     os.Getenv("MAIL_SERVICE_PASS"),
+		TLS:          os.Getenv("MAIL_SERVICE_TLS") != "false",
+		MaxAttempts:  6,
+		RetryBackoff: time.Minute,
+		BrandName:    os.Getenv("MAIL_BRAND_NAME"),
+		AppURL:       os.Getenv("MAIL_APP_URL"),
+		LogoURL:      os.Getenv("MAIL_LOGO_URL"),
+		TemplatesDir: os.Getenv("MAIL_TEMPLATES_DIR"),
+		AttachPDF:    os.Getenv("MAIL_ATTACH_PDF") == "true",
+	}
+
+	if mailService.BrandName == "" {
+		mailService.BrandName = "Personal finance"
+	}
+	if mailService.AppURL == "" {
+		mailService.AppURL = "https://tavo826.github.io/Finance-With-Angular-Front/Home"
+	}
+
+	if attempts, err := strconv.Atoi(os.Getenv("MAIL_MAX_ATTEMPTS")); err == nil && attempts > 0 {
+		mailService.MaxAttempts = attempts
+	}
+	if backoff, err := time.ParseDuration(os.Getenv("MAIL_RETRY_BACKOFF")); err == nil && backoff > 0 {
+		mailService.RetryBackoff = backoff
 	}
 
 	token := &Token{
 		JwtSecret: os.Getenv("JWT_SECRET"),
 	}
 
+	scheduler := &Scheduler{
+		Enabled:           os.Getenv("SCHEDULER_ENABLED") != "false",
+		Interval:          time.Hour,
+		ReportConcurrency: 4,
+		ReportDay:         1,
+		OutboxInterval:    15 * time.Second,
+		SnapshotInterval:  time.Hour,
+		PurgeInterval:     24 * time.Hour,
+		TrashRetention:    30 * 24 * time.Hour,
+	}
+
+	if interval, err := time.ParseDuration(os.Getenv("SCHEDULER_INTERVAL")); err == nil && interval > 0 {
+		scheduler.Interval = interval
+	}
+	if concurrency, err := strconv.Atoi(os.Getenv("REPORT_CONCURRENCY")); err == nil && concurrency > 0 {
+		scheduler.ReportConcurrency = concurrency
+	}
+	if day, err := strconv.Atoi(os.Getenv("REPORT_DAY")); err == nil && day >= 1 && day <= 31 {
+		scheduler.ReportDay = day
+	}
+	if interval, err := time.ParseDuration(os.Getenv("OUTBOX_INTERVAL")); err == nil && interval > 0 {
+		scheduler.OutboxInterval = interval
+	}
+	if interval, err := time.ParseDuration(os.Getenv("SNAPSHOT_INTERVAL")); err == nil && interval > 0 {
+		scheduler.SnapshotInterval = interval
+	}
+	if interval, err := time.ParseDuration(os.Getenv("PURGE_INTERVAL")); err == nil && interval > 0 {
+		scheduler.PurgeInterval = interval
+	}
+	if retention, err := time.ParseDuration(os.Getenv("TRASH_RETENTION")); err == nil && retention > 0 {
+		scheduler.TrashRetention = retention
+	}
+
+	report := &Report{
+		IncomeSubjects:  splitList(os.Getenv("REPORT_INCOME_SUBJECTS")),
+		ExpenseSubjects: splitList(os.Getenv("REPORT_EXPENSE_SUBJECTS")),
+	}
+
 	return &Container{
 		app,
 		db,
 		imageCloud,
 		mailService,
 		token,
+		scheduler,
+		report,
 	}, nil
 }
+
+// splitList parses a comma separated list, ignoring blank items.
+func splitList(value string) []string {
+
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
@@ -0,0 +1,35 @@
+package http
+
+import (
+	"personal-finance/adapter/handler/http/dto"
+	"personal-finance/core/port"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AnalyticsHandler struct {
+	service port.AnalyticsService
+}
+
+func NewAnalyticsHandler(service port.AnalyticsService) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		service,
+	}
+}
+
+func (ah *AnalyticsHandler) GetTrends(ctx *gin.Context) {
+
+	var request dto.TrendsRequest
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		dto.ValidationError(ctx, err)
+		return
+	}
+
+	trends, err := ah.service.GetTrends(ctx, request.UserId, request.From, request.To)
+	if err != nil {
+		dto.HandleError(ctx, err)
+		return
+	}
+
+	dto.HandleSuccess(ctx, trends)
+}
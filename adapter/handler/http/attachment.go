@@ -0,0 +1,117 @@
+package http
+
+import (
+	"personal-finance/adapter/handler/http/dto"
+	"personal-finance/core/domain"
+	"personal-finance/core/port"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AttachmentHandler struct {
+	service port.AttachmentService
+}
+
+func NewAttachmentHandler(service port.AttachmentService) *AttachmentHandler {
+	return &AttachmentHandler{
+		service,
+	}
+}
+
+func (ah *AttachmentHandler) GetAttachmentsByTransactionId(ctx *gin.Context) {
+
+	var request dto.IdRequest
+	var attachmentList []dto.AttachmentResponse
+
+	if err := ctx.ShouldBindUri(&request); err != nil {
+		dto.ValidationError(ctx, err)
+		return
+	}
+
+	attachments, err := ah.service.GetAttachmentsByTransactionId(ctx, request.ID)
+	if err != nil {
+		dto.HandleError(ctx, err)
+		return
+	}
+
+	for _, attachment := range attachments {
+		attachmentList = append(attachmentList, dto.NewAttachmentResponse(&attachment))
+	}
+
+	if attachmentList == nil {
+		attachmentList = []dto.AttachmentResponse{}
+	}
+
+	dto.HandleSuccess(ctx, attachmentList)
+}
+
+func (ah *AttachmentHandler) UploadAttachment(ctx *gin.Context) {
+
+	const MaxAttachmentSize = 10 << 20
+
+	var request dto.IdRequest
+	if err := ctx.ShouldBindUri(&request); err != nil {
+		dto.ValidationError(ctx, err)
+		return
+	}
+
+	if err := ctx.Request.ParseMultipartForm(MaxAttachmentSize); err != nil {
+		dto.ValidationError(ctx, err)
+		return
+	}
+
+	file, header, err := ctx.Request.FormFile("file")
+	if err != nil {
+		dto.HandleError(ctx, domain.ErrGettingFile)
+		return
+	}
+	defer file.Close()
+
+	if header.Size > MaxAttachmentSize {
+		dto.HandleError(ctx, domain.ErrAttachmentSize)
+		return
+	}
+
+	contentType := header.Header.Get("Content-Type")
+	if !isValidAttachmentType(contentType) {
+		dto.HandleError(ctx, domain.ErrFileType)
+		return
+	}
+
+	attachment, err := ah.service.UploadAttachment(ctx, request.ID, file, header.Filename, contentType)
+	if err != nil {
+		dto.HandleError(ctx, err)
+		return
+	}
+
+	response := dto.NewAttachmentResponse(attachment)
+
+	dto.HandleSuccess(ctx, response)
+}
+
+func (ah *AttachmentHandler) DeleteAttachment(ctx *gin.Context) {
+
+	var request dto.AttachmentIdRequest
+	if err := ctx.ShouldBindUri(&request); err != nil {
+		dto.ValidationError(ctx, err)
+		return
+	}
+
+	err := ah.service.DeleteAttachment(ctx, request.ID, request.AttachmentId)
+	if err != nil {
+		dto.HandleError(ctx, err)
+		return
+	}
+
+	dto.HandleSuccess(ctx, nil)
+}
+
+func isValidAttachmentType(contentType string) bool {
+
+	if isValidImageType(contentType) {
+		return true
+	}
+
+	return strings.EqualFold(contentType, "application/pdf")
+}
@@ -0,0 +1,54 @@
+package http
+
+import (
+	"personal-finance/adapter/handler/http/dto"
+	"personal-finance/core/domain"
+	"personal-finance/core/port"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuditHandler struct {
+	service port.AuditService
+}
+
+func NewAuditHandler(service port.AuditService) *AuditHandler {
+	return &AuditHandler{
+		service,
+	}
+}
+
+func (ah *AuditHandler) GetHistory(ctx *gin.Context) {
+
+	var request dto.HistoryUriRequest
+	if err := ctx.ShouldBindUri(&request); err != nil {
+		dto.ValidationError(ctx, err)
+		return
+	}
+
+	var req dto.HistoryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		dto.ValidationError(ctx, err)
+		return
+	}
+
+	records, totalDocuments, totalPages, err := ah.service.GetHistory(ctx, req.UserId, request.Entity, request.ID, req.Page, req.Limit)
+	if err != nil {
+		dto.HandleError(ctx, err)
+		return
+	}
+
+	if records == nil {
+		records = []domain.AuditRecord{}
+	}
+
+	response := dto.NewPaginatedResponse(
+		req.Page,
+		req.Limit,
+		totalDocuments,
+		totalPages,
+		records,
+	)
+
+	dto.HandleSuccess(ctx, response)
+}
@@ -216,6 +216,9 @@ func (ah *AuthHandler) UpdateUser(ctx *gin.Context) {
 	user.Password = actualUser.Password
 	user.Role = actualUser.Role
 	user.CreatedAt = actualUser.CreatedAt
+	user.MonthlyReportOptOut = actualUser.MonthlyReportOptOut
+	user.Locale = actualUser.Locale
+	user.Currency = actualUser.Currency
 
 	_, err = ah.service.UpdateUser(ctx, id, &user)
 	if err != nil {
@@ -228,6 +231,41 @@ func (ah *AuthHandler) UpdateUser(ctx *gin.Context) {
 	dto.HandleSuccess(ctx, response)
 }
 
+func (ah *AuthHandler) UpdatePreferences(ctx *gin.Context) {
+
+	id := ctx.Param("id")
+
+	if ctx.GetString("userID") != id {
+		dto.HandleError(ctx, domain.ErrForbidden)
+		return
+	}
+
+	var req dto.PreferencesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		dto.ValidationError(ctx, err)
+		return
+	}
+
+	preferences := domain.UserPreferences{
+		MonthlyReportOptOut: req.MonthlyReportOptOut,
+		Locale:              req.Locale,
+		Currency:            req.Currency,
+	}
+
+	if err := ah.service.UpdatePreferences(ctx, id, preferences); err != nil {
+		dto.HandleError(ctx, err)
+		return
+	}
+
+	user, err := ah.service.GetUserById(ctx, id)
+	if err != nil {
+		dto.HandleError(ctx, err)
+		return
+	}
+
+	dto.HandleSuccess(ctx, dto.NewUserResponse(user))
+}
+
 func (ah *AuthHandler) DeleteUser(ctx *gin.Context) {
 	var request dto.IdRequest
 	if err := ctx.ShouldBindUri(&request); err != nil {
@@ -0,0 +1,52 @@
+package http
+
+import (
+	"personal-finance/adapter/handler/http/dto"
+	"personal-finance/core/port"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BalanceHandler struct {
+	service port.BalanceService
+}
+
+func NewBalanceHandler(service port.BalanceService) *BalanceHandler {
+	return &BalanceHandler{
+		service,
+	}
+}
+
+func (bh *BalanceHandler) AuditBalances(ctx *gin.Context) {
+
+	var req dto.RequestByUserId
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		dto.ValidationError(ctx, err)
+		return
+	}
+
+	audit, err := bh.service.AuditBalances(ctx, req.UserId)
+	if err != nil {
+		dto.HandleError(ctx, err)
+		return
+	}
+
+	dto.HandleSuccess(ctx, audit)
+}
+
+func (bh *BalanceHandler) RepairBalances(ctx *gin.Context) {
+
+	var req dto.RequestByUserId
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		dto.ValidationError(ctx, err)
+		return
+	}
+
+	audit, err := bh.service.RepairBalances(ctx, req.UserId)
+	if err != nil {
+		dto.HandleError(ctx, err)
+		return
+	}
+
+	dto.HandleSuccess(ctx, audit)
+}
@@ -0,0 +1,209 @@
+package http
+
+import (
+	"personal-finance/adapter/handler/http/dto"
+	"personal-finance/core/domain"
+	"personal-finance/core/port"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DebtHandler struct {
+	service port.DebtService
+}
+
+func NewDebtHandler(service port.DebtService) *DebtHandler {
+	return &DebtHandler{
+		service,
+	}
+}
+
+func (dh *DebtHandler) GetDebtsByUserId(ctx *gin.Context) {
+
+	var req dto.RequestByUserId
+	var debtList []dto.DebtResponse
+
+	if err := ctx.Bind(&req); err != nil {
+		dto.ValidationError(ctx, err)
+		return
+	}
+
+	debts, err := dh.service.GetDebtsByUserId(ctx, req.UserId)
+	if err != nil {
+		dto.HandleError(ctx, err)
+		return
+	}
+
+	for _, debt := range debts {
+		debtList = append(debtList, dto.NewDebtResponse(&debt))
+	}
+
+	if debtList == nil {
+		debtList = []dto.DebtResponse{}
+	}
+
+	dto.HandleSuccess(ctx, debtList)
+}
+
+func (dh *DebtHandler) GetDebtStatus(ctx *gin.Context) {
+
+	var request dto.IdRequest
+	if err := ctx.ShouldBindUri(&request); err != nil {
+		dto.ValidationError(ctx, err)
+		return
+	}
+
+	status, err := dh.service.GetDebtStatus(ctx, request.ID)
+	if err != nil {
+		dto.HandleError(ctx, err)
+		return
+	}
+
+	dto.HandleSuccess(ctx, status)
+}
+
+func (dh *DebtHandler) CreateDebt(ctx *gin.Context) {
+
+	var req dto.DebtRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		dto.ValidationError(ctx, err)
+		return
+	}
+
+	debt := newDebt(req)
+	debt.CreatedAt = time.Now()
+
+	_, err := dh.service.CreateDebt(ctx, &debt)
+	if err != nil {
+		dto.HandleError(ctx, err)
+		return
+	}
+
+	response := dto.NewDebtResponse(&debt)
+
+	dto.HandleSuccess(ctx, response)
+}
+
+func (dh *DebtHandler) UpdateDebt(ctx *gin.Context) {
+
+	var req dto.DebtRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		dto.ValidationError(ctx, err)
+		return
+	}
+
+	id := ctx.Param("id")
+
+	debt := newDebt(req)
+
+	_, err := dh.service.UpdateDebt(ctx, id, &debt)
+	if err != nil {
+		dto.HandleError(ctx, err)
+		return
+	}
+
+	response := dto.NewDebtResponse(&debt)
+
+	dto.HandleSuccess(ctx, response)
+}
+
+func (dh *DebtHandler) DeleteDebt(ctx *gin.Context) {
+
+	var request dto.IdRequest
+	if err := ctx.ShouldBindUri(&request); err != nil {
+		dto.ValidationError(ctx, err)
+		return
+	}
+
+	err := dh.service.DeleteDebt(ctx, request.ID)
+	if err != nil {
+		dto.HandleError(ctx, err)
+		return
+	}
+
+	dto.HandleSuccess(ctx, nil)
+}
+
+func (dh *DebtHandler) GetSchedule(ctx *gin.Context) {
+
+	var request dto.IdRequest
+	if err := ctx.ShouldBindUri(&request); err != nil {
+		dto.ValidationError(ctx, err)
+		return
+	}
+
+	schedule, err := dh.service.GetSchedule(ctx, request.ID)
+	if err != nil {
+		dto.HandleError(ctx, err)
+		return
+	}
+
+	dto.HandleSuccess(ctx, schedule)
+}
+
+func (dh *DebtHandler) RecordPayment(ctx *gin.Context) {
+
+	var request dto.IdRequest
+	if err := ctx.ShouldBindUri(&request); err != nil {
+		dto.ValidationError(ctx, err)
+		return
+	}
+
+	var req dto.DebtPaymentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		dto.ValidationError(ctx, err)
+		return
+	}
+
+	if req.Date.IsZero() {
+		req.Date = time.Now()
+	}
+
+	transaction, err := dh.service.RecordPayment(ctx, request.ID, req.Amount, req.Date, req.FromOriginId)
+	if err != nil {
+		dto.HandleError(ctx, err)
+		return
+	}
+
+	response := dto.NewTransactionResponse(transaction)
+
+	dto.HandleSuccess(ctx, response)
+}
+
+func (dh *DebtHandler) SimulateExtraPayments(ctx *gin.Context) {
+
+	var request dto.IdRequest
+	if err := ctx.ShouldBindUri(&request); err != nil {
+		dto.ValidationError(ctx, err)
+		return
+	}
+
+	var req dto.DebtSimulationRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		dto.ValidationError(ctx, err)
+		return
+	}
+
+	simulation, err := dh.service.SimulateExtraPayments(ctx, request.ID, req.ExtraMonthly, req.LumpSum)
+	if err != nil {
+		dto.HandleError(ctx, err)
+		return
+	}
+
+	dto.HandleSuccess(ctx, simulation)
+}
+
+func newDebt(req dto.DebtRequest) domain.Debt {
+
+	return domain.Debt{
+		UserId:       req.UserId,
+		OriginId:     req.OriginId,
+		Name:         req.Name,
+		Principal:    req.Principal,
+		InterestRate: req.InterestRate,
+		TermMonths:   req.TermMonths,
+		StartDate:    req.StartDate,
+		UpdatedAt:    time.Now(),
+	}
+}
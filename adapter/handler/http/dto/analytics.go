@@ -0,0 +1,10 @@
+package dto
+
+import "time"
+
+// TrendsRequest selects the months from through to, both included ("2006-01").
+type TrendsRequest struct {
+	UserId string    `form:"user_id" binding:"required"`
+	From   time.Time `form:"from" time_format:"2006-01" binding:"required"`
+	To     time.Time `form:"to" time_format:"2006-01" binding:"required"`
+}
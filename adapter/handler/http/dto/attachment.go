@@ -0,0 +1,34 @@
+package dto
+
+import (
+	"personal-finance/core/domain"
+	"time"
+)
+
+type AttachmentIdRequest struct {
+	ID           string `uri:"id" binding:"required"`
+	AttachmentId string `uri:"attachment_id" binding:"required"`
+}
+
+type AttachmentResponse struct {
+	ID            string    `json:"_id"`
+	TransactionId string    `json:"transaction_id"`
+	FileName      string    `json:"file_name"`
+	ContentType   string    `json:"content_type"`
+	Size          int64     `json:"size"`
+	SecureUrl     string    `json:"secure_url"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+func NewAttachmentResponse(attachment *domain.Attachment) AttachmentResponse {
+
+	return AttachmentResponse{
+		ID:            attachment.ID,
+		TransactionId: attachment.TransactionId,
+		FileName:      attachment.FileName,
+		ContentType:   attachment.ContentType,
+		Size:          attachment.Size,
+		SecureUrl:     attachment.SecureUrl,
+		CreatedAt:     attachment.CreatedAt,
+	}
+}
@@ -0,0 +1,12 @@
+package dto
+
+type HistoryUriRequest struct {
+	Entity string `uri:"entity" binding:"required,oneof=transaction origin user"`
+	ID     string `uri:"id" binding:"required"`
+}
+
+type HistoryRequest struct {
+	UserId string `form:"user_id" binding:"required"`
+	Page   uint64 `form:"page,default=1" binding:"min=1"`
+	Limit  uint64 `form:"limit,default=20" binding:"min=1,max=100"`
+}
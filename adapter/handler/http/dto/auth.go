@@ -6,15 +6,18 @@ import (
 )
 
 type User struct {
-	ID           string    `json:"_id"`
-	Username     string    `json:"username"`
-	Email        string    `json:"email"`
-	Password     string    `json:"-"`
-	Role         string    `json:"role"`
-	ProfileImage string    `json:"profile_image,omitempty"`
-	PublicId     string    `json:"public_id,omitempty"`
-	CreatedAt    time.Time `json:"created_at" bson:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at" bson:"updated_at"`
+	ID                  string    `json:"_id"`
+	Username            string    `json:"username"`
+	Email               string    `json:"email"`
+	Password            string    `json:"-"`
+	Role                string    `json:"role"`
+	ProfileImage        string    `json:"profile_image,omitempty"`
+	PublicId            string    `json:"public_id,omitempty"`
+	MonthlyReportOptOut bool      `json:"monthly_report_opt_out"`
+	Locale              string    `json:"locale,omitempty"`
+	Currency            string    `json:"currency,omitempty"`
+	CreatedAt           time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at" bson:"updated_at"`
 }
 
 type LoginRequest struct {
@@ -32,6 +35,13 @@ type UserRequest struct {
 	ID string `form:"id" binding:"required"`
 }
 
+// PreferencesRequest changes only the preferences that are present.
+type PreferencesRequest struct {
+	MonthlyReportOptOut *bool   `json:"monthly_report_opt_out"`
+	Locale              *string `json:"locale"`
+	Currency            *string `json:"currency"`
+}
+
 type TokenResponse struct {
 	Token string `json:"token"`
 	User  User   `json:"user"`
@@ -40,13 +50,16 @@ type TokenResponse struct {
 func NewUserResponse(user *domain.User) User {
 
 	return User{
-		ID:           user.ID,
-		Username:     user.Username,
-		Email:        user.Email,
-		Role:         user.Role,
-		ProfileImage: user.ProfileImage,
-		PublicId:     user.PublicIdImage,
-		CreatedAt:    user.CreatedAt,
-		UpdatedAt:    user.UpdatedAt,
+		ID:                  user.ID,
+		Username:            user.Username,
+		Email:               user.Email,
+		Role:                user.Role,
+		ProfileImage:        user.ProfileImage,
+		PublicId:            user.PublicIdImage,
+		MonthlyReportOptOut: user.MonthlyReportOptOut,
+		Locale:              user.Locale,
+		Currency:            user.Currency,
+		CreatedAt:           user.CreatedAt,
+		UpdatedAt:           user.UpdatedAt,
 	}
 }
@@ -0,0 +1,60 @@
+package dto
+
+import (
+	"personal-finance/core/domain"
+	"time"
+)
+
+type DebtRequest struct {
+	UserId       string    `json:"user_id" binding:"required"`
+	OriginId     string    `json:"origin_id" binding:"required"`
+	Name         string    `json:"name" binding:"required"`
+	Principal    float64   `json:"principal" binding:"required,gt=0"`
+	InterestRate float64   `json:"interest_rate" binding:"gte=0"`
+	TermMonths   int       `json:"term_months" binding:"required,min=1,max=1200"`
+	StartDate    time.Time `json:"start_date" binding:"required"`
+}
+
+// DebtPaymentRequest records a payment; FromOriginId is the origin the money
+// left, when it should be booked there too.
+type DebtPaymentRequest struct {
+	Amount       float64   `json:"amount" binding:"required,gt=0"`
+	Date         time.Time `json:"date"`
+	FromOriginId string    `json:"from_origin_id,omitempty"`
+}
+
+type DebtSimulationRequest struct {
+	ExtraMonthly float64 `form:"extra_monthly" binding:"gte=0"`
+	LumpSum      float64 `form:"lump_sum" binding:"gte=0"`
+}
+
+type DebtResponse struct {
+	ID             string    `json:"_id"`
+	UserId         string    `json:"user_id"`
+	OriginId       string    `json:"origin_id"`
+	Name           string    `json:"name"`
+	Principal      float64   `json:"principal"`
+	InterestRate   float64   `json:"interest_rate"`
+	TermMonths     int       `json:"term_months"`
+	StartDate      time.Time `json:"start_date"`
+	MonthlyPayment float64   `json:"monthly_payment"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at,omitempty"`
+}
+
+func NewDebtResponse(debt *domain.Debt) DebtResponse {
+
+	return DebtResponse{
+		ID:             debt.ID,
+		UserId:         debt.UserId,
+		OriginId:       debt.OriginId,
+		Name:           debt.Name,
+		Principal:      debt.Principal,
+		InterestRate:   debt.InterestRate,
+		TermMonths:     debt.TermMonths,
+		StartDate:      debt.StartDate,
+		MonthlyPayment: debt.MonthlyPayment(),
+		CreatedAt:      debt.CreatedAt,
+		UpdatedAt:      debt.UpdatedAt,
+	}
+}
@@ -0,0 +1,62 @@
+package dto
+
+import (
+	"personal-finance/core/domain"
+	"time"
+)
+
+// ForecastRequest projects the next Months (3 by default) and flags the days
+// a balance falls below Threshold.
+type ForecastRequest struct {
+	UserId    string  `form:"user_id" binding:"required"`
+	Months    int     `form:"months" binding:"omitempty,min=1,max=24"`
+	Threshold float64 `form:"threshold"`
+}
+
+type ExpectedItemRequest struct {
+	UserId           string     `json:"user_id" binding:"required"`
+	OriginId         string     `json:"origin_id" binding:"required"`
+	Type             string     `json:"type" binding:"required,oneof=Income Output"`
+	Amount           float64    `json:"amount" binding:"required,gt=0"`
+	Subject          string     `json:"subject" binding:"required"`
+	PersonOrBusiness string     `json:"person_business,omitempty"`
+	Description      string     `json:"description,omitempty"`
+	Frequency        string     `json:"frequency" binding:"required,oneof=once weekly biweekly monthly"`
+	StartDate        time.Time  `json:"start_date" binding:"required"`
+	EndDate          *time.Time `json:"end_date,omitempty"`
+}
+
+type ExpectedItemResponse struct {
+	ID               string     `json:"_id"`
+	UserId           string     `json:"user_id"`
+	OriginId         string     `json:"origin_id"`
+	Type             string     `json:"type"`
+	Amount           float64    `json:"amount"`
+	Subject          string     `json:"subject"`
+	PersonOrBusiness string     `json:"person_business,omitempty"`
+	Description      string     `json:"description,omitempty"`
+	Frequency        string     `json:"frequency"`
+	StartDate        time.Time  `json:"start_date"`
+	EndDate          *time.Time `json:"end_date,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at,omitempty"`
+}
+
+func NewExpectedItemResponse(item *domain.ExpectedItem) ExpectedItemResponse {
+
+	return ExpectedItemResponse{
+		ID:               item.ID,
+		UserId:           item.UserId,
+		OriginId:         item.OriginId,
+		Type:             item.Type,
+		Amount:           item.Amount,
+		Subject:          item.Subject,
+		PersonOrBusiness: item.PersonOrBusiness,
+		Description:      item.Description,
+		Frequency:        item.Frequency,
+		StartDate:        item.StartDate,
+		EndDate:          item.EndDate,
+		CreatedAt:        item.CreatedAt,
+		UpdatedAt:        item.UpdatedAt,
+	}
+}
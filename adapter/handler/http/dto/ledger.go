@@ -0,0 +1,18 @@
+package dto
+
+// LedgerEntriesRequest pages through the user's journal, optionally only the
+// entries posting to Account (e.g. "origin:<id>" or "expense:Food").
+type LedgerEntriesRequest struct {
+	UserId  string `form:"user_id" binding:"required"`
+	Account string `form:"account"`
+	Page    uint64 `form:"page,default=1" binding:"min=1"`
+	Limit   uint64 `form:"limit,default=20" binding:"min=1,max=100"`
+}
+
+type LedgerMigrationRequest struct {
+	UserId string `json:"user_id" binding:"required"`
+}
+
+type LedgerMigrationResponse struct {
+	Appended int `json:"appended"`
+}
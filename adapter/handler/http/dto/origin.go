@@ -9,34 +9,88 @@ type RequestByUserId struct {
 	UserId string `form:"user_id" binding:"required"`
 }
 
+// OriginsRequest lists the user's origins, the archived ones only if
+// IncludeArchived.
+type OriginsRequest struct {
+	UserId          string `form:"user_id" binding:"required"`
+	IncludeArchived bool   `form:"include_archived"`
+}
+
+// DeleteOriginRequest tells what to do with the transactions of the deleted
+// origin: refuse while there are any, trashed ones included (the default),
+// move them to TargetId or archive the origin instead.
+type DeleteOriginRequest struct {
+	Mode     string `form:"mode,default=refuse" binding:"oneof=refuse reassign archive"`
+	TargetId string `form:"target_id" binding:"required_if=Mode reassign"`
+}
+
+// OriginRequest describes an origin. Kind defaults to checking on creation
+// and to the stored kind on update; the credit limit and statement day only
+// apply to credit cards and the interest rate (yearly, in percent) to
+// savings, credit cards, loans and investments.
 type OriginRequest struct {
-	UserId      string    `json:"user_id" binding:"required"`
-	Name        string    `json:"name" binding:"required"`
-	Total       float64   `json:"total" binding:"required"`
-	Description string    `json:"description,omitempty"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at,omitempty"`
+	UserId       string    `json:"user_id" binding:"required"`
+	Name         string    `json:"name" binding:"required"`
+	Kind         string    `json:"kind,omitempty" binding:"omitempty,oneof=cash checking savings credit_card loan investment"`
+	Total        float64   `json:"total" binding:"required"`
+	CreditLimit  *float64  `json:"credit_limit,omitempty" binding:"omitempty,gte=0"`
+	StatementDay int       `json:"statement_day,omitempty" binding:"omitempty,min=1,max=31"`
+	InterestRate *float64  `json:"interest_rate,omitempty" binding:"omitempty,gte=0"`
+	Description  string    `json:"description,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at,omitempty"`
+
+	PaymentDueDays     int      `json:"payment_due_days,omitempty" binding:"omitempty,min=1,max=60"`
+	MinimumPaymentRate *float64 `json:"minimum_payment_rate,omitempty" binding:"omitempty,gte=0,lte=100"`
 }
 
 type OriginResponse struct {
-	ID          string    `json:"_id" binding:"required"`
-	UserId      string    `json:"user_id" binding:"required"`
-	Name        string    `json:"name" binding:"required"`
-	Total       float64   `json:"total" binding:"required"`
-	Description string    `json:"description,omitempty"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at,omitempty"`
+	ID           string    `json:"_id" binding:"required"`
+	UserId       string    `json:"user_id" binding:"required"`
+	Name         string    `json:"name" binding:"required"`
+	Kind         string    `json:"kind"`
+	Liability    bool      `json:"liability"`
+	Total        float64   `json:"total" binding:"required"`
+	CreditLimit  *float64  `json:"credit_limit,omitempty"`
+	StatementDay int       `json:"statement_day,omitempty"`
+	InterestRate *float64  `json:"interest_rate,omitempty"`
+	Description  string    `json:"description,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at,omitempty"`
+
+	PaymentDueDays     int      `json:"payment_due_days,omitempty"`
+	MinimumPaymentRate *float64 `json:"minimum_payment_rate,omitempty"`
+
+	ReconciledThrough *time.Time `json:"reconciled_through,omitempty"`
+	ReconciledBalance float64    `json:"reconciled_balance,omitempty"`
+	OpeningBalance    *float64   `json:"opening_balance,omitempty"`
+	DeletedAt         *time.Time `json:"deleted_at,omitempty"`
+	ArchivedAt        *time.Time `json:"archived_at,omitempty"`
 }
 
 func NewOriginResponse(origin *domain.Origin) OriginResponse {
 
 	return OriginResponse{
-		ID:          origin.ID,
-		UserId:      origin.UserId,
-		Name:        origin.Name,
-		Total:       origin.Total,
-		Description: origin.Description,
-		CreatedAt:   origin.CreatedAt,
-		UpdatedAt:   origin.UpdatedAt,
+		ID:           origin.ID,
+		UserId:       origin.UserId,
+		Name:         origin.Name,
+		Kind:         origin.OriginKind(),
+		Liability:    origin.IsLiability(),
+		Total:        origin.Total,
+		CreditLimit:  origin.CreditLimit,
+		StatementDay: origin.StatementDay,
+		InterestRate: origin.InterestRate,
+		Description:  origin.Description,
+		CreatedAt:    origin.CreatedAt,
+		UpdatedAt:    origin.UpdatedAt,
+
+		PaymentDueDays:     origin.PaymentDueDays,
+		MinimumPaymentRate: origin.MinimumPaymentRate,
+
+		ReconciledThrough: origin.ReconciledThrough,
+		ReconciledBalance: origin.ReconciledBalance,
+		OpeningBalance:    origin.OpeningBalance,
+		DeletedAt:         origin.DeletedAt,
+		ArchivedAt:        origin.ArchivedAt,
 	}
 }
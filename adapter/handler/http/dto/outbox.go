@@ -0,0 +1,7 @@
+package dto
+
+type OutboxRequest struct {
+	Status string `form:"status" binding:"omitempty,oneof=pending sent dead"`
+	Page   uint64 `form:"page,default=1" binding:"min=1"`
+	Limit  uint64 `form:"limit,default=20" binding:"min=1,max=100"`
+}
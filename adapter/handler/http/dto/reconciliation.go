@@ -0,0 +1,15 @@
+package dto
+
+import "time"
+
+// ReconciliationRequest starts a reconciliation against the statement ending
+// on StatementDate with StatementBalance.
+type ReconciliationRequest struct {
+	StatementDate    time.Time `json:"statement_date" binding:"required"`
+	StatementBalance float64   `json:"statement_balance"`
+}
+
+type ClearedRequest struct {
+	TransactionIds []string `json:"transaction_ids" binding:"required,min=1"`
+	Cleared        bool     `json:"cleared"`
+}
@@ -0,0 +1,25 @@
+package dto
+
+import "time"
+
+// ReportRequest selects the report period: a month (year, month), a quarter
+// (year, quarter), a whole year (year) or a custom range of days (from, to).
+// Archived origins are left out of the totals and the origin summary unless
+// IncludeArchived.
+type ReportRequest struct {
+	UserId  string     `form:"user_id" binding:"required"`
+	Period  string     `form:"period" binding:"required,oneof=month quarter year custom"`
+	Year    int        `form:"year"`
+	Month   int        `form:"month" binding:"min=0,max=12"`
+	Quarter int        `form:"quarter" binding:"min=0,max=4"`
+	From    *time.Time `form:"from" time_format:"2006-01-02"`
+	To      *time.Time `form:"to" time_format:"2006-01-02"`
+
+	IncludeArchived bool `form:"include_archived"`
+}
+
+// DeliveryStatusRequest selects the delivered month ("2006-01"); the previous
+// month when empty.
+type DeliveryStatusRequest struct {
+	Period string `form:"period"`
+}
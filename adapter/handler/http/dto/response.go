@@ -10,18 +10,24 @@ import (
 )
 
 type TransactionResponse struct {
-	ID               any             `json:"_id"`
-	UserId           string          `json:"user_id"`
-	Amount           float64         `json:"amount"`
-	Type             string          `json:"type"`
-	Subject          string          `json:"subject"`
-	OutputCategory   string          `json:"output_category"`
-	PersonOrBusiness string          `json:"person_business"`
-	Description      string          `json:"description"`
-	CreatedAtString  string          `json:"created"`
-	CreatedAt        time.Time       `json:"created_at"`
-	UpdatedAt        time.Time       `json:"updated_at,omitempty"`
-	Origin           *OriginResponse `json:"origin"`
+	ID               any                 `json:"_id"`
+	UserId           string              `json:"user_id"`
+	Amount           float64             `json:"amount"`
+	Type             string              `json:"type"`
+	Subject          string              `json:"subject"`
+	OutputCategory   string              `json:"output_category"`
+	IncomeSource     string              `json:"income_source,omitempty"`
+	PersonOrBusiness string              `json:"person_business"`
+	Description      string              `json:"description"`
+	Tags             []string            `json:"tags"`
+	DebtPayment      *domain.DebtPayment `json:"debt_payment,omitempty"`
+	Cleared          bool                `json:"cleared"`
+	Reconciled       bool                `json:"reconciled"`
+	CreatedAtString  string              `json:"created"`
+	CreatedAt        time.Time           `json:"created_at"`
+	UpdatedAt        time.Time           `json:"updated_at,omitempty"`
+	DeletedAt        *time.Time          `json:"deleted_at,omitempty"`
+	Origin           *OriginResponse     `json:"origin"`
 }
 
 type response struct {
@@ -38,6 +44,13 @@ type PaginatedResponse struct {
 	Data           any    `json:"data"`
 }
 
+type CursorPaginatedResponse struct {
+	Limit          uint64 `json:"limit"`
+	TotalDocuments int64  `json:"totalDocuments"`
+	NextCursor     string `json:"nextCursor"`
+	Data           any    `json:"data"`
+}
+
 type ErrorResponse struct {
 	Success bool   `json:"success"`
 	Message string `json:"message"`
@@ -58,6 +71,31 @@ var errorStatusMap = map[error]int{
 	domain.ErrForbidden:                  http.StatusForbidden,
 	domain.ErrUserAlreadyExists:          http.StatusBadRequest,
 	domain.ErrNoUpdatedData:              http.StatusBadRequest,
+	domain.ErrGettingFile:                http.StatusBadRequest,
+	domain.ErrFileSize:                   http.StatusBadRequest,
+	domain.ErrFileType:                   http.StatusBadRequest,
+	domain.ErrAttachmentSize:             http.StatusBadRequest,
+	domain.ErrInvalidCursor:              http.StatusBadRequest,
+	domain.ErrCursorSort:                 http.StatusBadRequest,
+	domain.ErrEmptySearch:                http.StatusBadRequest,
+	domain.ErrInvalidPeriod:              http.StatusBadRequest,
+	domain.ErrMessageAlreadySent:         http.StatusConflict,
+	domain.ErrInvalidLocale:              http.StatusBadRequest,
+	domain.ErrInvalidCurrency:            http.StatusBadRequest,
+	domain.ErrInvalidOriginKind:          http.StatusBadRequest,
+	domain.ErrInvalidOriginFields:        http.StatusBadRequest,
+	domain.ErrNoStatementCycle:           http.StatusBadRequest,
+	domain.ErrInvalidDebtOrigin:          http.StatusBadRequest,
+	domain.ErrDebtPaidOff:                http.StatusConflict,
+	domain.ErrPaymentBelowInterest:       http.StatusBadRequest,
+	domain.ErrReconciledPeriod:           http.StatusConflict,
+	domain.ErrReconciliationClosed:       http.StatusConflict,
+	domain.ErrOriginInUse:                http.StatusConflict,
+	domain.ErrOriginHasDebt:              http.StatusConflict,
+	domain.ErrInvalidOriginDeletion:      http.StatusBadRequest,
+	domain.ErrArchivedOrigin:             http.StatusConflict,
+	domain.ErrOriginKindChange:           http.StatusConflict,
+	domain.ErrDebtPaymentEdit:            http.StatusConflict,
 }
 
 func NewTransactionResponse(transaction *domain.Transaction) TransactionResponse {
@@ -69,11 +107,17 @@ func NewTransactionResponse(transaction *domain.Transaction) TransactionResponse
 		Type:             transaction.Type,
 		Subject:          transaction.Subject,
 		OutputCategory:   transaction.OutputCategory,
+		IncomeSource:     transaction.IncomeSource,
 		PersonOrBusiness: transaction.PersonOrBusiness,
 		Description:      transaction.Description,
+		Tags:             transaction.Tags,
+		DebtPayment:      transaction.DebtPayment,
+		Cleared:          transaction.Cleared,
+		Reconciled:       transaction.Reconciled,
 		CreatedAtString:  transaction.CreatedAtString,
 		CreatedAt:        transaction.CreatedAt,
 		UpdatedAt:        transaction.UpdatedAt,
+		DeletedAt:        transaction.DeletedAt,
 	}
 
 	if transaction.Origin != nil {
@@ -87,6 +131,10 @@ func NewTransactionResponse(transaction *domain.Transaction) TransactionResponse
 		}
 	}
 
+	if response.Tags == nil {
+		response.Tags = []string{}
+	}
+
 	return response
 }
 
@@ -107,6 +155,21 @@ func NewPaginatedResponse(
 	}
 }
 
+func NewCursorPaginatedResponse(
+	limit uint64,
+	totalDocuments int64,
+	nextCursor string,
+	transactionList any,
+) CursorPaginatedResponse {
+
+	return CursorPaginatedResponse{
+		Limit:          limit,
+		TotalDocuments: totalDocuments,
+		NextCursor:     nextCursor,
+		Data:           transactionList,
+	}
+}
+
 func ValidationError(ctx *gin.Context, err error) {
 
 	errorResponse := newErrorResponse(err.Error())
@@ -0,0 +1,18 @@
+package dto
+
+import "time"
+
+// NetWorthRequest selects the days from through to, both included ("2006-01-02").
+type NetWorthRequest struct {
+	UserId string    `form:"user_id" binding:"required"`
+	From   time.Time `form:"from" time_format:"2006-01-02" binding:"required"`
+	To     time.Time `form:"to" time_format:"2006-01-02" binding:"required"`
+}
+
+type BackfillRequest struct {
+	UserId string `json:"user_id" binding:"required"`
+}
+
+type BackfillResponse struct {
+	Inserted int `json:"inserted"`
+}
@@ -0,0 +1,8 @@
+package dto
+
+// StatementRequest lists the open cycle of a credit card origin and its last
+// Cycles closed ones (3 by default).
+type StatementRequest struct {
+	ID     string `uri:"id" binding:"required"`
+	Cycles int    `form:"cycles" binding:"omitempty,min=1,max=24"`
+}
@@ -0,0 +1,36 @@
+package dto
+
+import (
+	"personal-finance/core/domain"
+	"time"
+)
+
+type TagRequest struct {
+	UserId      string `json:"user_id" binding:"required"`
+	Name        string `json:"name" binding:"required"`
+	Color       string `json:"color,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+type TagResponse struct {
+	ID          string    `json:"_id"`
+	UserId      string    `json:"user_id"`
+	Name        string    `json:"name"`
+	Color       string    `json:"color,omitempty"`
+	Description string    `json:"description,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at,omitempty"`
+}
+
+func NewTagResponse(tag *domain.Tag) TagResponse {
+
+	return TagResponse{
+		ID:          tag.ID,
+		UserId:      tag.UserId,
+		Name:        tag.Name,
+		Color:       tag.Color,
+		Description: tag.Description,
+		CreatedAt:   tag.CreatedAt,
+		UpdatedAt:   tag.UpdatedAt,
+	}
+}
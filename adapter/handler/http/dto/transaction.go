@@ -1,13 +1,26 @@
 package dto
 
-import "time"
+import (
+	"personal-finance/core/domain"
+	"time"
+)
 
+// TransactionByUserRequest selects page-number pagination when page is set
+// and cursor pagination (starting at cursor, if any) when it is not.
 type TransactionByUserRequest struct {
 	UserId string `form:"user_id" binding:"required"`
-	Page   uint64 `form:"page" binding:"required"`
+	Page   uint64 `form:"page"`
+	Cursor string `form:"cursor"`
 	Limit  uint64 `form:"limit" binding:"required"`
 }
 
+// TrashRequest pages through the user's transactions in the trash.
+type TrashRequest struct {
+	UserId string `form:"user_id" binding:"required"`
+	Page   uint64 `form:"page,default=1" binding:"min=1"`
+	Limit  uint64 `form:"limit,default=20" binding:"min=1,max=100"`
+}
+
 type DateFilterRequest struct {
 	*TransactionByUserRequest
 	Month int `form:"month" binding:"min=0,max=12"`
@@ -19,6 +32,41 @@ type TypeFilterRequest struct {
 	Type string `form:"type" binding:"required"`
 }
 
+type TagFilterRequest struct {
+	*TransactionByUserRequest
+	Tags  []string `form:"tags" binding:"required"`
+	Match string   `form:"match" binding:"omitempty,oneof=any all"`
+}
+
+type SearchRequest struct {
+	*TransactionByUserRequest
+	From      *time.Time `form:"from" time_format:"2006-01-02"`
+	To        *time.Time `form:"to" time_format:"2006-01-02"`
+	Type      string     `form:"type"`
+	OriginIds []string   `form:"origin_id"`
+	Category  string     `form:"category"`
+	Payee     string     `form:"payee"`
+	MinAmount *float64   `form:"min_amount" binding:"omitempty,gte=0"`
+	MaxAmount *float64   `form:"max_amount" binding:"omitempty,gte=0"`
+	Text      string     `form:"q"`
+	Tags      []string   `form:"tags"`
+	Sort      string     `form:"sort" binding:"omitempty,oneof=date amount payee category type"`
+	Order     string     `form:"order" binding:"omitempty,oneof=asc desc"`
+}
+
+type TextSearchRequest struct {
+	UserId string `form:"user_id" binding:"required"`
+	Query  string `form:"q" binding:"required"`
+	Page   uint64 `form:"page,default=1" binding:"min=1"`
+	Limit  uint64 `form:"limit" binding:"required"`
+}
+
+type TextSearchResponse struct {
+	Transaction TransactionResponse    `json:"transaction"`
+	Score       float64                `json:"score"`
+	Highlights  []domain.TextHighlight `json:"highlights"`
+}
+
 type IdRequest struct {
 	ID string `uri:"id" binding:"required"`
 }
@@ -30,8 +78,10 @@ type TransactionRequest struct {
 	Type             string    `json:"type" validate:"required"`
 	Subject          string    `json:"subject" validate:"required"`
 	OutputCategory   string    `json:"output_category" bson:"output_category"`
+	IncomeSource     string    `json:"income_source"`
 	PersonOrBusiness string    `json:"person_business" bson:"person_business" validate:"required"`
 	Description      string    `json:"description" validate:"required"`
+	Tags             []string  `json:"tags"`
 	CreatedAtString  string    `json:"created" bson:"created" validate:"required"`
 	CreatedAt        time.Time `json:"created_at" bson:"created_at"`
 	UpdatedAt        time.Time `json:"updated_at" bson:"updated_at"`
@@ -0,0 +1,45 @@
+package http
+
+import (
+	"net/http"
+	"path"
+	"personal-finance/adapter/handler/http/dto"
+	"personal-finance/core/domain"
+	"personal-finance/core/port"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FileHandler serves files of storage backends that have no public endpoint.
+// reader is nil when the configured backend serves its own URLs.
+type FileHandler struct {
+	reader port.SignedFileReader
+}
+
+func NewFileHandler(reader port.SignedFileReader) *FileHandler {
+	return &FileHandler{
+		reader,
+	}
+}
+
+func (fh *FileHandler) ServeFile(ctx *gin.Context) {
+
+	if fh.reader == nil {
+		dto.HandleError(ctx, domain.ErrDataNotFound)
+		return
+	}
+
+	publicId := strings.TrimPrefix(ctx.Param("path"), "/")
+
+	file, err := fh.reader.OpenSignedFile(ctx, publicId, ctx.Query("expires"), ctx.Query("signature"))
+	if err != nil {
+		dto.HandleError(ctx, err)
+		return
+	}
+	defer file.Close()
+
+	ctx.Header("Cache-Control", "private, max-age=86400")
+	http.ServeContent(ctx.Writer, ctx.Request, path.Base(publicId), time.Time{}, file)
+}
@@ -0,0 +1,168 @@
+package http
+
+import (
+	"personal-finance/adapter/handler/http/dto"
+	"personal-finance/core/domain"
+	"personal-finance/core/port"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const defaultForecastMonths = 3
+
+type ForecastHandler struct {
+	service port.ForecastService
+}
+
+func NewForecastHandler(service port.ForecastService) *ForecastHandler {
+	return &ForecastHandler{
+		service,
+	}
+}
+
+func (fh *ForecastHandler) GetForecast(ctx *gin.Context) {
+
+	var request dto.ForecastRequest
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		dto.ValidationError(ctx, err)
+		return
+	}
+
+	if request.Months == 0 {
+		request.Months = defaultForecastMonths
+	}
+
+	forecast, err := fh.service.GetForecast(ctx, request.UserId, request.Months, request.Threshold, time.Now())
+	if err != nil {
+		dto.HandleError(ctx, err)
+		return
+	}
+
+	dto.HandleSuccess(ctx, forecast)
+}
+
+func (fh *ForecastHandler) GetExpectedItemsByUserId(ctx *gin.Context) {
+
+	var req dto.RequestByUserId
+	var itemList []dto.ExpectedItemResponse
+
+	if err := ctx.Bind(&req); err != nil {
+		dto.ValidationError(ctx, err)
+		return
+	}
+
+	items, err := fh.service.GetExpectedItemsByUserId(ctx, req.UserId)
+	if err != nil {
+		dto.HandleError(ctx, err)
+		return
+	}
+
+	for _, item := range items {
+		itemList = append(itemList, dto.NewExpectedItemResponse(&item))
+	}
+
+	if itemList == nil {
+		itemList = []dto.ExpectedItemResponse{}
+	}
+
+	dto.HandleSuccess(ctx, itemList)
+}
+
+func (fh *ForecastHandler) GetExpectedItemById(ctx *gin.Context) {
+
+	var request dto.IdRequest
+	if err := ctx.ShouldBindUri(&request); err != nil {
+		dto.ValidationError(ctx, err)
+		return
+	}
+
+	item, err := fh.service.GetExpectedItemById(ctx, request.ID)
+	if err != nil {
+		dto.HandleError(ctx, err)
+		return
+	}
+
+	response := dto.NewExpectedItemResponse(item)
+
+	dto.HandleSuccess(ctx, response)
+}
+
+func (fh *ForecastHandler) CreateExpectedItem(ctx *gin.Context) {
+
+	var req dto.ExpectedItemRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		dto.ValidationError(ctx, err)
+		return
+	}
+
+	item := newExpectedItem(req)
+	item.CreatedAt = time.Now()
+
+	_, err := fh.service.CreateExpectedItem(ctx, &item)
+	if err != nil {
+		dto.HandleError(ctx, err)
+		return
+	}
+
+	response := dto.NewExpectedItemResponse(&item)
+
+	dto.HandleSuccess(ctx, response)
+}
+
+func (fh *ForecastHandler) UpdateExpectedItem(ctx *gin.Context) {
+
+	var req dto.ExpectedItemRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		dto.ValidationError(ctx, err)
+		return
+	}
+
+	id := ctx.Param("id")
+
+	item := newExpectedItem(req)
+
+	_, err := fh.service.UpdateExpectedItem(ctx, id, &item)
+	if err != nil {
+		dto.HandleError(ctx, err)
+		return
+	}
+
+	response := dto.NewExpectedItemResponse(&item)
+
+	dto.HandleSuccess(ctx, response)
+}
+
+func (fh *ForecastHandler) DeleteExpectedItem(ctx *gin.Context) {
+
+	var request dto.IdRequest
+	if err := ctx.ShouldBindUri(&request); err != nil {
+		dto.ValidationError(ctx, err)
+		return
+	}
+
+	err := fh.service.DeleteExpectedItem(ctx, request.ID)
+	if err != nil {
+		dto.HandleError(ctx, err)
+		return
+	}
+
+	dto.HandleSuccess(ctx, nil)
+}
+
+func newExpectedItem(req dto.ExpectedItemRequest) domain.ExpectedItem {
+
+	return domain.ExpectedItem{
+		UserId:           req.UserId,
+		OriginId:         req.OriginId,
+		Type:             req.Type,
+		Amount:           req.Amount,
+		Subject:          req.Subject,
+		PersonOrBusiness: req.PersonOrBusiness,
+		Description:      req.Description,
+		Frequency:        req.Frequency,
+		StartDate:        req.StartDate,
+		EndDate:          req.EndDate,
+		UpdatedAt:        time.Now(),
+	}
+}
@@ -0,0 +1,82 @@
+package http
+
+import (
+	"personal-finance/adapter/handler/http/dto"
+	"personal-finance/core/domain"
+	"personal-finance/core/port"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LedgerHandler struct {
+	service port.LedgerService
+}
+
+func NewLedgerHandler(service port.LedgerService) *LedgerHandler {
+	return &LedgerHandler{
+		service,
+	}
+}
+
+func (lh *LedgerHandler) GetEntries(ctx *gin.Context) {
+
+	var req dto.LedgerEntriesRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		dto.ValidationError(ctx, err)
+		return
+	}
+
+	entries, totalDocuments, totalPages, err := lh.service.GetEntries(ctx, req.UserId, req.Account, req.Page, req.Limit)
+	if err != nil {
+		dto.HandleError(ctx, err)
+		return
+	}
+
+	if entries == nil {
+		entries = []domain.JournalEntry{}
+	}
+
+	response := dto.NewPaginatedResponse(
+		req.Page,
+		req.Limit,
+		totalDocuments,
+		totalPages,
+		entries,
+	)
+
+	dto.HandleSuccess(ctx, response)
+}
+
+func (lh *LedgerHandler) GetTrialBalance(ctx *gin.Context) {
+
+	var req dto.RequestByUserId
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		dto.ValidationError(ctx, err)
+		return
+	}
+
+	balance, err := lh.service.GetTrialBalance(ctx, req.UserId)
+	if err != nil {
+		dto.HandleError(ctx, err)
+		return
+	}
+
+	dto.HandleSuccess(ctx, balance)
+}
+
+func (lh *LedgerHandler) MigrateUser(ctx *gin.Context) {
+
+	var request dto.LedgerMigrationRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		dto.ValidationError(ctx, err)
+		return
+	}
+
+	appended, err := lh.service.MigrateUser(ctx, request.UserId)
+	if err != nil {
+		dto.HandleError(ctx, err)
+		return
+	}
+
+	dto.HandleSuccess(ctx, dto.LedgerMigrationResponse{Appended: appended})
+}
@@ -24,7 +24,7 @@ func NewOriginHandler(service port.OriginService, validate *validator.Validate)
 
 func (oh *OriginHandler) GetOriginsByUserId(ctx *gin.Context) {
 
-	var req dto.RequestByUserId
+	var req dto.OriginsRequest
 	var originList []dto.OriginResponse
 
 	if err := ctx.Bind(&req); err != nil {
@@ -32,7 +32,7 @@ func (oh *OriginHandler) GetOriginsByUserId(ctx *gin.Context) {
 		return
 	}
 
-	origins, err := oh.service.GetOriginsByUserId(ctx, req.UserId)
+	origins, err := oh.service.GetOriginsByUserId(ctx, req.UserId, req.IncludeArchived)
 	if err != nil {
 		dto.HandleError(ctx, err)
 		return
@@ -82,11 +82,19 @@ func (oh *OriginHandler) CreateOrigin(ctx *gin.Context) {
 	}
 
 	origin := domain.Origin{
-		UserId:    req.UserId,
-		Name:      req.Name,
-		Total:     req.Total,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+		UserId:       req.UserId,
+		Name:         req.Name,
+		Kind:         req.Kind,
+		Total:        req.Total,
+		CreditLimit:  req.CreditLimit,
+		StatementDay: req.StatementDay,
+		InterestRate: req.InterestRate,
+		Description:  req.Description,
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+
+		PaymentDueDays:     req.PaymentDueDays,
+		MinimumPaymentRate: req.MinimumPaymentRate,
 	}
 
 	_, err := oh.service.CreateOrigin(ctx, &origin)
@@ -116,12 +124,19 @@ func (oh *OriginHandler) UpdateOrigin(ctx *gin.Context) {
 	id := ctx.Param("id")
 
 	origin := domain.Origin{
-		UserId:      req.UserId,
-		Name:        req.Name,
-		Total:       req.Total,
-		Description: req.Description,
-		CreatedAt:   req.CreatedAt,
-		UpdatedAt:   time.Now(),
+		UserId:       req.UserId,
+		Name:         req.Name,
+		Kind:         req.Kind,
+		Total:        req.Total,
+		CreditLimit:  req.CreditLimit,
+		StatementDay: req.StatementDay,
+		InterestRate: req.InterestRate,
+		Description:  req.Description,
+		CreatedAt:    req.CreatedAt,
+		UpdatedAt:    time.Now(),
+
+		PaymentDueDays:     req.PaymentDueDays,
+		MinimumPaymentRate: req.MinimumPaymentRate,
 	}
 
 	_, err := oh.service.UpdateOrigin(ctx, id, &origin)
@@ -143,7 +158,18 @@ func (oh *OriginHandler) DeleteOrigin(ctx *gin.Context) {
 		return
 	}
 
-	err := oh.service.DeleteOrigin(ctx, request.ID)
+	var req dto.DeleteOriginRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		dto.ValidationError(ctx, err)
+		return
+	}
+
+	deletion := domain.OriginDeletion{
+		Mode:     req.Mode,
+		TargetId: req.TargetId,
+	}
+
+	err := oh.service.DeleteOrigin(ctx, request.ID, deletion)
 	if err != nil {
 		dto.HandleError(ctx, err)
 		return
@@ -151,3 +177,87 @@ func (oh *OriginHandler) DeleteOrigin(ctx *gin.Context) {
 
 	dto.HandleSuccess(ctx, nil)
 }
+
+func (oh *OriginHandler) ArchiveOrigin(ctx *gin.Context) {
+
+	var request dto.IdRequest
+	if err := ctx.ShouldBindUri(&request); err != nil {
+		dto.ValidationError(ctx, err)
+		return
+	}
+
+	origin, err := oh.service.ArchiveOrigin(ctx, request.ID)
+	if err != nil {
+		dto.HandleError(ctx, err)
+		return
+	}
+
+	response := dto.NewOriginResponse(origin)
+
+	dto.HandleSuccess(ctx, response)
+}
+
+func (oh *OriginHandler) UnarchiveOrigin(ctx *gin.Context) {
+
+	var request dto.IdRequest
+	if err := ctx.ShouldBindUri(&request); err != nil {
+		dto.ValidationError(ctx, err)
+		return
+	}
+
+	origin, err := oh.service.UnarchiveOrigin(ctx, request.ID)
+	if err != nil {
+		dto.HandleError(ctx, err)
+		return
+	}
+
+	response := dto.NewOriginResponse(origin)
+
+	dto.HandleSuccess(ctx, response)
+}
+
+func (oh *OriginHandler) GetDeletedOrigins(ctx *gin.Context) {
+
+	var req dto.RequestByUserId
+	var originList []dto.OriginResponse
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		dto.ValidationError(ctx, err)
+		return
+	}
+
+	origins, err := oh.service.GetDeletedOrigins(ctx, req.UserId)
+	if err != nil {
+		dto.HandleError(ctx, err)
+		return
+	}
+
+	for _, origin := range origins {
+		originList = append(originList, dto.NewOriginResponse(&origin))
+	}
+
+	if originList == nil {
+		originList = []dto.OriginResponse{}
+	}
+
+	dto.HandleSuccess(ctx, originList)
+}
+
+func (oh *OriginHandler) RestoreOrigin(ctx *gin.Context) {
+
+	var request dto.IdRequest
+	if err := ctx.ShouldBindUri(&request); err != nil {
+		dto.ValidationError(ctx, err)
+		return
+	}
+
+	origin, err := oh.service.RestoreOrigin(ctx, request.ID)
+	if err != nil {
+		dto.HandleError(ctx, err)
+		return
+	}
+
+	response := dto.NewOriginResponse(origin)
+
+	dto.HandleSuccess(ctx, response)
+}
@@ -0,0 +1,82 @@
+package http
+
+import (
+	"personal-finance/adapter/handler/http/dto"
+	"personal-finance/core/domain"
+	"personal-finance/core/port"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OutboxHandler struct {
+	service port.OutboxService
+}
+
+func NewOutboxHandler(service port.OutboxService) *OutboxHandler {
+	return &OutboxHandler{
+		service,
+	}
+}
+
+func (oh *OutboxHandler) GetMessages(ctx *gin.Context) {
+
+	var req dto.OutboxRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		dto.ValidationError(ctx, err)
+		return
+	}
+
+	messages, totalDocuments, totalPages, err := oh.service.GetMessages(ctx, req.Status, req.Page, req.Limit)
+	if err != nil {
+		dto.HandleError(ctx, err)
+		return
+	}
+
+	if messages == nil {
+		messages = []domain.OutboxMessage{}
+	}
+
+	response := dto.NewPaginatedResponse(
+		req.Page,
+		req.Limit,
+		totalDocuments,
+		totalPages,
+		messages,
+	)
+
+	dto.HandleSuccess(ctx, response)
+}
+
+func (oh *OutboxHandler) GetMessageById(ctx *gin.Context) {
+
+	var request dto.IdRequest
+	if err := ctx.ShouldBindUri(&request); err != nil {
+		dto.ValidationError(ctx, err)
+		return
+	}
+
+	message, err := oh.service.GetMessageById(ctx, request.ID)
+	if err != nil {
+		dto.HandleError(ctx, err)
+		return
+	}
+
+	dto.HandleSuccess(ctx, message)
+}
+
+func (oh *OutboxHandler) ResendMessage(ctx *gin.Context) {
+
+	var request dto.IdRequest
+	if err := ctx.ShouldBindUri(&request); err != nil {
+		dto.ValidationError(ctx, err)
+		return
+	}
+
+	message, err := oh.service.ResendMessage(ctx, request.ID)
+	if err != nil {
+		dto.HandleError(ctx, err)
+		return
+	}
+
+	dto.HandleSuccess(ctx, message)
+}
@@ -0,0 +1,115 @@
+package http
+
+import (
+	"personal-finance/adapter/handler/http/dto"
+	"personal-finance/core/port"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReconciliationHandler struct {
+	service port.ReconciliationService
+}
+
+func NewReconciliationHandler(service port.ReconciliationService) *ReconciliationHandler {
+	return &ReconciliationHandler{
+		service,
+	}
+}
+
+func (rh *ReconciliationHandler) StartReconciliation(ctx *gin.Context) {
+
+	var request dto.IdRequest
+	if err := ctx.ShouldBindUri(&request); err != nil {
+		dto.ValidationError(ctx, err)
+		return
+	}
+
+	var req dto.ReconciliationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		dto.ValidationError(ctx, err)
+		return
+	}
+
+	state, err := rh.service.StartReconciliation(ctx, request.ID, req.StatementDate, req.StatementBalance)
+	if err != nil {
+		dto.HandleError(ctx, err)
+		return
+	}
+
+	dto.HandleSuccess(ctx, state)
+}
+
+func (rh *ReconciliationHandler) GetReconciliation(ctx *gin.Context) {
+
+	var request dto.IdRequest
+	if err := ctx.ShouldBindUri(&request); err != nil {
+		dto.ValidationError(ctx, err)
+		return
+	}
+
+	state, err := rh.service.GetReconciliation(ctx, request.ID)
+	if err != nil {
+		dto.HandleError(ctx, err)
+		return
+	}
+
+	dto.HandleSuccess(ctx, state)
+}
+
+func (rh *ReconciliationHandler) SetCleared(ctx *gin.Context) {
+
+	var request dto.IdRequest
+	if err := ctx.ShouldBindUri(&request); err != nil {
+		dto.ValidationError(ctx, err)
+		return
+	}
+
+	var req dto.ClearedRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		dto.ValidationError(ctx, err)
+		return
+	}
+
+	state, err := rh.service.SetCleared(ctx, request.ID, req.TransactionIds, req.Cleared)
+	if err != nil {
+		dto.HandleError(ctx, err)
+		return
+	}
+
+	dto.HandleSuccess(ctx, state)
+}
+
+func (rh *ReconciliationHandler) CompleteReconciliation(ctx *gin.Context) {
+
+	var request dto.IdRequest
+	if err := ctx.ShouldBindUri(&request); err != nil {
+		dto.ValidationError(ctx, err)
+		return
+	}
+
+	reconciliation, err := rh.service.CompleteReconciliation(ctx, request.ID)
+	if err != nil {
+		dto.HandleError(ctx, err)
+		return
+	}
+
+	dto.HandleSuccess(ctx, reconciliation)
+}
+
+func (rh *ReconciliationHandler) CancelReconciliation(ctx *gin.Context) {
+
+	var request dto.IdRequest
+	if err := ctx.ShouldBindUri(&request); err != nil {
+		dto.ValidationError(ctx, err)
+		return
+	}
+
+	err := rh.service.CancelReconciliation(ctx, request.ID)
+	if err != nil {
+		dto.HandleError(ctx, err)
+		return
+	}
+
+	dto.HandleSuccess(ctx, nil)
+}
@@ -1,19 +1,26 @@
 package http
 
 import (
+	"fmt"
+	"net/http"
 	"personal-finance/adapter/handler/http/dto"
+	"personal-finance/core/domain"
 	"personal-finance/core/port"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
 type ReportHandler struct {
-	reportService port.ReportService
+	reportService   port.ReportService
+	deliveryService port.ReportDeliveryService
 }
 
-func NewReportHandler(reportService port.ReportService) *ReportHandler {
+func NewReportHandler(reportService port.ReportService, deliveryService port.ReportDeliveryService) *ReportHandler {
 	return &ReportHandler{
 		reportService,
+		deliveryService,
 	}
 }
 
@@ -32,3 +39,95 @@ func (rh *ReportHandler) GenerateMonthlyTransactionReport(ctx *gin.Context) {
 
 	dto.HandleSuccess(ctx, "Mail sended successfully")
 }
+
+func (rh *ReportHandler) GetReport(ctx *gin.Context) {
+
+	var request dto.ReportRequest
+	if err := ctx.Bind(&request); err != nil {
+		dto.ValidationError(ctx, err)
+		return
+	}
+
+	period, err := newReportPeriod(request)
+	if err != nil {
+		dto.HandleError(ctx, err)
+		return
+	}
+
+	report, err := rh.reportService.GetReport(ctx, request.UserId, period, request.IncludeArchived)
+	if err != nil {
+		dto.HandleError(ctx, err)
+		return
+	}
+
+	dto.HandleSuccess(ctx, report)
+}
+
+func (rh *ReportHandler) DownloadReportPDF(ctx *gin.Context) {
+
+	var request dto.ReportRequest
+	if err := ctx.Bind(&request); err != nil {
+		dto.ValidationError(ctx, err)
+		return
+	}
+
+	period, err := newReportPeriod(request)
+	if err != nil {
+		dto.HandleError(ctx, err)
+		return
+	}
+
+	document, err := rh.reportService.GetReportPDF(ctx, request.UserId, period, request.IncludeArchived)
+	if err != nil {
+		dto.HandleError(ctx, err)
+		return
+	}
+
+	filename := fmt.Sprintf("report-%s.pdf", strings.ReplaceAll(period.Label, " ", "-"))
+
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	ctx.Data(http.StatusOK, "application/pdf", document)
+}
+
+func (rh *ReportHandler) GetDeliveryStatus(ctx *gin.Context) {
+
+	var request dto.DeliveryStatusRequest
+	if err := ctx.Bind(&request); err != nil {
+		dto.ValidationError(ctx, err)
+		return
+	}
+
+	if request.Period == "" {
+		request.Period = domain.PeriodKey(domain.PreviousMonthPeriod(time.Now()))
+	}
+
+	status, err := rh.deliveryService.GetDeliveryStatus(ctx, request.Period)
+	if err != nil {
+		dto.HandleError(ctx, err)
+		return
+	}
+
+	dto.HandleSuccess(ctx, status)
+}
+
+func newReportPeriod(request dto.ReportRequest) (domain.ReportPeriod, error) {
+
+	if request.Period != "custom" && request.Year == 0 {
+		return domain.ReportPeriod{}, domain.ErrInvalidPeriod
+	}
+
+	switch request.Period {
+	case "month":
+		return domain.MonthPeriod(request.Year, time.Month(request.Month))
+	case "quarter":
+		return domain.QuarterPeriod(request.Year, request.Quarter)
+	case "year":
+		return domain.YearPeriod(request.Year), nil
+	}
+
+	if request.From == nil || request.To == nil {
+		return domain.ReportPeriod{}, domain.ErrInvalidPeriod
+	}
+
+	return domain.CustomPeriod(*request.From, *request.To)
+}
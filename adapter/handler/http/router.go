@@ -20,6 +20,19 @@ func NewRouter(
 	authHandler AuthHandler,
 	originHandler OriginHandler,
 	reportHandler ReportHandler,
+	tagHandler TagHandler,
+	attachmentHandler AttachmentHandler,
+	fileHandler FileHandler,
+	outboxHandler OutboxHandler,
+	analyticsHandler AnalyticsHandler,
+	snapshotHandler SnapshotHandler,
+	forecastHandler ForecastHandler,
+	statementHandler StatementHandler,
+	debtHandler DebtHandler,
+	reconciliationHandler ReconciliationHandler,
+	balanceHandler BalanceHandler,
+	ledgerHandler LedgerHandler,
+	auditHandler AuditHandler,
 ) (*Router, error) {
 
 	if config.App.Env == "production" {
@@ -49,6 +62,11 @@ func NewRouter(
 			status.GET("/status", transactionHandler.GetStatus)
 		}
 
+		files := v1.Group("/files")
+		{
+			files.GET("/*path", fileHandler.ServeFile)
+		}
+
 		auth := v1.Group("/users")
 		{
 			auth.POST("/register", authHandler.Register)
@@ -58,6 +76,7 @@ func NewRouter(
 		{
 			auth.GET("/", authHandler.GetUserById)
 			auth.PUT("/:id", authHandler.UpdateUser)
+			auth.PUT("/:id/preferences", authHandler.UpdatePreferences)
 			auth.DELETE("/:id", authHandler.DeleteUser)
 		}
 
@@ -67,26 +86,123 @@ func NewRouter(
 			transaction.GET("/", transactionHandler.GetTransactionsByUserId)
 			transaction.GET("/filter_date", transactionHandler.GetTransactionsByDate)
 			transaction.GET("/filter_type", transactionHandler.GetTransactionsByType)
+			transaction.GET("/filter_tags", transactionHandler.GetTransactionsByTags)
+			transaction.GET("/search", transactionHandler.SearchTransactions)
+			transaction.GET("/text_search", transactionHandler.TextSearchTransactions)
+			transaction.GET("/trash", transactionHandler.GetDeletedTransactions)
 			transaction.GET("/:id", transactionHandler.GetTransactionById)
 			transaction.POST("/", transactionHandler.CreateTransaction)
 			transaction.PUT("/:id", transactionHandler.UpdateTransaction)
 			transaction.DELETE("/:id", transactionHandler.DeleteTransaction)
+			transaction.POST("/:id/restore", transactionHandler.RestoreTransaction)
+			transaction.GET("/:id/attachments", attachmentHandler.GetAttachmentsByTransactionId)
+			transaction.POST("/:id/attachments", attachmentHandler.UploadAttachment)
+			transaction.DELETE("/:id/attachments/:attachment_id", attachmentHandler.DeleteAttachment)
 		}
 
 		origin := v1.Group("/origins")
 		origin.Use(middleware.Implement(config.Token))
 		{
 			origin.GET("/", originHandler.GetOriginsByUserId)
+			origin.GET("/trash", originHandler.GetDeletedOrigins)
 			origin.GET("/:id", originHandler.GetOriginById)
 			origin.POST("/", originHandler.CreateOrigin)
 			origin.PUT("/:id", originHandler.UpdateOrigin)
 			origin.DELETE("/:id", originHandler.DeleteOrigin)
+			origin.POST("/:id/restore", originHandler.RestoreOrigin)
+			origin.POST("/:id/archive", originHandler.ArchiveOrigin)
+			origin.POST("/:id/unarchive", originHandler.UnarchiveOrigin)
+			origin.GET("/:id/statements", statementHandler.GetStatements)
+			origin.POST("/:id/reconciliations", reconciliationHandler.StartReconciliation)
+		}
+
+		tag := v1.Group("/tags")
+		tag.Use(middleware.Implement(config.Token))
+		{
+			tag.GET("/", tagHandler.GetTagsByUserId)
+			tag.GET("/:id", tagHandler.GetTagById)
+			tag.POST("/", tagHandler.CreateTag)
+			tag.PUT("/:id", tagHandler.UpdateTag)
+			tag.DELETE("/:id", tagHandler.DeleteTag)
 		}
 
 		report := v1.Group("/reports")
 		report.Use(middleware.Implement(config.Token))
 		{
 			report.GET("/", reportHandler.GenerateMonthlyTransactionReport)
+			report.GET("/summary", reportHandler.GetReport)
+			report.GET("/pdf", reportHandler.DownloadReportPDF)
+			report.GET("/deliveries", middleware.RequireRole("admin"), reportHandler.GetDeliveryStatus)
+		}
+
+		analytics := v1.Group("/analytics")
+		analytics.Use(middleware.Implement(config.Token))
+		{
+			analytics.GET("/trends", analyticsHandler.GetTrends)
+			analytics.GET("/net_worth", snapshotHandler.GetNetWorthHistory)
+			analytics.POST("/net_worth/backfill", snapshotHandler.BackfillSnapshots)
+		}
+
+		debt := v1.Group("/debts")
+		debt.Use(middleware.Implement(config.Token))
+		{
+			debt.GET("/", debtHandler.GetDebtsByUserId)
+			debt.GET("/:id", debtHandler.GetDebtStatus)
+			debt.POST("/", debtHandler.CreateDebt)
+			debt.PUT("/:id", debtHandler.UpdateDebt)
+			debt.DELETE("/:id", debtHandler.DeleteDebt)
+			debt.GET("/:id/schedule", debtHandler.GetSchedule)
+			debt.POST("/:id/payments", debtHandler.RecordPayment)
+			debt.GET("/:id/simulate", debtHandler.SimulateExtraPayments)
+		}
+
+		reconciliation := v1.Group("/reconciliations")
+		reconciliation.Use(middleware.Implement(config.Token))
+		{
+			reconciliation.GET("/:id", reconciliationHandler.GetReconciliation)
+			reconciliation.PUT("/:id/cleared", reconciliationHandler.SetCleared)
+			reconciliation.POST("/:id/complete", reconciliationHandler.CompleteReconciliation)
+			reconciliation.DELETE("/:id", reconciliationHandler.CancelReconciliation)
+		}
+
+		forecast := v1.Group("/forecast")
+		forecast.Use(middleware.Implement(config.Token))
+		{
+			forecast.GET("/", forecastHandler.GetForecast)
+			forecast.GET("/items", forecastHandler.GetExpectedItemsByUserId)
+			forecast.GET("/items/:id", forecastHandler.GetExpectedItemById)
+			forecast.POST("/items", forecastHandler.CreateExpectedItem)
+			forecast.PUT("/items/:id", forecastHandler.UpdateExpectedItem)
+			forecast.DELETE("/items/:id", forecastHandler.DeleteExpectedItem)
+		}
+
+		outbox := v1.Group("/outbox")
+		outbox.Use(middleware.Implement(config.Token), middleware.RequireRole("admin"))
+		{
+			outbox.GET("/", outboxHandler.GetMessages)
+			outbox.GET("/:id", outboxHandler.GetMessageById)
+			outbox.POST("/:id/resend", outboxHandler.ResendMessage)
+		}
+
+		balance := v1.Group("/balances")
+		balance.Use(middleware.Implement(config.Token), middleware.RequireRole("admin"))
+		{
+			balance.GET("/", balanceHandler.AuditBalances)
+			balance.POST("/repair", balanceHandler.RepairBalances)
+		}
+
+		ledger := v1.Group("/ledger")
+		ledger.Use(middleware.Implement(config.Token))
+		{
+			ledger.GET("/entries", ledgerHandler.GetEntries)
+			ledger.GET("/balances", ledgerHandler.GetTrialBalance)
+			ledger.POST("/migrate", middleware.RequireRole("admin"), ledgerHandler.MigrateUser)
+		}
+
+		history := v1.Group("/history")
+		history.Use(middleware.Implement(config.Token))
+		{
+			history.GET("/:entity/:id", auditHandler.GetHistory)
 		}
 	}
 
@@ -0,0 +1,52 @@
+package http
+
+import (
+	"personal-finance/adapter/handler/http/dto"
+	"personal-finance/core/port"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SnapshotHandler struct {
+	service port.SnapshotService
+}
+
+func NewSnapshotHandler(service port.SnapshotService) *SnapshotHandler {
+	return &SnapshotHandler{
+		service,
+	}
+}
+
+func (sh *SnapshotHandler) GetNetWorthHistory(ctx *gin.Context) {
+
+	var request dto.NetWorthRequest
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		dto.ValidationError(ctx, err)
+		return
+	}
+
+	history, err := sh.service.GetNetWorthHistory(ctx, request.UserId, request.From, request.To)
+	if err != nil {
+		dto.HandleError(ctx, err)
+		return
+	}
+
+	dto.HandleSuccess(ctx, history)
+}
+
+func (sh *SnapshotHandler) BackfillSnapshots(ctx *gin.Context) {
+
+	var request dto.BackfillRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		dto.ValidationError(ctx, err)
+		return
+	}
+
+	inserted, err := sh.service.BackfillSnapshots(ctx, request.UserId)
+	if err != nil {
+		dto.HandleError(ctx, err)
+		return
+	}
+
+	dto.HandleSuccess(ctx, dto.BackfillResponse{Inserted: inserted})
+}
@@ -0,0 +1,46 @@
+package http
+
+import (
+	"personal-finance/adapter/handler/http/dto"
+	"personal-finance/core/port"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const defaultStatementCycles = 3
+
+type StatementHandler struct {
+	service port.StatementService
+}
+
+func NewStatementHandler(service port.StatementService) *StatementHandler {
+	return &StatementHandler{
+		service,
+	}
+}
+
+func (sh *StatementHandler) GetStatements(ctx *gin.Context) {
+
+	var request dto.StatementRequest
+	if err := ctx.ShouldBindUri(&request); err != nil {
+		dto.ValidationError(ctx, err)
+		return
+	}
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		dto.ValidationError(ctx, err)
+		return
+	}
+
+	if request.Cycles == 0 {
+		request.Cycles = defaultStatementCycles
+	}
+
+	statements, err := sh.service.GetStatements(ctx, request.ID, request.Cycles, time.Now())
+	if err != nil {
+		dto.HandleError(ctx, err)
+		return
+	}
+
+	dto.HandleSuccess(ctx, statements)
+}
@@ -0,0 +1,153 @@
+package http
+
+import (
+	"personal-finance/adapter/handler/http/dto"
+	"personal-finance/core/domain"
+	"personal-finance/core/port"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+type TagHandler struct {
+	service  port.TagService
+	validate *validator.Validate
+}
+
+func NewTagHandler(service port.TagService, validate *validator.Validate) *TagHandler {
+	return &TagHandler{
+		service,
+		validate,
+	}
+}
+
+func (th *TagHandler) GetTagsByUserId(ctx *gin.Context) {
+
+	var req dto.RequestByUserId
+	var tagList []dto.TagResponse
+
+	if err := ctx.Bind(&req); err != nil {
+		dto.ValidationError(ctx, err)
+		return
+	}
+
+	tags, err := th.service.GetTagsByUserId(ctx, req.UserId)
+	if err != nil {
+		dto.HandleError(ctx, err)
+		return
+	}
+
+	for _, tag := range tags {
+		tagList = append(tagList, dto.NewTagResponse(&tag))
+	}
+
+	if tagList == nil {
+		tagList = []dto.TagResponse{}
+	}
+
+	dto.HandleSuccess(ctx, tagList)
+}
+
+func (th *TagHandler) GetTagById(ctx *gin.Context) {
+
+	var request dto.IdRequest
+	if err := ctx.ShouldBindUri(&request); err != nil {
+		dto.ValidationError(ctx, err)
+		return
+	}
+
+	tag, err := th.service.GetTagById(ctx, request.ID)
+	if err != nil {
+		dto.HandleError(ctx, err)
+		return
+	}
+
+	response := dto.NewTagResponse(tag)
+
+	dto.HandleSuccess(ctx, response)
+}
+
+func (th *TagHandler) CreateTag(ctx *gin.Context) {
+
+	var req dto.TagRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		dto.ValidationError(ctx, err)
+		return
+	}
+
+	if err := th.validate.Struct(req); err != nil {
+		dto.ValidationError(ctx, err)
+		return
+	}
+
+	tag := domain.Tag{
+		UserId:      req.UserId,
+		Name:        req.Name,
+		Color:       req.Color,
+		Description: req.Description,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+
+	_, err := th.service.CreateTag(ctx, &tag)
+	if err != nil {
+		dto.HandleError(ctx, err)
+		return
+	}
+
+	response := dto.NewTagResponse(&tag)
+
+	dto.HandleSuccess(ctx, response)
+}
+
+func (th *TagHandler) UpdateTag(ctx *gin.Context) {
+
+	var req dto.TagRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		dto.ValidationError(ctx, err)
+		return
+	}
+
+	if err := th.validate.Struct(req); err != nil {
+		dto.ValidationError(ctx, err)
+		return
+	}
+
+	id := ctx.Param("id")
+
+	tag := domain.Tag{
+		UserId:      req.UserId,
+		Name:        req.Name,
+		Color:       req.Color,
+		Description: req.Description,
+		UpdatedAt:   time.Now(),
+	}
+
+	_, err := th.service.UpdateTag(ctx, id, &tag)
+	if err != nil {
+		dto.HandleError(ctx, err)
+		return
+	}
+
+	response := dto.NewTagResponse(&tag)
+
+	dto.HandleSuccess(ctx, response)
+}
+
+func (th *TagHandler) DeleteTag(ctx *gin.Context) {
+
+	var request dto.IdRequest
+	if err := ctx.ShouldBindUri(&request); err != nil {
+		dto.ValidationError(ctx, err)
+		return
+	}
+
+	err := th.service.DeleteTag(ctx, request.ID)
+	if err != nil {
+		dto.HandleError(ctx, err)
+		return
+	}
+
+	dto.HandleSuccess(ctx, nil)
+}
@@ -47,9 +47,25 @@ func (am *AuthMiddleware) Implement(config *config.Token) gin.HandlerFunc {
 		}
 
 		userID := claims["id"].(string)
-		ctx.Set("userID", userID)
+		ctx.Set(domain.ActorContextKey, userID)
 		ctx.Set("userRole", claims["role"].(string))
 
 		ctx.Next()
 	}
 }
+
+// RequireRole only lets through users whose token carries role. It must run
+// after Implement.
+func (am *AuthMiddleware) RequireRole(role string) gin.HandlerFunc {
+
+	return func(ctx *gin.Context) {
+
+		if ctx.GetString("userRole") != role {
+			dto.HandleError(ctx, domain.ErrForbidden)
+			ctx.Abort()
+			return
+		}
+
+		ctx.Next()
+	}
+}
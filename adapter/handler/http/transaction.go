@@ -4,6 +4,7 @@ import (
 	"personal-finance/adapter/handler/http/dto"
 	"personal-finance/core/domain"
 	"personal-finance/core/port"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -38,6 +39,11 @@ func (th *TransactionHandler) GetTransactionsByUserId(ctx *gin.Context) {
 		return
 	}
 
+	if req.Page == 0 {
+		th.respondWithCursor(ctx, domain.TransactionFilter{UserId: req.UserId}, &req)
+		return
+	}
+
 	transactions, totalDocuments, totalPages, err := th.service.GetTransactionsByUserId(ctx, req.Page, req.Limit, req.UserId)
 	if err != nil {
 		dto.HandleError(ctx, err)
@@ -73,6 +79,12 @@ func (th *TransactionHandler) GetTransactionsByDate(ctx *gin.Context) {
 		return
 	}
 
+	if req.Page == 0 {
+		from, to := domain.MonthRange(req.Year, req.Month)
+		th.respondWithCursor(ctx, domain.TransactionFilter{UserId: req.UserId, From: &from, To: &to}, req.TransactionByUserRequest)
+		return
+	}
+
 	transactions, totalDocuments, totalPages, err := th.service.GetTransactionsByDate(ctx, req.UserId, req.Page, req.Limit, req.Year, req.Month)
 	if err != nil {
 		dto.HandleError(ctx, err)
@@ -108,6 +120,11 @@ func (th *TransactionHandler) GetTransactionsByType(ctx *gin.Context) {
 		return
 	}
 
+	if req.Page == 0 {
+		th.respondWithCursor(ctx, domain.TransactionFilter{UserId: req.UserId, Type: req.Type}, req.TransactionByUserRequest)
+		return
+	}
+
 	transactions, totalDocuments, totalPages, err := th.service.GetTransactionsByType(ctx, req.UserId, req.Page, req.Limit, req.Type)
 	if err != nil {
 		dto.HandleError(ctx, err)
@@ -133,6 +150,151 @@ func (th *TransactionHandler) GetTransactionsByType(ctx *gin.Context) {
 	dto.HandleSuccess(ctx, response)
 }
 
+func (th *TransactionHandler) GetTransactionsByTags(ctx *gin.Context) {
+
+	var req dto.TagFilterRequest
+	var transactionList []dto.TransactionResponse
+
+	if err := ctx.Bind(&req); err != nil {
+		dto.ValidationError(ctx, err)
+		return
+	}
+
+	if req.Page == 0 {
+		filter := domain.TransactionFilter{UserId: req.UserId, Tags: splitQueryList(req.Tags), AnyTag: req.Match != "all"}
+		th.respondWithCursor(ctx, filter, req.TransactionByUserRequest)
+		return
+	}
+
+	transactions, totalDocuments, totalPages, err := th.service.GetTransactionsByTags(ctx, req.UserId, req.Page, req.Limit, splitQueryList(req.Tags), req.Match == "all")
+	if err != nil {
+		dto.HandleError(ctx, err)
+		return
+	}
+
+	for _, transaction := range transactions {
+		transactionList = append(transactionList, dto.NewTransactionResponse(&transaction))
+	}
+
+	if transactionList == nil {
+		transactionList = []dto.TransactionResponse{}
+	}
+
+	response := dto.NewPaginatedResponse(
+		req.Page,
+		req.Limit,
+		totalDocuments,
+		totalPages,
+		transactionList,
+	)
+
+	dto.HandleSuccess(ctx, response)
+}
+
+func (th *TransactionHandler) SearchTransactions(ctx *gin.Context) {
+
+	var req dto.SearchRequest
+	var transactionList []dto.TransactionResponse
+
+	if err := ctx.Bind(&req); err != nil {
+		dto.ValidationError(ctx, err)
+		return
+	}
+
+	filter := domain.TransactionFilter{
+		UserId:        req.UserId,
+		From:          req.From,
+		Type:          req.Type,
+		OriginIds:     splitQueryList(req.OriginIds),
+		Category:      req.Category,
+		Payee:         req.Payee,
+		MinAmount:     req.MinAmount,
+		MaxAmount:     req.MaxAmount,
+		Text:          req.Text,
+		Tags:          splitQueryList(req.Tags),
+		SortField:     req.Sort,
+		SortAscending: req.Order == "asc",
+	}
+
+	// "to" is an inclusive day in the API, an exclusive bound in the filter
+	if req.To != nil {
+		to := req.To.AddDate(0, 0, 1)
+		filter.To = &to
+	}
+
+	if req.Page == 0 {
+		th.respondWithCursor(ctx, filter, req.TransactionByUserRequest)
+		return
+	}
+
+	transactions, totalDocuments, totalPages, err := th.service.SearchTransactions(ctx, filter, req.Page, req.Limit)
+	if err != nil {
+		dto.HandleError(ctx, err)
+		return
+	}
+
+	for _, transaction := range transactions {
+		transactionList = append(transactionList, dto.NewTransactionResponse(&transaction))
+	}
+
+	if transactionList == nil {
+		transactionList = []dto.TransactionResponse{}
+	}
+
+	response := dto.NewPaginatedResponse(
+		req.Page,
+		req.Limit,
+		totalDocuments,
+		totalPages,
+		transactionList,
+	)
+
+	dto.HandleSuccess(ctx, response)
+}
+
+func (th *TransactionHandler) TextSearchTransactions(ctx *gin.Context) {
+
+	var req dto.TextSearchRequest
+	var resultList []dto.TextSearchResponse
+
+	if err := ctx.Bind(&req); err != nil {
+		dto.ValidationError(ctx, err)
+		return
+	}
+
+	results, totalDocuments, totalPages, err := th.service.TextSearchTransactions(ctx, req.UserId, req.Query, req.Page, req.Limit)
+	if err != nil {
+		dto.HandleError(ctx, err)
+		return
+	}
+
+	for _, result := range results {
+		highlights := result.Highlights
+		if highlights == nil {
+			highlights = []domain.TextHighlight{}
+		}
+		resultList = append(resultList, dto.TextSearchResponse{
+			Transaction: dto.NewTransactionResponse(&result.Transaction),
+			Score:       result.Score,
+			Highlights:  highlights,
+		})
+	}
+
+	if resultList == nil {
+		resultList = []dto.TextSearchResponse{}
+	}
+
+	response := dto.NewPaginatedResponse(
+		req.Page,
+		req.Limit,
+		totalDocuments,
+		totalPages,
+		resultList,
+	)
+
+	dto.HandleSuccess(ctx, response)
+}
+
 func (th *TransactionHandler) GetTransactionById(ctx *gin.Context) {
 	var request dto.IdRequest
 	if err := ctx.ShouldBindUri(&request); err != nil {
@@ -170,8 +332,11 @@ func (th *TransactionHandler) CreateTransaction(ctx *gin.Context) {
 		OriginId:         &req.OriginId,
 		Type:             req.Type,
 		Subject:          req.Subject,
+		OutputCategory:   req.OutputCategory,
+		IncomeSource:     req.IncomeSource,
 		PersonOrBusiness: req.PersonOrBusiness,
 		Description:      req.Description,
+		Tags:             req.Tags,
 		CreatedAtString:  req.CreatedAtString,
 		CreatedAt:        time.Now(),
 		UpdatedAt:        time.Now(),
@@ -209,8 +374,11 @@ func (th *TransactionHandler) UpdateTransaction(ctx *gin.Context) {
 		OriginId:         &req.OriginId,
 		Type:             req.Type,
 		Subject:          req.Subject,
+		OutputCategory:   req.OutputCategory,
+		IncomeSource:     req.IncomeSource,
 		PersonOrBusiness: req.PersonOrBusiness,
 		Description:      req.Description,
+		Tags:             req.Tags,
 		CreatedAtString:  req.CreatedAtString,
 		CreatedAt:        req.CreatedAt,
 		UpdatedAt:        time.Now(),
@@ -243,3 +411,104 @@ func (th *TransactionHandler) DeleteTransaction(ctx *gin.Context) {
 
 	dto.HandleSuccess(ctx, nil)
 }
+
+func (th *TransactionHandler) GetDeletedTransactions(ctx *gin.Context) {
+
+	var req dto.TrashRequest
+	var transactionList []dto.TransactionResponse
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		dto.ValidationError(ctx, err)
+		return
+	}
+
+	transactions, totalDocuments, totalPages, err := th.service.GetDeletedTransactions(ctx, req.UserId, req.Page, req.Limit)
+	if err != nil {
+		dto.HandleError(ctx, err)
+		return
+	}
+
+	for _, transaction := range transactions {
+		transactionList = append(transactionList, dto.NewTransactionResponse(&transaction))
+	}
+
+	if transactionList == nil {
+		transactionList = []dto.TransactionResponse{}
+	}
+
+	response := dto.NewPaginatedResponse(
+		req.Page,
+		req.Limit,
+		totalDocuments,
+		totalPages,
+		transactionList,
+	)
+
+	dto.HandleSuccess(ctx, response)
+}
+
+func (th *TransactionHandler) RestoreTransaction(ctx *gin.Context) {
+
+	var request dto.IdRequest
+	if err := ctx.ShouldBindUri(&request); err != nil {
+		dto.ValidationError(ctx, err)
+		return
+	}
+
+	transaction, err := th.service.RestoreTransaction(ctx, request.ID)
+	if err != nil {
+		dto.HandleError(ctx, err)
+		return
+	}
+
+	response := dto.NewTransactionResponse(transaction)
+
+	dto.HandleSuccess(ctx, response)
+}
+
+// respondWithCursor serves a listing in cursor pagination mode, the default
+// when the request carries no page number.
+func (th *TransactionHandler) respondWithCursor(ctx *gin.Context, filter domain.TransactionFilter, req *dto.TransactionByUserRequest) {
+
+	var transactionList []dto.TransactionResponse
+
+	transactions, totalDocuments, nextCursor, err := th.service.GetTransactionsByCursor(ctx, filter, req.Cursor, req.Limit)
+	if err != nil {
+		dto.HandleError(ctx, err)
+		return
+	}
+
+	for _, transaction := range transactions {
+		transactionList = append(transactionList, dto.NewTransactionResponse(&transaction))
+	}
+
+	if transactionList == nil {
+		transactionList = []dto.TransactionResponse{}
+	}
+
+	response := dto.NewCursorPaginatedResponse(
+		req.Limit,
+		totalDocuments,
+		nextCursor,
+		transactionList,
+	)
+
+	dto.HandleSuccess(ctx, response)
+}
+
+// splitQueryList accepts both repeated (?a=x&a=y) and comma separated
+// (?a=x,y) query values.
+func splitQueryList(values []string) []string {
+
+	var list []string
+
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+	}
+
+	return list
+}
@@ -0,0 +1,44 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"personal-finance/core/port"
+	"time"
+)
+
+// OutboxWorker periodically sends the mail outbox messages that are due.
+type OutboxWorker struct {
+	outboxService port.OutboxService
+	interval      time.Duration
+}
+
+func NewOutboxWorker(outboxService port.OutboxService, interval time.Duration) *OutboxWorker {
+
+	return &OutboxWorker{
+		outboxService,
+		interval,
+	}
+}
+
+// Start runs the worker until ctx is cancelled.
+func (ow *OutboxWorker) Start(ctx context.Context) {
+
+	ticker := time.NewTicker(ow.interval)
+	defer ticker.Stop()
+
+	for {
+		sent, err := ow.outboxService.ProcessDueMessages(ctx, time.Now())
+		if err != nil {
+			slog.Error("Error processing mail outbox", "error", err)
+		} else if sent > 0 {
+			slog.Info("Mail outbox processed", "sent", sent)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
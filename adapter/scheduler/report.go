@@ -0,0 +1,66 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"personal-finance/adapter/config"
+	"personal-finance/core/port"
+	"time"
+)
+
+// ReportScheduler delivers the monthly reports from inside the API process.
+// It checks on every tick whether the previous month's run is due or still
+// pending, so delivery starts on the configured report day and resumes after
+// a restart.
+type ReportScheduler struct {
+	deliveryService port.ReportDeliveryService
+	config          *config.Scheduler
+}
+
+func NewReportScheduler(deliveryService port.ReportDeliveryService, config *config.Scheduler) *ReportScheduler {
+
+	return &ReportScheduler{
+		deliveryService,
+		config,
+	}
+}
+
+// Start runs the scheduler until ctx is cancelled.
+func (rs *ReportScheduler) Start(ctx context.Context) {
+
+	if !rs.config.Enabled {
+		slog.Info("Report scheduler disabled")
+		return
+	}
+
+	ticker := time.NewTicker(rs.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		rs.tick(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (rs *ReportScheduler) tick(ctx context.Context) {
+
+	now := time.Now()
+
+	run, err := rs.deliveryService.DeliverMonthlyReports(ctx, now)
+	if err != nil {
+		slog.Error("Error delivering monthly reports", "error", err)
+		return
+	}
+
+	if run == nil || run.FinishedAt == nil || run.FinishedAt.Before(now) {
+		// not due yet, or already completed by an earlier tick
+		return
+	}
+
+	slog.Info("Monthly reports delivered", "period", run.Period, "status", run.Status, "sent", run.Sent, "failed", run.Failed, "skipped", run.Skipped)
+}
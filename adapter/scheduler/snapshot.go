@@ -0,0 +1,49 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"personal-finance/adapter/config"
+	"personal-finance/core/port"
+	"time"
+)
+
+// SnapshotScheduler records the balance of every origin once per interval.
+// Each run overwrites the snapshot of the current day, so the last run of a
+// day leaves its closing balance even for origins that saw no movement.
+type SnapshotScheduler struct {
+	snapshotService port.SnapshotService
+	config          *config.Scheduler
+}
+
+func NewSnapshotScheduler(snapshotService port.SnapshotService, config *config.Scheduler) *SnapshotScheduler {
+
+	return &SnapshotScheduler{
+		snapshotService,
+		config,
+	}
+}
+
+// Start runs the scheduler until ctx is cancelled.
+func (ss *SnapshotScheduler) Start(ctx context.Context) {
+
+	if !ss.config.Enabled {
+		slog.Info("Snapshot scheduler disabled")
+		return
+	}
+
+	ticker := time.NewTicker(ss.config.SnapshotInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := ss.snapshotService.SnapshotAllOrigins(ctx, time.Now()); err != nil {
+			slog.Error("Error recording balance snapshots", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"personal-finance/adapter/config"
+	"personal-finance/core/port"
+	"time"
+)
+
+// TrashScheduler permanently deletes, once per interval, the transactions and
+// origins that have been in the trash for longer than the retention period.
+type TrashScheduler struct {
+	transactionService port.TransactionService
+	originService      port.OriginService
+	config             *config.Scheduler
+}
+
+func NewTrashScheduler(
+	transactionService port.TransactionService,
+	originService port.OriginService,
+	config *config.Scheduler,
+) *TrashScheduler {
+
+	return &TrashScheduler{
+		transactionService,
+		originService,
+		config,
+	}
+}
+
+// Start runs the scheduler until ctx is cancelled.
+func (ts *TrashScheduler) Start(ctx context.Context) {
+
+	if !ts.config.Enabled {
+		slog.Info("Trash scheduler disabled")
+		return
+	}
+
+	ticker := time.NewTicker(ts.config.PurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		ts.purge(ctx, time.Now().Add(-ts.config.TrashRetention))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (ts *TrashScheduler) purge(ctx context.Context, before time.Time) {
+
+	transactions, err := ts.transactionService.PurgeDeletedTransactions(ctx, before)
+	if err != nil {
+		slog.Error("Error purging deleted transactions", "error", err)
+	}
+
+	origins, err := ts.originService.PurgeDeletedOrigins(ctx, before)
+	if err != nil {
+		slog.Error("Error purging deleted origins", "error", err)
+	}
+
+	if transactions > 0 || origins > 0 {
+		slog.Info("Trash purged", "transactions", transactions, "origins", origins)
+	}
+}
@@ -0,0 +1,77 @@
+package adapter
+
+import (
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"personal-finance/adapter/storage/cloud/data"
+	"personal-finance/core/domain"
+
+	"github.com/cloudinary/cloudinary-go/v2"
+	"github.com/cloudinary/cloudinary-go/v2/api/uploader"
+)
+
+type FileAdapter struct {
+	adapter *cloudinary.Cloudinary
+}
+
+func NewFileAdapter(adapter *cloudinary.Cloudinary) *FileAdapter {
+	return &FileAdapter{
+		adapter: adapter,
+	}
+}
+
+// UploadFile stores the document under folder letting Cloudinary pick the
+// public id. PDFs are kept as "image" resources so they can be previewed.
+func (fa *FileAdapter) UploadFile(ctx context.Context, file multipart.File, folder string) (*domain.StoredFile, error) {
+
+	var adapterResponse data.ImageUploadResponse
+
+	file.Seek(0, 0)
+
+	uploadParams := uploader.UploadParams{
+		Folder:       folder,
+		ResourceType: "image",
+	}
+
+	response, err := fa.adapter.Upload.Upload(ctx, file, uploadParams)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := json.Marshal(response)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(resp, &adapterResponse); err != nil {
+		return nil, err
+	}
+
+	storedFile := domain.StoredFile{
+		SecureUrl: adapterResponse.SecureUrl,
+		PublicId:  adapterResponse.PublicId,
+		Size:      int64(adapterResponse.Bytes),
+	}
+
+	return &storedFile, nil
+}
+
+func (fa *FileAdapter) DeleteFile(ctx context.Context, publicId string) error {
+	_, err := fa.adapter.Upload.Destroy(ctx, uploader.DestroyParams{
+		PublicID:     publicId,
+		ResourceType: "image",
+	})
+
+	return err
+}
+
+func (fa *FileAdapter) FileUrl(ctx context.Context, publicId string) (string, error) {
+
+	asset, err := fa.adapter.Image(publicId)
+	if err != nil {
+		return "", err
+	}
+
+	return asset.String()
+}
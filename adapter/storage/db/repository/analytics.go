@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"personal-finance/adapter/config"
+	"personal-finance/core/domain"
+	"regexp"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// AnalyticsRepository aggregates the transactions collection by month on the
+// database side.
+type AnalyticsRepository struct {
+	db *mongo.Collection
+}
+
+func NewAnalyticsRepository(db *mongo.Database, config *config.DB) *AnalyticsRepository {
+	return &AnalyticsRepository{
+		db.Collection(config.Transactions),
+	}
+}
+
+type monthGroup struct {
+	ID struct {
+		Year     int    `bson:"year"`
+		Month    int    `bson:"month"`
+		Type     string `bson:"type"`
+		Category string `bson:"category"`
+	} `bson:"_id"`
+	Total float64 `bson:"total"`
+}
+
+func (g monthGroup) monthKey() string {
+	return domain.MonthKey(time.Date(g.ID.Year, time.Month(g.ID.Month), 1, 0, 0, 0, 0, time.UTC))
+}
+
+// GetMonthlyTotals returns the income and expenses of every month in [from, to)
+// that has transactions counted by rules, in month order.
+func (ar *AnalyticsRepository) GetMonthlyTotals(ctx context.Context, userId string, from, to time.Time, rules domain.ReportRules) ([]domain.MonthlyTotals, error) {
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: analyticsMatch(userId, from, to, rules)}},
+		{{Key: "$group", Value: bson.M{
+			"_id": bson.M{
+				"year":  bson.M{"$year": "$created_at"},
+				"month": bson.M{"$month": "$created_at"},
+				"type":  "$type",
+			},
+			"total": bson.M{"$sum": "$amount"},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "_id.year", Value: 1}, {Key: "_id.month", Value: 1}}}},
+	}
+
+	groups, err := ar.aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	var totals []domain.MonthlyTotals
+
+	for _, group := range groups {
+		month := group.monthKey()
+
+		if len(totals) == 0 || totals[len(totals)-1].Month != month {
+			totals = append(totals, domain.MonthlyTotals{Month: month})
+		}
+
+		if group.ID.Type == "Income" {
+			totals[len(totals)-1].Income += group.Total
+		} else {
+			totals[len(totals)-1].Expenses += group.Total
+		}
+	}
+
+	return totals, nil
+}
+
+// GetMonthlyCategoryTotals returns the expenses per category and month in
+// [from, to), in month order.
+func (ar *AnalyticsRepository) GetMonthlyCategoryTotals(ctx context.Context, userId string, from, to time.Time, rules domain.ReportRules) ([]domain.CategoryMonthTotal, error) {
+
+	match := analyticsMatch(userId, from, to, rules)
+	match["type"] = bson.M{"$ne": "Income"}
+	match["output_category"] = bson.M{"$nin": bson.A{"", nil}}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{
+			"_id": bson.M{
+				"year":     bson.M{"$year": "$created_at"},
+				"month":    bson.M{"$month": "$created_at"},
+				"category": "$output_category",
+			},
+			"total": bson.M{"$sum": "$amount"},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "_id.year", Value: 1}, {Key: "_id.month", Value: 1}, {Key: "_id.category", Value: 1}}}},
+	}
+
+	groups, err := ar.aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	var totals []domain.CategoryMonthTotal
+
+	for _, group := range groups {
+		totals = append(totals, domain.CategoryMonthTotal{
+			Month:    group.monthKey(),
+			Category: group.ID.Category,
+			Expenses: group.Total,
+		})
+	}
+
+	return totals, nil
+}
+
+func (ar *AnalyticsRepository) aggregate(ctx context.Context, pipeline mongo.Pipeline) ([]monthGroup, error) {
+
+	var groups []monthGroup
+
+	cursor, err := ar.db.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var group monthGroup
+		if err := cursor.Decode(&group); err != nil {
+			return nil, err
+		}
+		groups = append(groups, group)
+	}
+
+	return groups, nil
+}
+
+// analyticsMatch selects the user's transactions in [from, to) that rules count.
+func analyticsMatch(userId string, from, to time.Time, rules domain.ReportRules) bson.M {
+
+	return bson.M{
+		"user_id":    userId,
+		"created_at": bson.M{"$gte": from, "$lt": to},
+		"deleted_at": notDeleted,
+		"$or": bson.A{
+			withSubjects(bson.M{"type": "Income"}, rules.IncomeSubjects),
+			withSubjects(bson.M{"type": bson.M{"$ne": "Income"}}, rules.ExpenseSubjects),
+		},
+	}
+}
+
+// withSubjects restricts match to the subjects, matched case-insensitively
+// like ReportRules.Counts does.
+func withSubjects(match bson.M, subjects []string) bson.M {
+
+	var patterns bson.A
+	for _, subject := range subjects {
+		if subject == domain.AnySubject {
+			return match
+		}
+		patterns = append(patterns, primitive.Regex{Pattern: "^" + regexp.QuoteMeta(subject) + "$", Options: "i"})
+	}
+
+	match["subject"] = bson.M{"$in": patterns}
+
+	return match
+}
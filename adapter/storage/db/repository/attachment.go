@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"personal-finance/adapter/config"
+	"personal-finance/core/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type AttachmentRepository struct {
+	db *mongo.Collection
+}
+
+func NewAttachmentRepository(db *mongo.Database, config *config.DB) *AttachmentRepository {
+	return &AttachmentRepository{
+		db.Collection(config.Attachments),
+	}
+}
+
+func (ar *AttachmentRepository) GetAttachmentsByTransactionId(ctx context.Context, transactionId string) ([]domain.Attachment, error) {
+
+	var attachments []domain.Attachment
+
+	filter := bson.M{
+		"transaction_id": transactionId,
+	}
+
+	findOptions := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+
+	cursor, err := ar.db.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var attachment domain.Attachment
+		if err := cursor.Decode(&attachment); err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, attachment)
+	}
+
+	return attachments, nil
+}
+
+func (ar *AttachmentRepository) GetAttachmentById(ctx context.Context, id string) (*domain.Attachment, error) {
+
+	var attachment domain.Attachment
+	objectId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ar.db.FindOne(ctx, bson.M{"_id": objectId}).Decode(&attachment); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return &attachment, nil
+}
+
+func (ar *AttachmentRepository) CreateAttachment(ctx context.Context, attachment *domain.Attachment) (*domain.Attachment, error) {
+
+	result, err := ar.db.InsertOne(ctx, attachment)
+	if err != nil {
+		return nil, err
+	}
+
+	attachment.ID = result.InsertedID.(primitive.ObjectID).Hex()
+
+	return attachment, nil
+}
+
+func (ar *AttachmentRepository) DeleteAttachment(ctx context.Context, id string) error {
+
+	objectId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	result, err := ar.db.DeleteOne(ctx, bson.M{"_id": objectId})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return domain.ErrDataNotFound
+	}
+
+	return nil
+}
+
+func (ar *AttachmentRepository) DeleteAttachmentsByTransactionId(ctx context.Context, transactionId string) error {
+
+	_, err := ar.db.DeleteMany(ctx, bson.M{"transaction_id": transactionId})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"personal-finance/adapter/config"
+	"personal-finance/core/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type AuditRepository struct {
+	db *mongo.Collection
+}
+
+func NewAuditRepository(db *mongo.Database, config *config.DB) *AuditRepository {
+	return &AuditRepository{
+		db.Collection(config.Audit),
+	}
+}
+
+func (ar *AuditRepository) CreateIndexes(ctx context.Context) error {
+
+	_, err := ar.db.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "entity", Value: 1},
+			{Key: "entity_id", Value: 1},
+			{Key: "created_at", Value: -1},
+		},
+	})
+
+	return err
+}
+
+func (ar *AuditRepository) CreateRecord(ctx context.Context, record *domain.AuditRecord) error {
+
+	_, err := ar.db.InsertOne(ctx, record)
+
+	return err
+}
+
+// GetHistory pages through the changes made to an entity of the user, newest
+// first.
+func (ar *AuditRepository) GetHistory(ctx context.Context, userId, entity, entityId string, page, limit uint64) ([]domain.AuditRecord, int64, int, error) {
+
+	var records []domain.AuditRecord
+
+	filter := bson.M{
+		"user_id":   userId,
+		"entity":    entity,
+		"entity_id": entityId,
+	}
+
+	total, err := ar.db.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+
+	cursor, err := ar.db.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var record domain.AuditRecord
+		if err := cursor.Decode(&record); err != nil {
+			return nil, 0, 0, err
+		}
+		records = append(records, record)
+	}
+
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+
+	return records, total, totalPages, nil
+}
@@ -4,10 +4,12 @@ import (
 	"context"
 	"personal-finance/adapter/config"
 	"personal-finance/core/domain"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type AuthRepository struct {
@@ -82,6 +84,77 @@ func (ar *AuthRepository) GetUserByEmail(ctx context.Context, email string) (*do
 	return &user, nil
 }
 
+// GetReportSubscribers returns up to limit users, in _id order after afterId,
+// that have not opted out of the monthly report.
+func (ar *AuthRepository) GetReportSubscribers(ctx context.Context, afterId string, limit int64) ([]domain.User, error) {
+
+	var users []domain.User
+
+	filter := bson.M{
+		"monthly_report_opt_out": bson.M{"$ne": true},
+	}
+
+	if afterId != "" {
+		objectId, err := primitive.ObjectIDFromHex(afterId)
+		if err != nil {
+			return nil, err
+		}
+		filter["_id"] = bson.M{"$gt": objectId}
+	}
+
+	findOptions := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetLimit(limit)
+
+	cursor, err := ar.db.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var user domain.User
+		if err := cursor.Decode(&user); err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+
+	return users, nil
+}
+
+func (ar *AuthRepository) UpdatePreferences(ctx context.Context, id string, preferences domain.UserPreferences) error {
+
+	objectId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	set := bson.M{
+		"updatedat": time.Now(),
+	}
+
+	if preferences.MonthlyReportOptOut != nil {
+		set["monthly_report_opt_out"] = *preferences.MonthlyReportOptOut
+	}
+	if preferences.Locale != nil {
+		set["locale"] = *preferences.Locale
+	}
+	if preferences.Currency != nil {
+		set["currency"] = *preferences.Currency
+	}
+
+	result, err := ar.db.UpdateOne(ctx, bson.M{"_id": objectId}, bson.M{"$set": set})
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return domain.ErrDataNotFound
+	}
+
+	return nil
+}
+
 func (ar *AuthRepository) DeleteUser(ctx context.Context, id string) error {
 
 	objectId, err := primitive.ObjectIDFromHex(id)
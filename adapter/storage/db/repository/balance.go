@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"personal-finance/adapter/config"
+	"personal-finance/core/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type BalanceRepository struct {
+	transactions *mongo.Collection
+}
+
+func NewBalanceRepository(db *mongo.Database, config *config.DB) *BalanceRepository {
+	return &BalanceRepository{
+		db.Collection(config.Transactions),
+	}
+}
+
+// GetOriginActivity sums the income and output booked on each of the user's
+// origins.
+func (br *BalanceRepository) GetOriginActivity(ctx context.Context, userId string) ([]domain.OriginActivity, error) {
+
+	var activity []domain.OriginActivity
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"user_id":    userId,
+			"origin_id":  bson.M{"$nin": bson.A{"", nil}},
+			"deleted_at": notDeleted,
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id": "$origin_id",
+			"income": bson.M{"$sum": bson.M{"$cond": bson.A{
+				bson.M{"$eq": bson.A{"$type", "Income"}}, "$amount", 0,
+			}}},
+			"output": bson.M{"$sum": bson.M{"$cond": bson.A{
+				bson.M{"$eq": bson.A{"$type", "Output"}}, "$amount", 0,
+			}}},
+			"transactions": bson.M{"$sum": 1},
+		}}},
+	}
+
+	cursor, err := br.transactions.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var origin domain.OriginActivity
+		if err := cursor.Decode(&origin); err != nil {
+			return nil, err
+		}
+		activity = append(activity, origin)
+	}
+
+	return activity, nil
+}
@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"personal-finance/adapter/config"
+	"personal-finance/core/domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type DebtRepository struct {
+	db           *mongo.Collection
+	transactions *mongo.Collection
+}
+
+func NewDebtRepository(db *mongo.Database, config *config.DB) *DebtRepository {
+	return &DebtRepository{
+		db.Collection(config.Debts),
+		db.Collection(config.Transactions),
+	}
+}
+
+func (dr *DebtRepository) GetDebtsByUserId(ctx context.Context, userId string) ([]domain.Debt, error) {
+
+	var debts []domain.Debt
+
+	findOptions := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+
+	cursor, err := dr.db.Find(ctx, bson.M{"user_id": userId}, findOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var debt domain.Debt
+		if err := cursor.Decode(&debt); err != nil {
+			return nil, err
+		}
+		debts = append(debts, debt)
+	}
+
+	return debts, nil
+}
+
+func (dr *DebtRepository) GetDebtById(ctx context.Context, id string) (*domain.Debt, error) {
+
+	var debt domain.Debt
+	objectId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := dr.db.FindOne(ctx, bson.M{"_id": objectId}).Decode(&debt); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return &debt, nil
+}
+
+func (dr *DebtRepository) CreateDebt(ctx context.Context, debt *domain.Debt) (*domain.Debt, error) {
+
+	result, err := dr.db.InsertOne(ctx, debt)
+	if err != nil {
+		return nil, err
+	}
+
+	debt.ID = result.InsertedID.(primitive.ObjectID).Hex()
+
+	return debt, nil
+}
+
+func (dr *DebtRepository) UpdateDebt(ctx context.Context, id string, debt *domain.Debt) (*domain.Debt, error) {
+
+	objectId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	update := bson.M{"$set": bson.M{
+		"origin_id":     debt.OriginId,
+		"name":          debt.Name,
+		"principal":     debt.Principal,
+		"interest_rate": debt.InterestRate,
+		"term_months":   debt.TermMonths,
+		"start_date":    debt.StartDate,
+		"updated_at":    time.Now(),
+	}}
+
+	result, err := dr.db.UpdateOne(ctx, bson.M{"_id": objectId}, update)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.MatchedCount == 0 {
+		return nil, domain.ErrDataNotFound
+	}
+
+	debt.ID = id
+
+	return debt, nil
+}
+
+func (dr *DebtRepository) DeleteDebt(ctx context.Context, id string) error {
+
+	objectId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	result, err := dr.db.DeleteOne(ctx, bson.M{"_id": objectId})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return domain.ErrDataNotFound
+	}
+
+	return nil
+}
+
+// GetDebtPayments returns the payments booked on the debt's loan origin,
+// oldest first.
+func (dr *DebtRepository) GetDebtPayments(ctx context.Context, debt *domain.Debt) ([]domain.Transaction, error) {
+
+	var transactions []domain.Transaction
+
+	filter := bson.M{
+		"origin_id":            debt.OriginId,
+		"debt_payment.debt_id": debt.ID,
+		"deleted_at":           notDeleted,
+	}
+
+	findOptions := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+
+	cursor, err := dr.transactions.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var transaction domain.Transaction
+		if err := cursor.Decode(&transaction); err != nil {
+			return nil, err
+		}
+		transactions = append(transactions, transaction)
+	}
+
+	return transactions, nil
+}
@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"personal-finance/adapter/config"
+	"personal-finance/core/domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ExpectedItemRepository struct {
+	db *mongo.Collection
+}
+
+func NewExpectedItemRepository(db *mongo.Database, config *config.DB) *ExpectedItemRepository {
+	return &ExpectedItemRepository{
+		db.Collection(config.ExpectedItems),
+	}
+}
+
+func (er *ExpectedItemRepository) GetExpectedItemsByUserId(ctx context.Context, userId string) ([]domain.ExpectedItem, error) {
+
+	var items []domain.ExpectedItem
+
+	findOptions := options.Find().SetSort(bson.D{{Key: "start_date", Value: 1}})
+
+	cursor, err := er.db.Find(ctx, bson.M{"user_id": userId}, findOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var item domain.ExpectedItem
+		if err := cursor.Decode(&item); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+func (er *ExpectedItemRepository) GetExpectedItemById(ctx context.Context, id string) (*domain.ExpectedItem, error) {
+
+	var item domain.ExpectedItem
+	objectId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := er.db.FindOne(ctx, bson.M{"_id": objectId}).Decode(&item); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+func (er *ExpectedItemRepository) CreateExpectedItem(ctx context.Context, item *domain.ExpectedItem) (*domain.ExpectedItem, error) {
+
+	result, err := er.db.InsertOne(ctx, item)
+	if err != nil {
+		return nil, err
+	}
+
+	item.ID = result.InsertedID.(primitive.ObjectID).Hex()
+
+	return item, nil
+}
+
+func (er *ExpectedItemRepository) UpdateExpectedItem(ctx context.Context, id string, item *domain.ExpectedItem) (*domain.ExpectedItem, error) {
+
+	objectId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	update := bson.M{"$set": bson.M{
+		"origin_id":       item.OriginId,
+		"type":            item.Type,
+		"amount":          item.Amount,
+		"subject":         item.Subject,
+		"person_business": item.PersonOrBusiness,
+		"description":     item.Description,
+		"frequency":       item.Frequency,
+		"start_date":      item.StartDate,
+		"end_date":        item.EndDate,
+		"updated_at":      time.Now(),
+	}}
+
+	result, err := er.db.UpdateOne(ctx, bson.M{"_id": objectId}, update)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.MatchedCount == 0 {
+		return nil, domain.ErrDataNotFound
+	}
+
+	item.ID = id
+
+	return item, nil
+}
+
+func (er *ExpectedItemRepository) DeleteExpectedItem(ctx context.Context, id string) error {
+
+	objectId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	result, err := er.db.DeleteOne(ctx, bson.M{"_id": objectId})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return domain.ErrDataNotFound
+	}
+
+	return nil
+}
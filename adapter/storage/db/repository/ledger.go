@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"context"
+	"personal-finance/adapter/config"
+	"personal-finance/core/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type LedgerRepository struct {
+	db *mongo.Collection
+}
+
+func NewLedgerRepository(db *mongo.Database, config *config.DB) *LedgerRepository {
+	return &LedgerRepository{
+		db.Collection(config.Ledger),
+	}
+}
+
+func (lr *LedgerRepository) CreateIndexes(ctx context.Context) error {
+
+	_, err := lr.db.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "date", Value: -1}}},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "postings.account", Value: 1}}},
+		{Keys: bson.D{{Key: "transaction_id", Value: 1}, {Key: "created_at", Value: -1}}},
+	})
+
+	return err
+}
+
+func (lr *LedgerRepository) AppendEntries(ctx context.Context, entries []domain.JournalEntry) error {
+
+	if len(entries) == 0 {
+		return nil
+	}
+
+	documents := make([]interface{}, len(entries))
+	for i, entry := range entries {
+		documents[i] = entry
+	}
+
+	_, err := lr.db.InsertMany(ctx, documents)
+
+	return err
+}
+
+// GetEntries pages through the user's journal, newest first, optionally only
+// the entries posting to account.
+func (lr *LedgerRepository) GetEntries(ctx context.Context, userId string, account string, page, limit uint64) ([]domain.JournalEntry, int64, int, error) {
+
+	var entries []domain.JournalEntry
+
+	filter := bson.M{"user_id": userId}
+	if account != "" {
+		filter["postings.account"] = account
+	}
+
+	total, err := lr.db.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "date", Value: -1}, {Key: "created_at", Value: -1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+
+	cursor, err := lr.db.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var entry domain.JournalEntry
+		if err := cursor.Decode(&entry); err != nil {
+			return nil, 0, 0, err
+		}
+		entries = append(entries, entry)
+	}
+
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+
+	return entries, total, totalPages, nil
+}
+
+// GetLastTransactionEntry returns the latest entry booked for the
+// transaction, which is a reversal once the transaction has been deleted.
+func (lr *LedgerRepository) GetLastTransactionEntry(ctx context.Context, transactionId string) (*domain.JournalEntry, error) {
+
+	var entry domain.JournalEntry
+
+	findOptions := options.FindOne().SetSort(bson.D{{Key: "created_at", Value: -1}})
+
+	if err := lr.db.FindOne(ctx, bson.M{"transaction_id": transactionId}, findOptions).Decode(&entry); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+func (lr *LedgerRepository) GetAccountTotals(ctx context.Context, userId string) ([]domain.AccountTotals, error) {
+
+	var totals []domain.AccountTotals
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"user_id": userId}}},
+		{{Key: "$unwind", Value: "$postings"}},
+		{{Key: "$group", Value: bson.M{
+			"_id":    "$postings.account",
+			"debit":  bson.M{"$sum": "$postings.debit"},
+			"credit": bson.M{"$sum": "$postings.credit"},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "_id", Value: 1}}}},
+	}
+
+	cursor, err := lr.db.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var account domain.AccountTotals
+		if err := cursor.Decode(&account); err != nil {
+			return nil, err
+		}
+		totals = append(totals, account)
+	}
+
+	return totals, nil
+}
+
+func (lr *LedgerRepository) GetJournaledTransactionIds(ctx context.Context, userId string) ([]string, error) {
+
+	return lr.distinctStrings(ctx, "transaction_id", bson.M{"user_id": userId, "transaction_id": bson.M{"$exists": true}})
+}
+
+// GetJournaledOriginIds returns the origins whose opening balance is already
+// in the journal.
+func (lr *LedgerRepository) GetJournaledOriginIds(ctx context.Context, userId string) ([]string, error) {
+
+	return lr.distinctStrings(ctx, "origin_id", bson.M{"user_id": userId, "kind": domain.EntryOpening})
+}
+
+func (lr *LedgerRepository) distinctStrings(ctx context.Context, field string, filter bson.M) ([]string, error) {
+
+	values, err := lr.db.Distinct(ctx, field, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(values))
+	for _, value := range values {
+		if id, ok := value.(string); ok {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids, nil
+}
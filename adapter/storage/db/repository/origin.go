@@ -22,12 +22,17 @@ func NewOriginRepository(db *mongo.Database, config *config.DB) *OriginRepositor
 	}
 }
 
-func (or *OriginRepository) GetOriginsByUserId(ctx context.Context, userId string) ([]domain.Origin, error) {
+func (or *OriginRepository) GetOriginsByUserId(ctx context.Context, userId string, includeArchived bool) ([]domain.Origin, error) {
 
 	var origins []domain.Origin
 
 	filter := bson.M{
-		"user_id": userId,
+		"user_id":    userId,
+		"deleted_at": notDeleted,
+	}
+
+	if !includeArchived {
+		filter["archived_at"] = bson.M{"$exists": false}
 	}
 
 	findOptions := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
@@ -59,7 +64,10 @@ func (or *OriginRepository) GetOriginById(ctx context.Context, id string) (*doma
 		return nil, err
 	}
 
-	if err := or.db.FindOne(ctx, bson.M{"_id": objectId}).Decode(&origin); err != nil {
+	if err := or.db.FindOne(ctx, bson.M{"_id": objectId, "deleted_at": notDeleted}).Decode(&origin); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrDataNotFound
+		}
 		return nil, err
 	}
 
@@ -86,12 +94,21 @@ func (or *OriginRepository) UpdateOrigin(ctx context.Context, id string, updated
 	}
 
 	origin := domain.OriginRequest{
-		UserId:      updatedOrigin.UserId,
-		Name:        updatedOrigin.Name,
-		Total:       updatedOrigin.Total,
-		Description: updatedOrigin.Description,
-		CreatedAt:   updatedOrigin.CreatedAt,
-		UpdatedAt:   time.Now(),
+		UserId:       updatedOrigin.UserId,
+		Name:         updatedOrigin.Name,
+		Kind:         updatedOrigin.Kind,
+		Total:        updatedOrigin.Total,
+		CreditLimit:  updatedOrigin.CreditLimit,
+		StatementDay: updatedOrigin.StatementDay,
+		InterestRate: updatedOrigin.InterestRate,
+		Description:  updatedOrigin.Description,
+		CreatedAt:    updatedOrigin.CreatedAt,
+		UpdatedAt:    time.Now(),
+
+		PaymentDueDays:     updatedOrigin.PaymentDueDays,
+		MinimumPaymentRate: updatedOrigin.MinimumPaymentRate,
+
+		OpeningBalance: updatedOrigin.OpeningBalance,
 	}
 
 	update := bson.M{"$set": origin}
@@ -110,6 +127,51 @@ func (or *OriginRepository) UpdateOrigin(ctx context.Context, id string, updated
 	return updatedOrigin, nil
 }
 
+func (or *OriginRepository) ArchiveOrigin(ctx context.Context, id string) error {
+
+	objectId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	result, err := or.db.UpdateOne(ctx,
+		bson.M{"_id": objectId, "deleted_at": notDeleted},
+		bson.M{"$set": bson.M{"archived_at": time.Now()}},
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return domain.ErrDataNotFound
+	}
+
+	return nil
+}
+
+func (or *OriginRepository) UnarchiveOrigin(ctx context.Context, id string) error {
+
+	objectId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	result, err := or.db.UpdateOne(ctx,
+		bson.M{"_id": objectId, "deleted_at": notDeleted},
+		bson.M{"$unset": bson.M{"archived_at": ""}},
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return domain.ErrDataNotFound
+	}
+
+	return nil
+}
+
+// DeleteOrigin moves the origin to the trash.
 func (or *OriginRepository) DeleteOrigin(ctx context.Context, id string) error {
 
 	objectId, err := primitive.ObjectIDFromHex(id)
@@ -117,15 +179,105 @@ func (or *OriginRepository) DeleteOrigin(ctx context.Context, id string) error {
 		return err
 	}
 
-	result, err := or.db.DeleteOne(ctx, bson.M{"_id": objectId})
+	result, err := or.db.UpdateOne(ctx,
+		bson.M{"_id": objectId, "deleted_at": notDeleted},
+		bson.M{"$set": bson.M{"deleted_at": time.Now()}},
+	)
 
 	if err != nil {
 		return err
 	}
 
-	if result.DeletedCount == 0 {
+	if result.MatchedCount == 0 {
 		return domain.ErrDataNotFound
 	}
 
 	return nil
 }
+
+// GetDeletedOrigins returns the user's origins in the trash, most recently
+// deleted first.
+func (or *OriginRepository) GetDeletedOrigins(ctx context.Context, userId string) ([]domain.Origin, error) {
+
+	var origins []domain.Origin
+
+	filter := bson.M{
+		"user_id":    userId,
+		"deleted_at": bson.M{"$exists": true},
+	}
+
+	findOptions := options.Find().SetSort(bson.D{{Key: "deleted_at", Value: -1}})
+
+	cursor, err := or.db.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var origin domain.Origin
+		if err := cursor.Decode(&origin); err != nil {
+			return nil, err
+		}
+		origins = append(origins, origin)
+	}
+
+	return origins, nil
+}
+
+// GetDeletedOriginById returns the origin if it is in the trash.
+func (or *OriginRepository) GetDeletedOriginById(ctx context.Context, id string) (*domain.Origin, error) {
+
+	var origin domain.Origin
+	objectId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{"_id": objectId, "deleted_at": bson.M{"$exists": true}}
+
+	if err := or.db.FindOne(ctx, filter).Decode(&origin); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return &origin, nil
+}
+
+// RestoreOrigin takes the origin out of the trash.
+func (or *OriginRepository) RestoreOrigin(ctx context.Context, id string) error {
+
+	objectId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	result, err := or.db.UpdateOne(ctx,
+		bson.M{"_id": objectId, "deleted_at": bson.M{"$exists": true}},
+		bson.M{"$unset": bson.M{"deleted_at": ""}},
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return domain.ErrDataNotFound
+	}
+
+	return nil
+}
+
+// PurgeOrigins permanently deletes the origins moved to the trash before the
+// given time and returns how many were deleted.
+func (or *OriginRepository) PurgeOrigins(ctx context.Context, before time.Time) (int64, error) {
+
+	result, err := or.db.DeleteMany(ctx, bson.M{"deleted_at": bson.M{"$lt": before}})
+	if err != nil {
+		return 0, err
+	}
+
+	return result.DeletedCount, nil
+}
@@ -0,0 +1,204 @@
+package repository
+
+import (
+	"context"
+	"personal-finance/adapter/config"
+	"personal-finance/core/domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type OutboxRepository struct {
+	db *mongo.Collection
+}
+
+func NewOutboxRepository(db *mongo.Database, config *config.DB) *OutboxRepository {
+	return &OutboxRepository{
+		db.Collection(config.Outbox),
+	}
+}
+
+func (or *OutboxRepository) GetMessages(ctx context.Context, status string, page, limit uint64) ([]domain.OutboxMessage, int64, int, error) {
+
+	var messages []domain.OutboxMessage
+
+	filter := bson.M{}
+	if status != "" {
+		filter["status"] = status
+	}
+
+	total, err := or.db.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+
+	cursor, err := or.db.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var message domain.OutboxMessage
+		if err := cursor.Decode(&message); err != nil {
+			return nil, 0, 0, err
+		}
+		messages = append(messages, message)
+	}
+
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+
+	return messages, total, totalPages, nil
+}
+
+func (or *OutboxRepository) GetMessageById(ctx context.Context, id string) (*domain.OutboxMessage, error) {
+
+	var message domain.OutboxMessage
+
+	objectId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, domain.ErrDataNotFound
+	}
+
+	if err := or.db.FindOne(ctx, bson.M{"_id": objectId}).Decode(&message); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return &message, nil
+}
+
+// EnqueueMessage inserts the message, or returns the one already queued under
+// the same idempotency key.
+func (or *OutboxRepository) EnqueueMessage(ctx context.Context, message *domain.OutboxMessage) (*domain.OutboxMessage, error) {
+
+	result, err := or.db.InsertOne(ctx, message)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			var existing domain.OutboxMessage
+			if err := or.db.FindOne(ctx, bson.M{"idempotency_key": message.IdempotencyKey}).Decode(&existing); err != nil {
+				return nil, err
+			}
+			return &existing, nil
+		}
+		return nil, err
+	}
+
+	message.ID = result.InsertedID.(primitive.ObjectID).Hex()
+
+	return message, nil
+}
+
+// ClaimDueMessage atomically takes the oldest pending message due at now and
+// leases it until leaseUntil, counting the attempt. A worker that dies while
+// sending leaves the message to be retried once the lease expires.
+func (or *OutboxRepository) ClaimDueMessage(ctx context.Context, now time.Time, leaseUntil time.Time) (*domain.OutboxMessage, error) {
+
+	var message domain.OutboxMessage
+
+	filter := bson.M{
+		"status":          domain.OutboxPending,
+		"next_attempt_at": bson.M{"$lte": now},
+	}
+
+	update := bson.M{
+		"$set": bson.M{"next_attempt_at": leaseUntil, "updated_at": now},
+		"$inc": bson.M{"attempts": 1},
+	}
+
+	updateOptions := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "next_attempt_at", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	if err := or.db.FindOneAndUpdate(ctx, filter, update, updateOptions).Decode(&message); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return &message, nil
+}
+
+func (or *OutboxRepository) UpdateMessageStatus(ctx context.Context, message *domain.OutboxMessage) error {
+
+	objectId, err := primitive.ObjectIDFromHex(message.ID)
+	if err != nil {
+		return err
+	}
+
+	update := bson.M{"$set": bson.M{
+		"status":          message.Status,
+		"last_error":      message.LastError,
+		"next_attempt_at": message.NextAttemptAt,
+		"sent_at":         message.SentAt,
+		"updated_at":      message.UpdatedAt,
+	}}
+
+	_, err = or.db.UpdateOne(ctx, bson.M{"_id": objectId}, update)
+
+	return err
+}
+
+// RequeueMessage makes a message that was not sent due again at now with a
+// fresh attempt budget.
+func (or *OutboxRepository) RequeueMessage(ctx context.Context, id string, now time.Time) (*domain.OutboxMessage, error) {
+
+	var message domain.OutboxMessage
+
+	objectId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, domain.ErrDataNotFound
+	}
+
+	filter := bson.M{
+		"_id":    objectId,
+		"status": bson.M{"$ne": domain.OutboxSent},
+	}
+
+	update := bson.M{"$set": bson.M{
+		"status":          domain.OutboxPending,
+		"attempts":        0,
+		"last_error":      "",
+		"next_attempt_at": now,
+		"updated_at":      now,
+	}}
+
+	updateOptions := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	if err := or.db.FindOneAndUpdate(ctx, filter, update, updateOptions).Decode(&message); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return &message, nil
+}
+
+func (or *OutboxRepository) CreateIndexes(ctx context.Context) error {
+
+	_, err := or.db.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "idempotency_key", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}},
+		},
+	})
+
+	return err
+}
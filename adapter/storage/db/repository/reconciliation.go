@@ -0,0 +1,232 @@
+package repository
+
+import (
+	"context"
+	"personal-finance/adapter/config"
+	"personal-finance/core/domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ReconciliationRepository struct {
+	db           *mongo.Collection
+	transactions *mongo.Collection
+	origins      *mongo.Collection
+}
+
+func NewReconciliationRepository(db *mongo.Database, config *config.DB) *ReconciliationRepository {
+	return &ReconciliationRepository{
+		db.Collection(config.Reconciliations),
+		db.Collection(config.Transactions),
+		db.Collection(config.Origin),
+	}
+}
+
+// CreateIndexes allows a single open reconciliation per origin.
+func (rr *ReconciliationRepository) CreateIndexes(ctx context.Context) error {
+
+	_, err := rr.db.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "origin_id", Value: 1}},
+		Options: options.Index().
+			SetUnique(true).
+			SetPartialFilterExpression(bson.M{"status": domain.ReconciliationOpen}),
+	})
+
+	return err
+}
+
+func (rr *ReconciliationRepository) GetReconciliationById(ctx context.Context, id string) (*domain.Reconciliation, error) {
+
+	var reconciliation domain.Reconciliation
+	objectId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := rr.db.FindOne(ctx, bson.M{"_id": objectId}).Decode(&reconciliation); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return &reconciliation, nil
+}
+
+func (rr *ReconciliationRepository) GetOpenReconciliation(ctx context.Context, originId string) (*domain.Reconciliation, error) {
+
+	var reconciliation domain.Reconciliation
+
+	filter := bson.M{"origin_id": originId, "status": domain.ReconciliationOpen}
+
+	if err := rr.db.FindOne(ctx, filter).Decode(&reconciliation); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return &reconciliation, nil
+}
+
+func (rr *ReconciliationRepository) CreateReconciliation(ctx context.Context, reconciliation *domain.Reconciliation) (*domain.Reconciliation, error) {
+
+	result, err := rr.db.InsertOne(ctx, reconciliation)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, domain.ErrConflictingData
+		}
+		return nil, err
+	}
+
+	reconciliation.ID = result.InsertedID.(primitive.ObjectID).Hex()
+
+	return reconciliation, nil
+}
+
+func (rr *ReconciliationRepository) UpdateReconciliation(ctx context.Context, reconciliation *domain.Reconciliation) error {
+
+	objectId, err := primitive.ObjectIDFromHex(reconciliation.ID)
+	if err != nil {
+		return err
+	}
+
+	update := bson.M{"$set": bson.M{
+		"status":        reconciliation.Status,
+		"adjustment":    reconciliation.Adjustment,
+		"adjustment_id": reconciliation.AdjustmentId,
+		"completed_at":  reconciliation.CompletedAt,
+	}}
+
+	result, err := rr.db.UpdateOne(ctx, bson.M{"_id": objectId}, update)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return domain.ErrDataNotFound
+	}
+
+	return nil
+}
+
+func (rr *ReconciliationRepository) DeleteReconciliation(ctx context.Context, id string) error {
+
+	objectId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	result, err := rr.db.DeleteOne(ctx, bson.M{"_id": objectId})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return domain.ErrDataNotFound
+	}
+
+	return nil
+}
+
+// GetUnreconciledTransactions returns the origin's transactions not locked by
+// a previous reconciliation, oldest first.
+func (rr *ReconciliationRepository) GetUnreconciledTransactions(ctx context.Context, originId string) ([]domain.Transaction, error) {
+
+	var transactions []domain.Transaction
+
+	filter := bson.M{
+		"origin_id":  originId,
+		"reconciled": bson.M{"$ne": true},
+		"deleted_at": notDeleted,
+	}
+
+	findOptions := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+
+	cursor, err := rr.transactions.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var transaction domain.Transaction
+		if err := cursor.Decode(&transaction); err != nil {
+			return nil, err
+		}
+		transactions = append(transactions, transaction)
+	}
+
+	return transactions, nil
+}
+
+// SetCleared flags the given transactions of the origin as cleared or not,
+// leaving reconciled ones untouched.
+func (rr *ReconciliationRepository) SetCleared(ctx context.Context, originId string, ids []string, cleared bool) error {
+
+	objectIds := make([]primitive.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		objectId, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return err
+		}
+		objectIds = append(objectIds, objectId)
+	}
+
+	filter := bson.M{
+		"_id":        bson.M{"$in": objectIds},
+		"origin_id":  originId,
+		"reconciled": bson.M{"$ne": true},
+		"deleted_at": notDeleted,
+	}
+
+	_, err := rr.transactions.UpdateMany(ctx, filter, bson.M{"$set": bson.M{"cleared": cleared}})
+
+	return err
+}
+
+// MarkReconciled locks the origin's cleared transactions dated on or before
+// the day through.
+func (rr *ReconciliationRepository) MarkReconciled(ctx context.Context, originId string, through time.Time) error {
+
+	filter := bson.M{
+		"origin_id":  originId,
+		"cleared":    true,
+		"reconciled": bson.M{"$ne": true},
+		"deleted_at": notDeleted,
+		"created_at": bson.M{"$lt": domain.SnapshotDay(through).AddDate(0, 0, 1)},
+	}
+
+	_, err := rr.transactions.UpdateMany(ctx, filter, bson.M{"$set": bson.M{"reconciled": true}})
+
+	return err
+}
+
+func (rr *ReconciliationRepository) SetOriginReconciled(ctx context.Context, originId string, through time.Time, balance float64) error {
+
+	objectId, err := primitive.ObjectIDFromHex(originId)
+	if err != nil {
+		return err
+	}
+
+	update := bson.M{"$set": bson.M{
+		"reconciled_through": through,
+		"reconciled_balance": balance,
+	}}
+
+	result, err := rr.origins.UpdateOne(ctx, bson.M{"_id": objectId}, update)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return domain.ErrDataNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"personal-finance/adapter/config"
+	"personal-finance/core/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ReportDeliveryRepository struct {
+	deliveries *mongo.Collection
+	runs       *mongo.Collection
+}
+
+func NewReportDeliveryRepository(db *mongo.Database, config *config.DB) *ReportDeliveryRepository {
+	return &ReportDeliveryRepository{
+		db.Collection(config.ReportDeliveries),
+		db.Collection(config.ReportRuns),
+	}
+}
+
+func (rr *ReportDeliveryRepository) GetDelivery(ctx context.Context, userId string, period string) (*domain.ReportDelivery, error) {
+
+	var delivery domain.ReportDelivery
+
+	if err := rr.deliveries.FindOne(ctx, bson.M{"user_id": userId, "period": period}).Decode(&delivery); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return &delivery, nil
+}
+
+func (rr *ReportDeliveryRepository) GetDeliveriesByPeriod(ctx context.Context, period string) ([]domain.ReportDelivery, error) {
+
+	var deliveries []domain.ReportDelivery
+
+	findOptions := options.Find().SetSort(bson.D{{Key: "updated_at", Value: -1}})
+
+	cursor, err := rr.deliveries.Find(ctx, bson.M{"period": period}, findOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var delivery domain.ReportDelivery
+		if err := cursor.Decode(&delivery); err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, nil
+}
+
+// SaveDelivery upserts the delivery keyed by (user_id, period).
+func (rr *ReportDeliveryRepository) SaveDelivery(ctx context.Context, delivery *domain.ReportDelivery) error {
+
+	filter := bson.M{"user_id": delivery.UserId, "period": delivery.Period}
+
+	update := bson.M{"$set": bson.M{
+		"status":     delivery.Status,
+		"attempts":   delivery.Attempts,
+		"error":      delivery.Error,
+		"sent_at":    delivery.SentAt,
+		"updated_at": delivery.UpdatedAt,
+	}}
+
+	_, err := rr.deliveries.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+
+	return err
+}
+
+func (rr *ReportDeliveryRepository) GetRun(ctx context.Context, period string) (*domain.ReportRun, error) {
+
+	var run domain.ReportRun
+
+	if err := rr.runs.FindOne(ctx, bson.M{"period": period}).Decode(&run); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return &run, nil
+}
+
+// SaveRun upserts the run keyed by its period.
+func (rr *ReportDeliveryRepository) SaveRun(ctx context.Context, run *domain.ReportRun) error {
+
+	update := bson.M{"$set": bson.M{
+		"status":      run.Status,
+		"sent":        run.Sent,
+		"failed":      run.Failed,
+		"skipped":     run.Skipped,
+		"started_at":  run.StartedAt,
+		"finished_at": run.FinishedAt,
+	}}
+
+	_, err := rr.runs.UpdateOne(ctx, bson.M{"period": run.Period}, update, options.Update().SetUpsert(true))
+
+	return err
+}
+
+func (rr *ReportDeliveryRepository) CreateIndexes(ctx context.Context) error {
+
+	_, err := rr.deliveries.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "period", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = rr.runs.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "period", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+
+	return err
+}
@@ -0,0 +1,226 @@
+package repository
+
+import (
+	"context"
+	"personal-finance/adapter/config"
+	"personal-finance/core/domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type SnapshotRepository struct {
+	db           *mongo.Collection
+	origins      *mongo.Collection
+	transactions *mongo.Collection
+}
+
+func NewSnapshotRepository(db *mongo.Database, config *config.DB) *SnapshotRepository {
+	return &SnapshotRepository{
+		db.Collection(config.Snapshots),
+		db.Collection(config.Origin),
+		db.Collection(config.Transactions),
+	}
+}
+
+// SaveSnapshot upserts the snapshot of the origin for its date.
+func (sr *SnapshotRepository) SaveSnapshot(ctx context.Context, snapshot *domain.BalanceSnapshot) error {
+
+	filter := bson.M{"origin_id": snapshot.OriginId, "date": snapshot.Date}
+
+	update := bson.M{"$set": bson.M{
+		"user_id":    snapshot.UserId,
+		"balance":    snapshot.Balance,
+		"updated_at": snapshot.UpdatedAt,
+	}}
+
+	_, err := sr.db.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+
+	return err
+}
+
+// InsertMissingSnapshots inserts the snapshots of the days an origin has none
+// yet, leaving the recorded ones untouched, and returns how many it inserted.
+func (sr *SnapshotRepository) InsertMissingSnapshots(ctx context.Context, snapshots []domain.BalanceSnapshot) (int, error) {
+
+	if len(snapshots) == 0 {
+		return 0, nil
+	}
+
+	models := make([]mongo.WriteModel, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"origin_id": snapshot.OriginId, "date": snapshot.Date}).
+			SetUpdate(bson.M{"$setOnInsert": bson.M{
+				"user_id":    snapshot.UserId,
+				"balance":    snapshot.Balance,
+				"updated_at": snapshot.UpdatedAt,
+			}}).
+			SetUpsert(true))
+	}
+
+	result, err := sr.db.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		return 0, err
+	}
+
+	return int(result.UpsertedCount), nil
+}
+
+// SnapshotAllOrigins records the current balance of every origin as its
+// snapshot of day, merging the origins collection into the snapshots one.
+// Liabilities are negated, as in domain.Origin.NetWorth.
+func (sr *SnapshotRepository) SnapshotAllOrigins(ctx context.Context, day time.Time) error {
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"deleted_at": notDeleted}}},
+		{{Key: "$project", Value: bson.M{
+			"_id":       0,
+			"user_id":   1,
+			"origin_id": bson.M{"$toString": "$_id"},
+			"date":      day,
+			"balance": bson.M{"$cond": bson.A{
+				bson.M{"$in": bson.A{"$kind", bson.A{domain.OriginCreditCard, domain.OriginLoan}}},
+				bson.M{"$multiply": bson.A{"$total", -1}},
+				"$total",
+			}},
+			"updated_at": "$$NOW",
+		}}},
+		{{Key: "$merge", Value: bson.M{
+			"into":           sr.db.Name(),
+			"on":             bson.A{"origin_id", "date"},
+			"whenMatched":    "merge",
+			"whenNotMatched": "insert",
+		}}},
+	}
+
+	cursor, err := sr.origins.Aggregate(ctx, pipeline)
+	if err != nil {
+		return err
+	}
+
+	return cursor.Close(ctx)
+}
+
+func (sr *SnapshotRepository) GetSnapshots(ctx context.Context, userId string, from, to time.Time) ([]domain.BalanceSnapshot, error) {
+
+	filter := bson.M{
+		"user_id": userId,
+		"date":    bson.M{"$gte": from, "$lt": to},
+	}
+
+	findOptions := options.Find().SetSort(bson.D{{Key: "date", Value: 1}})
+
+	cursor, err := sr.db.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeSnapshots(ctx, cursor)
+}
+
+// GetLatestSnapshotsBefore returns, for each origin of the user, its last
+// snapshot dated before day.
+func (sr *SnapshotRepository) GetLatestSnapshotsBefore(ctx context.Context, userId string, day time.Time) ([]domain.BalanceSnapshot, error) {
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"user_id": userId, "date": bson.M{"$lt": day}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "date", Value: -1}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":      "$origin_id",
+			"snapshot": bson.M{"$first": "$$ROOT"},
+		}}},
+		{{Key: "$replaceRoot", Value: bson.M{"newRoot": "$snapshot"}}},
+	}
+
+	cursor, err := sr.db.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeSnapshots(ctx, cursor)
+}
+
+// GetDailyOriginNet sums, per origin and day, the income minus the outputs of
+// the user's transactions.
+func (sr *SnapshotRepository) GetDailyOriginNet(ctx context.Context, userId string) ([]domain.DailyOriginNet, error) {
+
+	var nets []domain.DailyOriginNet
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"user_id":    userId,
+			"origin_id":  bson.M{"$nin": bson.A{"", nil}},
+			"deleted_at": notDeleted,
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id": bson.M{
+				"origin_id": "$origin_id",
+				"date":      bson.M{"$dateTrunc": bson.M{"date": "$created_at", "unit": "day"}},
+			},
+			"net": bson.M{"$sum": bson.M{"$cond": bson.A{
+				bson.M{"$eq": bson.A{"$type", "Income"}},
+				"$amount",
+				bson.M{"$multiply": bson.A{"$amount", -1}},
+			}}},
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"_id":       0,
+			"origin_id": "$_id.origin_id",
+			"date":      "$_id.date",
+			"net":       1,
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "origin_id", Value: 1}, {Key: "date", Value: 1}}}},
+	}
+
+	cursor, err := sr.transactions.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var net domain.DailyOriginNet
+		if err := cursor.Decode(&net); err != nil {
+			return nil, err
+		}
+		nets = append(nets, net)
+	}
+
+	return nets, nil
+}
+
+func (sr *SnapshotRepository) CreateIndexes(ctx context.Context) error {
+
+	_, err := sr.db.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "origin_id", Value: 1}, {Key: "date", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "date", Value: 1}},
+		},
+	})
+
+	return err
+}
+
+func decodeSnapshots(ctx context.Context, cursor *mongo.Cursor) ([]domain.BalanceSnapshot, error) {
+
+	var snapshots []domain.BalanceSnapshot
+
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var snapshot domain.BalanceSnapshot
+		if err := cursor.Decode(&snapshot); err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+
+	return snapshots, nil
+}
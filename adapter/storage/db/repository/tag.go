@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+	"personal-finance/adapter/config"
+	"personal-finance/core/domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type TagRepository struct {
+	db *mongo.Collection
+}
+
+func NewTagRepository(db *mongo.Database, config *config.DB) *TagRepository {
+	return &TagRepository{
+		db.Collection(config.Tags),
+	}
+}
+
+// CreateIndexes makes tag names unique per user, so concurrent creates and
+// renames to the same name fail with ErrConflictingData.
+func (tr *TagRepository) CreateIndexes(ctx context.Context) error {
+
+	_, err := tr.db.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "name", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+
+	return err
+}
+
+func (tr *TagRepository) GetTagsByUserId(ctx context.Context, userId string) ([]domain.Tag, error) {
+
+	var tags []domain.Tag
+
+	filter := bson.M{
+		"user_id": userId,
+	}
+
+	findOptions := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+
+	cursor, err := tr.db.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var tag domain.Tag
+		if err := cursor.Decode(&tag); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+
+	return tags, nil
+}
+
+func (tr *TagRepository) GetTagById(ctx context.Context, id string) (*domain.Tag, error) {
+
+	var tag domain.Tag
+	objectId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tr.db.FindOne(ctx, bson.M{"_id": objectId}).Decode(&tag); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return &tag, nil
+}
+
+func (tr *TagRepository) GetTagByName(ctx context.Context, userId string, name string) (*domain.Tag, error) {
+
+	var tag domain.Tag
+
+	if err := tr.db.FindOne(ctx, bson.M{"user_id": userId, "name": name}).Decode(&tag); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return &tag, nil
+}
+
+func (tr *TagRepository) CreateTag(ctx context.Context, tag *domain.Tag) (*domain.Tag, error) {
+
+	result, err := tr.db.InsertOne(ctx, tag)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, domain.ErrConflictingData
+		}
+		return nil, err
+	}
+
+	tag.ID = result.InsertedID.(primitive.ObjectID).Hex()
+
+	return tag, nil
+}
+
+func (tr *TagRepository) UpdateTag(ctx context.Context, id string, updatedTag *domain.Tag) (*domain.Tag, error) {
+
+	objectId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	update := bson.M{"$set": bson.M{
+		"name":        updatedTag.Name,
+		"color":       updatedTag.Color,
+		"description": updatedTag.Description,
+		"updated_at":  time.Now(),
+	}}
+
+	result, err := tr.db.UpdateOne(ctx, bson.M{"_id": objectId}, update)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, domain.ErrConflictingData
+		}
+		return nil, err
+	}
+
+	if result.MatchedCount == 0 {
+		return nil, domain.ErrDataNotFound
+	}
+
+	updatedTag.ID = id
+
+	return updatedTag, nil
+}
+
+func (tr *TagRepository) DeleteTag(ctx context.Context, id string) error {
+
+	objectId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	result, err := tr.db.DeleteOne(ctx, bson.M{"_id": objectId})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return domain.ErrDataNotFound
+	}
+
+	return nil
+}
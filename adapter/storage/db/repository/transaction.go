@@ -2,13 +2,18 @@ package repository
 
 import (
 	"context"
+	"encoding/base64"
 	"personal-finance/adapter/config"
 	"personal-finance/core/domain"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type TransactionRepository struct {
@@ -27,162 +32,322 @@ func (tr *TransactionRepository) GetTransactionsByUserId(
 	userId string,
 ) ([]domain.Transaction, int64, int, error) {
 
-	pipeline := mongo.Pipeline{
+	// Filter by user
+	match := bson.M{
+		"user_id":    userId,
+		"deleted_at": notDeleted,
+	}
 
-		// Filter by usuario
-		{{Key: "$match", Value: bson.D{{Key: "user_id", Value: userId}}}},
+	return tr.findPage(ctx, match, newestFirst, page, limit)
+}
 
-		// origin_id to ObjectId
-		{{Key: "$addFields", Value: bson.D{
-			{Key: "origin_object_id", Value: bson.D{
-				{Key: "$cond", Value: bson.A{
-					bson.D{{Key: "$and", Value: bson.A{
-						bson.D{{Key: "$ifNull", Value: bson.A{"$origin_id", false}}},
-						bson.D{{Key: "$ne", Value: bson.A{"$origin_id", ""}}},
-					}}},
-					bson.D{{Key: "$toObjectId", Value: "$origin_id"}},
-					nil,
-				}},
-			}},
-		}}},
+func (tr *TransactionRepository) GetTransactionsByDate(
+	ctx context.Context,
+	userId string,
+	page, limit uint64,
+	year int,
+	month int,
+) ([]domain.Transaction, int64, int, error) {
 
-		// Left join origins
-		{{Key: "$lookup", Value: bson.D{
-			{Key: "from", Value: "origins"},
-			{Key: "localField", Value: "origin_object_id"},
-			{Key: "foreignField", Value: "_id"},
-			{Key: "as", Value: "origin"},
-		}}},
+	startDate, endDate := domain.MonthRange(year, month)
 
-		// Array origin to object
-		{{Key: "$addFields", Value: bson.D{
-			{Key: "origin", Value: bson.D{
-				{Key: "$cond", Value: bson.A{
-					bson.D{{Key: "$gt", Value: bson.A{bson.D{{Key: "$size", Value: "$origin"}}, 0}}},
-					bson.D{{Key: "$arrayElemAt", Value: bson.A{"$origin", 0}}},
-					nil,
-				}},
-			}},
-		}}},
+	// Filter by user and date
+	match := bson.M{
+		"user_id": userId,
+		"created_at": bson.M{
+			"$gte": startDate,
+			"$lt":  endDate,
+		},
+		"deleted_at": notDeleted,
+	}
 
-		// Clear temporal field
-		{{Key: "$unset", Value: "origin_object_id"}},
+	return tr.findPage(ctx, match, newestFirst, page, limit)
+}
 
-		// Order by creation date DESC
-		{{Key: "$sort", Value: bson.D{{Key: "created_at", Value: -1}}}},
+func (tr *TransactionRepository) GetTransactionsByType(
+	ctx context.Context,
+	userId string,
+	page, limit uint64,
+	transaction_type string,
+) ([]domain.Transaction, int64, int, error) {
 
-		// Pagination
-		{{Key: "$skip", Value: int64((page - 1) * limit)}},
-		{{Key: "$limit", Value: int64(limit)}},
+	// Filter by user and type
+	match := bson.M{
+		"user_id":    userId,
+		"type":       transaction_type,
+		"deleted_at": notDeleted,
 	}
 
-	return tr.findtransactionUsingPipeline(ctx, pipeline, userId, limit)
+	return tr.findPage(ctx, match, newestFirst, page, limit)
 }
 
-func (tr *TransactionRepository) GetTransactionsByDate(
+func (tr *TransactionRepository) GetTransactionsByTags(
 	ctx context.Context,
 	userId string,
 	page, limit uint64,
-	year int,
-	month int,
+	tags []string,
+	matchAll bool,
 ) ([]domain.Transaction, int64, int, error) {
 
-	var dateFilter bson.M
+	tagOperator := "$in"
+	if matchAll {
+		tagOperator = "$all"
+	}
 
-	if month == 0 {
+	// Filter by user and tags
+	match := bson.M{
+		"user_id":    userId,
+		"tags":       bson.M{tagOperator: tags},
+		"deleted_at": notDeleted,
+	}
 
-		startDate := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
-		endDate := time.Date(year+1, 1, 1, 0, 0, 0, 0, time.UTC)
+	return tr.findPage(ctx, match, newestFirst, page, limit)
+}
 
-		dateFilter = bson.M{
-			"user_id": userId,
-			"created_at": bson.M{
-				"$gte": startDate,
-				"$lt":  endDate,
-			},
-		}
-	} else {
+// SearchTransactions runs every criterion of filter as a single $match so
+// the count and the page are computed over the same set of documents.
+func (tr *TransactionRepository) SearchTransactions(
+	ctx context.Context,
+	filter domain.TransactionFilter,
+	page, limit uint64,
+) ([]domain.Transaction, int64, int, error) {
 
-		startDate := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
-		endDate := startDate.AddDate(0, 1, 0)
+	sortField, ok := domain.TransactionSortFields[filter.SortField]
+	if !ok {
+		sortField = "created_at"
+	}
 
-		dateFilter = bson.M{
-			"user_id": userId,
-			"created_at": bson.M{
-				"$gte": startDate,
-				"$lt":  endDate,
-			},
-		}
+	sortDirection := -1
+	if filter.SortAscending {
+		sortDirection = 1
 	}
 
-	pipeline := mongo.Pipeline{
+	// _id keeps documents with equal sort values stable across pages
+	sort := bson.D{
+		{Key: sortField, Value: sortDirection},
+		{Key: "_id", Value: sortDirection},
+	}
 
-		// Filter by user and date
-		{{Key: "$match", Value: dateFilter}},
+	return tr.findPage(ctx, buildSearchMatch(filter), sort, page, limit)
+}
 
-		// origin_id to ObjectId
-		{{Key: "$addFields", Value: bson.D{
-			{Key: "origin_object_id", Value: bson.D{
-				{Key: "$cond", Value: bson.A{
-					bson.D{{Key: "$and", Value: bson.A{
-						bson.D{{Key: "$ifNull", Value: bson.A{"$origin_id", false}}},
-						bson.D{{Key: "$ne", Value: bson.A{"$origin_id", ""}}},
-					}}},
-					bson.D{{Key: "$toObjectId", Value: "$origin_id"}},
-					nil,
-				}},
-			}},
-		}}},
+// GetTransactionsByCursor pages through the transactions matching filter by
+// (created_at, _id) keyset instead of $skip, so every page costs the same
+// regardless of its depth. An empty cursor starts from the first document;
+// the returned cursor is empty once the last page has been reached.
+func (tr *TransactionRepository) GetTransactionsByCursor(
+	ctx context.Context,
+	filter domain.TransactionFilter,
+	cursor string,
+	limit uint64,
+) ([]domain.Transaction, int64, string, error) {
 
-		// Left join origins
-		{{Key: "$lookup", Value: bson.D{
-			{Key: "from", Value: "origins"},
-			{Key: "localField", Value: "origin_object_id"},
-			{Key: "foreignField", Value: "_id"},
-			{Key: "as", Value: "origin"},
-		}}},
+	var transactions []domain.Transaction
 
-		// Array origin to object
-		{{Key: "$addFields", Value: bson.D{
-			{Key: "origin", Value: bson.D{
-				{Key: "$cond", Value: bson.A{
-					bson.D{{Key: "$gt", Value: bson.A{bson.D{{Key: "$size", Value: "$origin"}}, 0}}},
-					bson.D{{Key: "$arrayElemAt", Value: bson.A{"$origin", 0}}},
-					nil,
-				}},
+	if sortField, ok := domain.TransactionSortFields[filter.SortField]; ok && sortField != "created_at" {
+		return nil, 0, "", domain.ErrCursorSort
+	}
+
+	match := buildSearchMatch(filter)
+
+	total, err := tr.db.CountDocuments(ctx, match)
+	if err != nil {
+		return nil, 0, "", err
+	}
+
+	sortDirection := -1
+	keysetOperator := "$lt"
+	if filter.SortAscending {
+		sortDirection = 1
+		keysetOperator = "$gt"
+	}
+
+	pageMatch := match
+	if cursor != "" {
+		createdAt, objectId, err := decodeCursor(cursor)
+		if err != nil {
+			return nil, 0, "", domain.ErrInvalidCursor
+		}
+
+		pageMatch = bson.M{"$and": bson.A{
+			match,
+			bson.M{"$or": bson.A{
+				bson.M{"created_at": bson.M{keysetOperator: createdAt}},
+				bson.M{"created_at": createdAt, "_id": bson.M{keysetOperator: objectId}},
 			}},
+		}}
+	}
+
+	pipeline := mongo.Pipeline{
+
+		// Filter by every criterion and resume after the cursor
+		{{Key: "$match", Value: pageMatch}},
+
+		// Order by creation date, _id breaks ties
+		{{Key: "$sort", Value: bson.D{
+			{Key: "created_at", Value: sortDirection},
+			{Key: "_id", Value: sortDirection},
 		}}},
 
-		// Clear temporal field
-		{{Key: "$unset", Value: "origin_object_id"}},
+		// One extra document tells whether there is a next page
+		{{Key: "$limit", Value: int64(limit + 1)}},
+	}
 
-		// Order by creation date DESC
-		{{Key: "$sort", Value: bson.D{{Key: "created_at", Value: -1}}}},
+	pipeline = append(pipeline, originLookupStages()...)
 
-		// Pagination
-		{{Key: "$skip", Value: int64((page - 1) * limit)}},
-		{{Key: "$limit", Value: int64(limit)}},
+	transactions, err = tr.aggregateTransactions(ctx, pipeline)
+	if err != nil {
+		return nil, 0, "", err
 	}
 
-	return tr.findtransactionUsingPipeline(ctx, pipeline, userId, limit)
+	nextCursor := ""
+	if uint64(len(transactions)) > limit {
+		transactions = transactions[:limit]
+		last := transactions[len(transactions)-1]
+		nextCursor = encodeCursor(last.CreatedAt, last.ID)
+	}
+
+	return transactions, total, nextCursor, nil
 }
 
-func (tr *TransactionRepository) GetTransactionsByType(
+// TextSearchTransactions matches query against the transaction text index
+// and orders the results by relevance. The index ignores case and diacritics,
+// so "farmacia" also finds "Farmácia".
+func (tr *TransactionRepository) TextSearchTransactions(
 	ctx context.Context,
 	userId string,
+	query string,
 	page, limit uint64,
-	transaction_type string,
-) ([]domain.Transaction, int64, int, error) {
+) ([]domain.TextSearchResult, int64, int, error) {
 
-	typeFilter := bson.M{
-		"user_id": userId,
-		"type":    transaction_type,
+	var results []domain.TextSearchResult
+
+	match := bson.M{
+		"user_id":    userId,
+		"$text":      bson.M{"$search": query},
+		"deleted_at": notDeleted,
+	}
+
+	total, err := tr.db.CountDocuments(ctx, match)
+	if err != nil {
+		return nil, 0, 0, err
 	}
 
 	pipeline := mongo.Pipeline{
 
-		// Filter by user and date
-		{{Key: "$match", Value: typeFilter}},
+		// Filter by user and text
+		{{Key: "$match", Value: match}},
+
+		// Order by relevance, newest first on ties
+		{{Key: "$addFields", Value: bson.D{{Key: "score", Value: bson.D{{Key: "$meta", Value: "textScore"}}}}}},
+		{{Key: "$sort", Value: bson.D{
+			{Key: "score", Value: -1},
+			{Key: "created_at", Value: -1},
+			{Key: "_id", Value: -1},
+		}}},
+
+		// Pagination
+		{{Key: "$skip", Value: int64((page - 1) * limit)}},
+		{{Key: "$limit", Value: int64(limit)}},
+	}
+
+	pipeline = append(pipeline, originLookupStages()...)
+
+	cursor, err := tr.db.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var scored struct {
+			domain.Transaction `bson:",inline"`
+			Score              float64 `bson:"score"`
+		}
+		if err := cursor.Decode(&scored); err != nil {
+			return nil, 0, 0, err
+		}
+		results = append(results, domain.TextSearchResult{
+			Transaction: scored.Transaction,
+			Score:       scored.Score,
+		})
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, 0, 0, err
+	}
+
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+
+	return results, total, totalPages, nil
+}
+
+func buildSearchMatch(filter domain.TransactionFilter) bson.M {
+
+	match := bson.M{
+		"user_id":    filter.UserId,
+		"deleted_at": notDeleted,
+	}
+
+	if filter.From != nil || filter.To != nil {
+		dateFilter := bson.M{}
+		if filter.From != nil {
+			dateFilter["$gte"] = *filter.From
+		}
+		if filter.To != nil {
+			dateFilter["$lt"] = *filter.To
+		}
+		match["created_at"] = dateFilter
+	}
+
+	if filter.Type != "" {
+		match["type"] = filter.Type
+	}
+
+	if len(filter.OriginIds) > 0 {
+		match["origin_id"] = bson.M{"$in": filter.OriginIds}
+	}
+
+	if filter.Category != "" {
+		match["output_category"] = filter.Category
+	}
+
+	if filter.Payee != "" {
+		match["person_business"] = bson.M{"$regex": regexp.QuoteMeta(filter.Payee), "$options": "i"}
+	}
+
+	if filter.MinAmount != nil || filter.MaxAmount != nil {
+		amountFilter := bson.M{}
+		if filter.MinAmount != nil {
+			amountFilter["$gte"] = *filter.MinAmount
+		}
+		if filter.MaxAmount != nil {
+			amountFilter["$lte"] = *filter.MaxAmount
+		}
+		match["amount"] = amountFilter
+	}
+
+	if filter.Text != "" {
+		match["description"] = bson.M{"$regex": regexp.QuoteMeta(filter.Text), "$options": "i"}
+	}
+
+	if len(filter.Tags) > 0 {
+		tagOperator := "$all"
+		if filter.AnyTag {
+			tagOperator = "$in"
+		}
+		match["tags"] = bson.M{tagOperator: filter.Tags}
+	}
+
+	return match
+}
+
+// originLookupStages embeds the referenced origin document as "origin",
+// leaving it null when the transaction has no origin.
+func originLookupStages() mongo.Pipeline {
+
+	return mongo.Pipeline{
 
 		// origin_id to ObjectId
 		{{Key: "$addFields", Value: bson.D{
@@ -219,16 +384,50 @@ func (tr *TransactionRepository) GetTransactionsByType(
 
 		// Clear temporal field
 		{{Key: "$unset", Value: "origin_object_id"}},
+	}
+}
 
-		// Order by creation date DESC
-		{{Key: "$sort", Value: bson.D{{Key: "created_at", Value: -1}}}},
-
-		// Pagination
-		{{Key: "$skip", Value: int64((page - 1) * limit)}},
-		{{Key: "$limit", Value: int64(limit)}},
+// CreateIndexes creates the compound indexes backing the listing, filter and
+// search queries. Every one is prefixed by user_id, which all queries match on,
+// except the sparse deleted_at one backing the trash purge.
+func (tr *TransactionRepository) CreateIndexes(ctx context.Context) error {
+
+	indexes := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "type", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "origin_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "output_category", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "amount", Value: -1}}},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "tags", Value: 1}}},
+		{Keys: bson.D{{Key: "deleted_at", Value: 1}}, Options: options.Index().SetSparse(true)},
+
+		// A single text index per collection is allowed. "none" disables
+		// stemming and stop words, which would only suit one of our languages.
+		{
+			Keys: bson.D{
+				{Key: "description", Value: "text"},
+				{Key: "person_business", Value: "text"},
+				{Key: "subject", Value: "text"},
+				{Key: "output_category", Value: "text"},
+				{Key: "tags", Value: "text"},
+			},
+			Options: options.Index().
+				SetName("transaction_text").
+				SetDefaultLanguage("none").
+				SetLanguageOverride("text_language").
+				SetWeights(bson.D{
+					{Key: "description", Value: 5},
+					{Key: "person_business", Value: 5},
+					{Key: "output_category", Value: 3},
+					{Key: "subject", Value: 2},
+					{Key: "tags", Value: 2},
+				}),
+		},
 	}
 
-	return tr.findtransactionUsingPipeline(ctx, pipeline, userId, limit)
+	_, err := tr.db.Indexes().CreateMany(ctx, indexes)
+
+	return err
 }
 
 func (tr *TransactionRepository) GetTransactionById(ctx context.Context, id string) (*domain.Transaction, error) {
@@ -242,45 +441,11 @@ func (tr *TransactionRepository) GetTransactionById(ctx context.Context, id stri
 	pipeline := mongo.Pipeline{
 
 		// Filter by id
-		{{Key: "$match", Value: bson.D{{Key: "_id", Value: objectId}}}},
-
-		// origin_id to ObjectId
-		{{Key: "$addFields", Value: bson.D{
-			{Key: "origin_object_id", Value: bson.D{
-				{Key: "$cond", Value: bson.A{
-					bson.D{{Key: "$and", Value: bson.A{
-						bson.D{{Key: "$ifNull", Value: bson.A{"$origin_id", false}}},
-						bson.D{{Key: "$ne", Value: bson.A{"$origin_id", ""}}},
-					}}},
-					bson.D{{Key: "$toObjectId", Value: "$origin_id"}},
-					nil,
-				}},
-			}},
-		}}},
-
-		// Left join origins
-		{{Key: "$lookup", Value: bson.D{
-			{Key: "from", Value: "origins"},
-			{Key: "localField", Value: "origin_object_id"},
-			{Key: "foreignField", Value: "_id"},
-			{Key: "as", Value: "origin"},
-		}}},
-
-		// Array origin to object
-		{{Key: "$addFields", Value: bson.D{
-			{Key: "origin", Value: bson.D{
-				{Key: "$cond", Value: bson.A{
-					bson.D{{Key: "$gt", Value: bson.A{bson.D{{Key: "$size", Value: "$origin"}}, 0}}},
-					bson.D{{Key: "$arrayElemAt", Value: bson.A{"$origin", 0}}},
-					nil,
-				}},
-			}},
-		}}},
-
-		// Clear temporal field
-		{{Key: "$unset", Value: "origin_object_id"}},
+		{{Key: "$match", Value: bson.D{{Key: "_id", Value: objectId}, {Key: "deleted_at", Value: notDeleted}}}},
 	}
 
+	pipeline = append(pipeline, originLookupStages()...)
+
 	cursor, err := tr.db.Aggregate(ctx, pipeline)
 	if err != nil {
 		return nil, err
@@ -321,6 +486,19 @@ func (tr *TransactionRepository) UpdateTransaction(ctx context.Context, id strin
 
 	update := bson.M{"$set": updatedTransaction}
 
+	// $set leaves out the omitempty fields left empty, so clearing them
+	// has to be explicit
+	unset := bson.M{}
+	if len(updatedTransaction.Tags) == 0 {
+		unset["tags"] = ""
+	}
+	if updatedTransaction.IncomeSource == "" {
+		unset["income_source"] = ""
+	}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+
 	result, err := tr.db.UpdateOne(ctx, bson.M{"_id": objectId}, update)
 	if err != nil {
 		return nil, err
@@ -335,6 +513,87 @@ func (tr *TransactionRepository) UpdateTransaction(ctx context.Context, id strin
 	return updatedTransaction, nil
 }
 
+// RenameTag replaces oldName with newName in every transaction of the user,
+// dropping the old label where the new one is already present.
+func (tr *TransactionRepository) RenameTag(ctx context.Context, userId string, oldName string, newName string) error {
+
+	_, err := tr.db.UpdateMany(ctx,
+		bson.M{"user_id": userId, "tags": bson.M{"$all": bson.A{oldName, newName}}},
+		bson.M{"$pull": bson.M{"tags": oldName}},
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = tr.db.UpdateMany(ctx,
+		bson.M{"user_id": userId, "tags": oldName},
+		bson.M{"$set": bson.M{"tags.$": newName}},
+	)
+
+	return err
+}
+
+func (tr *TransactionRepository) RemoveTag(ctx context.Context, userId string, name string) error {
+
+	_, err := tr.db.UpdateMany(ctx,
+		bson.M{"user_id": userId, "tags": name},
+		bson.M{"$pull": bson.M{"tags": name}},
+	)
+
+	return err
+}
+
+// CountTransactionsByOriginId counts the transactions on the origin, those in
+// the trash included, since they can still be restored onto it.
+func (tr *TransactionRepository) CountTransactionsByOriginId(ctx context.Context, originId string) (int64, error) {
+
+	return tr.db.CountDocuments(ctx, bson.M{"origin_id": originId})
+}
+
+// GetTransactionsByOriginId returns every transaction on the origin, those in
+// the trash included, oldest first.
+func (tr *TransactionRepository) GetTransactionsByOriginId(ctx context.Context, originId string) ([]domain.Transaction, error) {
+
+	var transactions []domain.Transaction
+
+	findOptions := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}})
+
+	cursor, err := tr.db.Find(ctx, bson.M{"origin_id": originId}, findOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var transaction domain.Transaction
+		if err := cursor.Decode(&transaction); err != nil {
+			return nil, err
+		}
+		transactions = append(transactions, transaction)
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	return transactions, nil
+}
+
+// ReassignTransactions moves every transaction of an origin to another one,
+// those in the trash included.
+func (tr *TransactionRepository) ReassignTransactions(ctx context.Context, fromOriginId string, toOriginId string) error {
+
+	_, err := tr.db.UpdateMany(ctx,
+		bson.M{"origin_id": fromOriginId},
+		bson.M{"$set": bson.M{"origin_id": toOriginId, "updated_at": time.Now()}},
+	)
+
+	return err
+}
+
+// DeleteTransaction moves the transaction to the trash. It is left out of
+// every listing until it is restored or purged.
 func (tr *TransactionRepository) DeleteTransaction(ctx context.Context, id string) error {
 
 	objectId, err := primitive.ObjectIDFromHex(id)
@@ -343,8 +602,130 @@ func (tr *TransactionRepository) DeleteTransaction(ctx context.Context, id strin
 		return err
 	}
 
-	result, err := tr.db.DeleteOne(ctx, bson.M{"_id": objectId})
+	result, err := tr.db.UpdateOne(ctx,
+		bson.M{"_id": objectId, "deleted_at": notDeleted},
+		bson.M{"$set": bson.M{"deleted_at": time.Now()}},
+	)
+
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return domain.ErrDataNotFound
+	}
+
+	return nil
+}
+
+// GetDeletedTransactions returns one page of the user's transactions in the
+// trash, most recently deleted first.
+func (tr *TransactionRepository) GetDeletedTransactions(
+	ctx context.Context,
+	userId string,
+	page, limit uint64,
+) ([]domain.Transaction, int64, int, error) {
+
+	match := bson.M{
+		"user_id":    userId,
+		"deleted_at": bson.M{"$exists": true},
+	}
+
+	sort := bson.D{
+		{Key: "deleted_at", Value: -1},
+		{Key: "_id", Value: -1},
+	}
+
+	return tr.findPage(ctx, match, sort, page, limit)
+}
+
+// GetDeletedTransactionById returns the transaction if it is in the trash.
+func (tr *TransactionRepository) GetDeletedTransactionById(ctx context.Context, id string) (*domain.Transaction, error) {
+
+	var transaction domain.Transaction
+	objectId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{"_id": objectId, "deleted_at": bson.M{"$exists": true}}
+
+	if err := tr.db.FindOne(ctx, filter).Decode(&transaction); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return &transaction, nil
+}
+
+// RestoreTransaction takes the transaction out of the trash.
+func (tr *TransactionRepository) RestoreTransaction(ctx context.Context, id string) error {
+
+	objectId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	result, err := tr.db.UpdateOne(ctx,
+		bson.M{"_id": objectId, "deleted_at": bson.M{"$exists": true}},
+		bson.M{"$unset": bson.M{"deleted_at": ""}},
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return domain.ErrDataNotFound
+	}
+
+	return nil
+}
+
+// GetPurgeableTransactionIds returns the ids of the transactions moved to the
+// trash before the given time.
+func (tr *TransactionRepository) GetPurgeableTransactionIds(ctx context.Context, before time.Time) ([]string, error) {
+
+	var ids []string
+
+	filter := bson.M{"deleted_at": bson.M{"$lt": before}}
+
+	cursor, err := tr.db.Find(ctx, filter, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var document struct {
+			ID primitive.ObjectID `bson:"_id"`
+		}
+		if err := cursor.Decode(&document); err != nil {
+			return nil, err
+		}
+		ids = append(ids, document.ID.Hex())
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// PurgeTransaction permanently deletes the transaction if it was moved to the
+// trash before the given time. A transaction restored in the meantime is kept
+// and ErrDataNotFound returned.
+func (tr *TransactionRepository) PurgeTransaction(ctx context.Context, id string, before time.Time) error {
+
+	objectId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
 
+	result, err := tr.db.DeleteOne(ctx, bson.M{"_id": objectId, "deleted_at": bson.M{"$lt": before}})
 	if err != nil {
 		return err
 	}
@@ -366,23 +747,49 @@ func (tr *TransactionRepository) DeleteTransactionsByUserId(ctx context.Context,
 	return nil
 }
 
-func (tr *TransactionRepository) findtransactionUsingPipeline(
+// findPage returns one page of the documents matching match in sort order,
+// along with the count of every matching document. Origins are joined after
+// $skip/$limit so the lookup only runs for the documents of the page.
+func (tr *TransactionRepository) findPage(
 	ctx context.Context,
-	pipeline mongo.Pipeline,
-	userId string,
-	limit uint64,
+	match bson.M,
+	sort bson.D,
+	page, limit uint64,
 ) ([]domain.Transaction, int64, int, error) {
 
-	var transactions []domain.Transaction
+	total, err := tr.db.CountDocuments(ctx, match)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$sort", Value: sort}},
+
+		// Pagination
+		{{Key: "$skip", Value: int64((page - 1) * limit)}},
+		{{Key: "$limit", Value: int64(limit)}},
+	}
+
+	pipeline = append(pipeline, originLookupStages()...)
 
-	total, err := tr.db.CountDocuments(ctx, bson.M{"user_id": userId})
+	transactions, err := tr.aggregateTransactions(ctx, pipeline)
 	if err != nil {
 		return nil, 0, 0, err
 	}
 
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+
+	return transactions, total, totalPages, nil
+}
+
+func (tr *TransactionRepository) aggregateTransactions(ctx context.Context, pipeline mongo.Pipeline) ([]domain.Transaction, error) {
+
+	var transactions []domain.Transaction
+
 	cursor, err := tr.db.Aggregate(ctx, pipeline)
 	if err != nil {
-		return nil, 0, 0, err
+		return nil, err
 	}
 
 	defer cursor.Close(ctx)
@@ -390,16 +797,56 @@ func (tr *TransactionRepository) findtransactionUsingPipeline(
 	for cursor.Next(ctx) {
 		var transaction domain.Transaction
 		if err := cursor.Decode(&transaction); err != nil {
-			return nil, 0, 0, err
+			return nil, err
 		}
 		transactions = append(transactions, transaction)
 	}
 
 	if err := cursor.Err(); err != nil {
-		return nil, 0, 0, err
+		return nil, err
 	}
 
-	totalPages := int((total + int64(limit) - 1) / int64(limit))
+	return transactions, nil
+}
 
-	return transactions, total, totalPages, nil
+// notDeleted matches the documents that are not in the trash.
+var notDeleted = bson.M{"$exists": false}
+
+var newestFirst = bson.D{
+	{Key: "created_at", Value: -1},
+	{Key: "_id", Value: -1},
+}
+
+// encodeCursor packs the keyset of the last document of a page into an
+// opaque token: base64url("<created_at unix ms>:<_id hex>").
+func encodeCursor(createdAt time.Time, id string) string {
+
+	raw := strconv.FormatInt(createdAt.UnixMilli(), 10) + ":" + id
+
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(cursor string) (time.Time, primitive.ObjectID, error) {
+
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, primitive.NilObjectID, err
+	}
+
+	millis, id, found := strings.Cut(string(raw), ":")
+	if !found {
+		return time.Time{}, primitive.NilObjectID, domain.ErrInvalidCursor
+	}
+
+	unixMillis, err := strconv.ParseInt(millis, 10, 64)
+	if err != nil {
+		return time.Time{}, primitive.NilObjectID, err
+	}
+
+	objectId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return time.Time{}, primitive.NilObjectID, err
+	}
+
+	return time.UnixMilli(unixMillis).UTC(), objectId, nil
 }
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestCursor_RoundTrip(t *testing.T) {
+	createdAt := time.Date(2024, time.March, 9, 14, 30, 15, 123_000_000, time.UTC)
+	objectId := primitive.NewObjectID()
+
+	decodedAt, decodedId, err := decodeCursor(encodeCursor(createdAt, objectId.Hex()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !decodedAt.Equal(createdAt) {
+		t.Errorf("expected %v, got %v", createdAt, decodedAt)
+	}
+	if decodedId != objectId {
+		t.Errorf("expected %s, got %s", objectId.Hex(), decodedId.Hex())
+	}
+}
+
+func TestCursor_TruncatesToMilliseconds(t *testing.T) {
+	createdAt := time.Date(2024, time.March, 9, 14, 30, 15, 123_456_789, time.UTC)
+
+	decodedAt, _, err := decodeCursor(encodeCursor(createdAt, primitive.NewObjectID().Hex()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if want := createdAt.Truncate(time.Millisecond); !decodedAt.Equal(want) {
+		t.Errorf("expected %v, got %v", want, decodedAt)
+	}
+}
+
+func TestDecodeCursor_RejectsMalformed(t *testing.T) {
+	encode := func(raw string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(raw))
+	}
+	objectId := primitive.NewObjectID().Hex()
+
+	cases := map[string]string{
+		"not base64":        "!!not-base64!!",
+		"padded base64":     base64.URLEncoding.EncodeToString([]byte("1700000000000:" + objectId)),
+		"missing separator": encode("1700000000000" + objectId),
+		"non numeric time":  encode("yesterday:" + objectId),
+		"invalid id":        encode("1700000000000:not-an-object-id"),
+		"empty id":          encode("1700000000000:"),
+		"empty":             "",
+	}
+
+	for name, cursor := range cases {
+		if _, _, err := decodeCursor(cursor); err == nil {
+			t.Errorf("%s: expected an error for cursor %q", name, cursor)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package imaging
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"io"
+
+	_ "image/gif"
+	_ "image/png"
+)
+
+// Thumbnail decodes the image in r, crops it around its center to the
+// target aspect ratio and scales it to width x height, mirroring the
+// "c_fill,w_300,h_300" Cloudinary transformation. The result is JPEG encoded.
+func Thumbnail(r io.Reader, width, height int) ([]byte, error) {
+
+	source, _, err := image.Decode(r)
+	if err != nil {
+		return nil, err
+	}
+
+	cropped := cropToAspect(source.Bounds(), width, height)
+
+	// Flatten transparent sources onto white before dropping the alpha channel.
+	canvas := image.NewRGBA(image.Rect(0, 0, cropped.Dx(), cropped.Dy()))
+	draw.Draw(canvas, canvas.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(canvas, canvas.Bounds(), source, cropped.Min, draw.Over)
+
+	resized := scaleBilinear(canvas, width, height)
+
+	var buffer bytes.Buffer
+	if err := jpeg.Encode(&buffer, resized, &jpeg.Options{Quality: 85}); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+func cropToAspect(bounds image.Rectangle, width, height int) image.Rectangle {
+
+	sourceWidth, sourceHeight := bounds.Dx(), bounds.Dy()
+
+	cropWidth := sourceWidth
+	cropHeight := sourceWidth * height / width
+
+	if cropHeight > sourceHeight {
+		cropHeight = sourceHeight
+		cropWidth = sourceHeight * width / height
+	}
+
+	x0 := bounds.Min.X + (sourceWidth-cropWidth)/2
+	y0 := bounds.Min.Y + (sourceHeight-cropHeight)/2
+
+	return image.Rect(x0, y0, x0+cropWidth, y0+cropHeight)
+}
+
+func scaleBilinear(source *image.RGBA, width, height int) *image.RGBA {
+
+	destination := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	sourceWidth, sourceHeight := source.Bounds().Dx(), source.Bounds().Dy()
+	xRatio := float64(sourceWidth) / float64(width)
+	yRatio := float64(sourceHeight) / float64(height)
+
+	for y := 0; y < height; y++ {
+
+		sy := (float64(y)+0.5)*yRatio - 0.5
+		y0 := clamp(int(sy), 0, sourceHeight-1)
+		y1 := clamp(y0+1, 0, sourceHeight-1)
+		dy := sy - float64(y0)
+		if dy < 0 {
+			dy = 0
+		}
+
+		for x := 0; x < width; x++ {
+
+			sx := (float64(x)+0.5)*xRatio - 0.5
+			x0 := clamp(int(sx), 0, sourceWidth-1)
+			x1 := clamp(x0+1, 0, sourceWidth-1)
+			dx := sx - float64(x0)
+			if dx < 0 {
+				dx = 0
+			}
+
+			offset := destination.PixOffset(x, y)
+			for channel := 0; channel < 4; channel++ {
+				top := lerp(source.Pix[source.PixOffset(x0, y0)+channel], source.Pix[source.PixOffset(x1, y0)+channel], dx)
+				bottom := lerp(source.Pix[source.PixOffset(x0, y1)+channel], source.Pix[source.PixOffset(x1, y1)+channel], dx)
+				destination.Pix[offset+channel] = uint8(top + (bottom-top)*dy + 0.5)
+			}
+		}
+	}
+
+	return destination
+}
+
+func lerp(a, b uint8, t float64) float64 {
+	return float64(a) + (float64(b)-float64(a))*t
+}
+
+func clamp(value, min, max int) int {
+	if value < min {
+		return min
+	}
+	if value > max {
+		return max
+	}
+	return value
+}
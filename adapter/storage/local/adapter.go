@@ -0,0 +1,225 @@
+package local
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"personal-finance/adapter/config"
+	"personal-finance/adapter/storage/imaging"
+	"personal-finance/core/domain"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// profileImageUrlTTL bounds the signed URL of a profile image, which is stored
+// on the user and only refreshed when the image is uploaded again.
+const profileImageUrlTTL = 365 * 24 * time.Hour
+
+// StorageAdapter keeps images and attachments on the local disk. Files are
+// served by the API through URLs carrying an expiry and an HMAC of both the
+// public id and that expiry.
+type StorageAdapter struct {
+	root       string
+	publicUrl  string
+	signingKey []byte
+	urlTTL     time.Duration
+}
+
+func New(config *config.ImageCloud) (*StorageAdapter, error) {
+
+	if config.SigningKey == "" {
+		return nil, errors.New("local storage requires STORAGE_SIGNING_KEY")
+	}
+
+	if err := os.MkdirAll(config.LocalPath, 0o755); err != nil {
+		return nil, err
+	}
+
+	return &StorageAdapter{
+		root:       config.LocalPath,
+		publicUrl:  strings.TrimSuffix(config.PublicUrl, "/"),
+		signingKey: []byte(config.SigningKey),
+		urlTTL:     config.UrlTTL,
+	}, nil
+}
+
+func (sa *StorageAdapter) UploadImageFromFile(ctx context.Context, file multipart.File, userId string) (*domain.Image, error) {
+
+	file.Seek(0, 0)
+
+	thumbnail, err := imaging.Thumbnail(file, 300, 300)
+	if err != nil {
+		return nil, err
+	}
+
+	publicId := "profile_images/user_" + userId + ".jpg"
+
+	if err := sa.write(publicId, bytes.NewReader(thumbnail)); err != nil {
+		return nil, err
+	}
+
+	imageResponse := domain.Image{
+		SecureUrl: sa.signedUrl(publicId, time.Now().Add(profileImageUrlTTL)),
+		PublicId:  publicId,
+	}
+
+	return &imageResponse, nil
+}
+
+func (sa *StorageAdapter) DeleteImage(ctx context.Context, publicId string) error {
+	return sa.remove(publicId)
+}
+
+func (sa *StorageAdapter) UploadFile(ctx context.Context, file multipart.File, folder string) (*domain.StoredFile, error) {
+
+	file.Seek(0, 0)
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, err
+	}
+
+	name, err := randomName()
+	if err != nil {
+		return nil, err
+	}
+
+	if extensions, _ := mime.ExtensionsByType(http.DetectContentType(head[:n])); len(extensions) > 0 {
+		name += extensions[0]
+	}
+
+	publicId := path.Join(folder, name)
+
+	file.Seek(0, 0)
+
+	if err := sa.write(publicId, file); err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(sa.filePath(publicId))
+	if err != nil {
+		return nil, err
+	}
+
+	storedFile := domain.StoredFile{
+		SecureUrl: sa.signedUrl(publicId, time.Now().Add(sa.urlTTL)),
+		PublicId:  publicId,
+		Size:      info.Size(),
+	}
+
+	return &storedFile, nil
+}
+
+func (sa *StorageAdapter) DeleteFile(ctx context.Context, publicId string) error {
+	return sa.remove(publicId)
+}
+
+func (sa *StorageAdapter) FileUrl(ctx context.Context, publicId string) (string, error) {
+	return sa.signedUrl(publicId, time.Now().Add(sa.urlTTL)), nil
+}
+
+func (sa *StorageAdapter) OpenSignedFile(ctx context.Context, publicId string, expires string, signature string) (io.ReadSeekCloser, error) {
+
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return nil, domain.ErrForbidden
+	}
+
+	if !hmac.Equal([]byte(signature), []byte(sa.sign(publicId, expires))) {
+		return nil, domain.ErrForbidden
+	}
+
+	if time.Now().Unix() > expiresAt {
+		return nil, domain.ErrForbidden
+	}
+
+	file, err := os.Open(sa.filePath(publicId))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return file, nil
+}
+
+func (sa *StorageAdapter) write(publicId string, content io.Reader) error {
+
+	destination := sa.filePath(publicId)
+
+	if err := os.MkdirAll(filepath.Dir(destination), 0o755); err != nil {
+		return err
+	}
+
+	file, err := os.Create(destination)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(file, content); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+func (sa *StorageAdapter) remove(publicId string) error {
+
+	err := os.Remove(sa.filePath(publicId))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+// filePath resolves the public id inside the storage root; cleaning it as an
+// absolute path first keeps ".." segments from escaping the root.
+func (sa *StorageAdapter) filePath(publicId string) string {
+	return filepath.Join(sa.root, filepath.FromSlash(path.Clean("/"+publicId)))
+}
+
+func (sa *StorageAdapter) signedUrl(publicId string, expiresAt time.Time) string {
+
+	escaped := (&url.URL{Path: publicId}).EscapedPath()
+	expires := strconv.FormatInt(expiresAt.Unix(), 10)
+
+	return sa.publicUrl + "/v1/files/" + escaped + "?expires=" + expires + "&signature=" + sa.sign(publicId, expires)
+}
+
+// sign covers the expiry along with the public id so a link cannot be
+// extended by editing its expires parameter.
+func (sa *StorageAdapter) sign(publicId string, expires string) string {
+
+	mac := hmac.New(sha256.New, sa.signingKey)
+	mac.Write([]byte(publicId + "\n" + expires))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func randomName() (string, error) {
+
+	buffer := make([]byte, 16)
+	if _, err := rand.Read(buffer); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(buffer), nil
+}
@@ -0,0 +1,156 @@
+package local
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"personal-finance/adapter/config"
+	"personal-finance/core/domain"
+)
+
+func newTestAdapter(t *testing.T, root string, signingKey string) *StorageAdapter {
+	adapter, err := New(&config.ImageCloud{
+		LocalPath:  root,
+		PublicUrl:  "https://api.example.com/",
+		SigningKey: signingKey,
+		UrlTTL:     time.Minute,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return adapter
+}
+
+// tempFile writes content to a temporary file, which satisfies multipart.File.
+func tempFile(t *testing.T, content []byte) *os.File {
+	file, err := os.CreateTemp(t.TempDir(), "upload")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(func() { file.Close() })
+	if _, err := file.Write(content); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return file
+}
+
+// signedQuery returns the expires and signature parameters of a signed URL.
+func signedQuery(t *testing.T, signedUrl string) (string, string) {
+	parsed, err := url.Parse(signedUrl)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return parsed.Query().Get("expires"), parsed.Query().Get("signature")
+}
+
+// tamper changes the first character of a signature.
+func tamper(signature string) string {
+	if strings.HasPrefix(signature, "A") {
+		return "B" + signature[1:]
+	}
+	return "A" + signature[1:]
+}
+
+func TestFilePath_StaysInsideRoot(t *testing.T) {
+	root := t.TempDir()
+	adapter := newTestAdapter(t, root, "key")
+
+	for _, publicId := range []string{
+		"../../etc/passwd",
+		"/../../etc/passwd",
+		"attachments/../../../etc/passwd",
+		"attachments/user_1/../../../../secret",
+	} {
+		resolved := adapter.filePath(publicId)
+		if !strings.HasPrefix(resolved, root+string(filepath.Separator)) {
+			t.Errorf("%q resolved outside the root: %s", publicId, resolved)
+		}
+	}
+}
+
+func TestUploadFile_WritesInsideRootDespiteTraversalFolder(t *testing.T) {
+	root := t.TempDir()
+	adapter := newTestAdapter(t, root, "key")
+
+	stored, err := adapter.UploadFile(context.Background(), tempFile(t, []byte("hello")), "../../outside")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(root, "outside", filepath.Base(stored.PublicId))); err != nil {
+		t.Errorf("expected the file kept under the root: %v", err)
+	}
+}
+
+func TestOpenSignedFile_ServesFreshLink(t *testing.T) {
+	adapter := newTestAdapter(t, t.TempDir(), "key")
+
+	stored, err := adapter.UploadFile(context.Background(), tempFile(t, []byte("%PDF-1.4 receipt")), "attachments/user_1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	fileUrl, err := adapter.FileUrl(context.Background(), stored.PublicId)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(fileUrl, "https://api.example.com/v1/files/"+stored.PublicId+"?") {
+		t.Errorf("unexpected url %q", fileUrl)
+	}
+
+	expires, signature := signedQuery(t, fileUrl)
+	file, err := adapter.OpenSignedFile(context.Background(), stored.PublicId, expires, signature)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer file.Close()
+
+	content, _ := io.ReadAll(file)
+	if string(content) != "%PDF-1.4 receipt" {
+		t.Errorf("unexpected content %q", content)
+	}
+}
+
+func TestOpenSignedFile_RejectsBadSignatures(t *testing.T) {
+	root := t.TempDir()
+	adapter := newTestAdapter(t, root, "key")
+	foreign := newTestAdapter(t, root, "other-key")
+
+	stored, err := adapter.UploadFile(context.Background(), tempFile(t, []byte("hello")), "attachments/user_1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	other, err := adapter.UploadFile(context.Background(), tempFile(t, []byte("other")), "attachments/user_2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expires, signature := signedQuery(t, stored.SecureUrl)
+	_, foreignSignature := signedQuery(t, foreign.signedUrl(stored.PublicId, time.Now().Add(time.Minute)))
+	extended := strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)
+	expired := strconv.FormatInt(time.Now().Add(-time.Minute).Unix(), 10)
+
+	cases := map[string][3]string{
+		"empty signature":    {stored.PublicId, expires, ""},
+		"tampered signature": {stored.PublicId, expires, tamper(signature)},
+		"foreign key":        {stored.PublicId, expires, foreignSignature},
+		"other file":         {other.PublicId, expires, signature},
+		"extended expiry":    {stored.PublicId, extended, signature},
+		"missing expiry":     {stored.PublicId, "", signature},
+		"expired link":       {stored.PublicId, expired, adapter.sign(stored.PublicId, expired)},
+		"traversal to file":  {"attachments/user_2/../user_1/" + filepath.Base(stored.PublicId), expires, signature},
+	}
+
+	for name, args := range cases {
+		if _, err := adapter.OpenSignedFile(context.Background(), args[0], args[1], args[2]); err != domain.ErrForbidden {
+			t.Errorf("%s: expected ErrForbidden, got %v", name, err)
+		}
+	}
+}
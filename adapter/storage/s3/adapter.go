@@ -0,0 +1,205 @@
+package s3
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"path"
+	"personal-finance/adapter/config"
+	"personal-finance/adapter/storage/imaging"
+	"personal-finance/core/domain"
+	"strings"
+	"time"
+)
+
+// StorageAdapter stores images and attachments in an S3-compatible bucket
+// (AWS S3, MinIO, ...) using path-style requests signed with SigV4.
+// Attachments are read through presigned URLs valid for urlTTL; profile
+// images keep the plain object URL since it is stored on the user.
+type StorageAdapter struct {
+	client    *http.Client
+	endpoint  *url.URL
+	publicUrl string
+	bucket    string
+	region    string
+	accessKey string
+	secretKey string
+	urlTTL    time.Duration
+}
+
+func New(config *config.ImageCloud) (*StorageAdapter, error) {
+
+	if config.S3Endpoint == "" || config.S3Bucket == "" {
+		return nil, errors.New("s3 storage requires S3_ENDPOINT and S3_BUCKET")
+	}
+
+	endpoint, err := url.Parse(strings.TrimSuffix(config.S3Endpoint, "/"))
+	if err != nil {
+		return nil, err
+	}
+
+	publicUrl := strings.TrimSuffix(config.PublicUrl, "/")
+	if publicUrl == "" {
+		publicUrl = endpoint.String() + "/" + config.S3Bucket
+	}
+
+	return &StorageAdapter{
+		client:    &http.Client{Timeout: 30 * time.Second},
+		endpoint:  endpoint,
+		publicUrl: publicUrl,
+		bucket:    config.S3Bucket,
+		region:    config.S3Region,
+		accessKey: config.S3AccessKey,
+		secretKey: config.S3SecretKey,
+		urlTTL:    config.UrlTTL,
+	}, nil
+}
+
+func (sa *StorageAdapter) UploadImageFromFile(ctx context.Context, file multipart.File, userId string) (*domain.Image, error) {
+
+	file.Seek(0, 0)
+
+	thumbnail, err := imaging.Thumbnail(file, 300, 300)
+	if err != nil {
+		return nil, err
+	}
+
+	key := "profile_images/user_" + userId + ".jpg"
+
+	if err := sa.putObject(ctx, key, thumbnail, "image/jpeg"); err != nil {
+		return nil, err
+	}
+
+	imageResponse := domain.Image{
+		SecureUrl: sa.objectUrl(key),
+		PublicId:  key,
+	}
+
+	return &imageResponse, nil
+}
+
+func (sa *StorageAdapter) DeleteImage(ctx context.Context, publicId string) error {
+	return sa.deleteObject(ctx, publicId)
+}
+
+func (sa *StorageAdapter) UploadFile(ctx context.Context, file multipart.File, folder string) (*domain.StoredFile, error) {
+
+	file.Seek(0, 0)
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+
+	contentType := http.DetectContentType(content)
+
+	name, err := randomName()
+	if err != nil {
+		return nil, err
+	}
+
+	if extensions, _ := mime.ExtensionsByType(contentType); len(extensions) > 0 {
+		name += extensions[0]
+	}
+
+	key := path.Join(folder, name)
+
+	if err := sa.putObject(ctx, key, content, contentType); err != nil {
+		return nil, err
+	}
+
+	storedFile := domain.StoredFile{
+		SecureUrl: sa.presignedUrl(key),
+		PublicId:  key,
+		Size:      int64(len(content)),
+	}
+
+	return &storedFile, nil
+}
+
+func (sa *StorageAdapter) DeleteFile(ctx context.Context, publicId string) error {
+	return sa.deleteObject(ctx, publicId)
+}
+
+func (sa *StorageAdapter) FileUrl(ctx context.Context, publicId string) (string, error) {
+	return sa.presignedUrl(publicId), nil
+}
+
+func (sa *StorageAdapter) putObject(ctx context.Context, key string, content []byte, contentType string) error {
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, sa.requestUrl(key), bytes.NewReader(content))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", contentType)
+	signRequest(req, hashHex(content), sa.accessKey, sa.secretKey, sa.region, time.Now())
+
+	return sa.do(req)
+}
+
+func (sa *StorageAdapter) deleteObject(ctx context.Context, key string) error {
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, sa.requestUrl(key), nil)
+	if err != nil {
+		return err
+	}
+
+	signRequest(req, emptyPayloadHash, sa.accessKey, sa.secretKey, sa.region, time.Now())
+
+	return sa.do(req)
+}
+
+func (sa *StorageAdapter) do(req *http.Request) error {
+
+	response, err := sa.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(response.Body, 1024))
+		return fmt.Errorf("s3 %s %s: %s: %s", req.Method, req.URL.Path, response.Status, body)
+	}
+
+	return nil
+}
+
+func (sa *StorageAdapter) requestUrl(key string) string {
+	return sa.keyUrl(key).String()
+}
+
+func (sa *StorageAdapter) keyUrl(key string) *url.URL {
+
+	objectUrl := *sa.endpoint
+	objectUrl.Path = path.Join("/", objectUrl.Path, sa.bucket, key)
+
+	return &objectUrl
+}
+
+func (sa *StorageAdapter) presignedUrl(key string) string {
+	return presignUrl(sa.keyUrl(key), sa.accessKey, sa.secretKey, sa.region, time.Now(), sa.urlTTL)
+}
+
+func (sa *StorageAdapter) objectUrl(key string) string {
+	return sa.publicUrl + "/" + (&url.URL{Path: key}).EscapedPath()
+}
+
+func randomName() (string, error) {
+
+	buffer := make([]byte, 16)
+	if _, err := rand.Read(buffer); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(buffer), nil
+}
@@ -0,0 +1,284 @@
+package s3
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"personal-finance/adapter/config"
+)
+
+const (
+	testAccessKey = "minio-access"
+	testSecretKey = "minio-secret"
+	testRegion    = "us-east-1"
+	testBucket    = "finance"
+)
+
+// fakeS3 is a minimal MinIO-style stand-in: it verifies the SigV4 signature
+// of every request and keeps PUT objects in memory.
+type fakeS3 struct {
+	mu           sync.Mutex
+	objects      map[string][]byte
+	contentTypes map[string]string
+}
+
+func newFakeS3() *fakeS3 {
+	return &fakeS3{
+		objects:      map[string][]byte{},
+		contentTypes: map[string]string{},
+	}
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	if r.Method == http.MethodGet {
+		f.servePresigned(w, r)
+		return
+	}
+
+	body, _ := io.ReadAll(r.Body)
+
+	if hashHex(body) != r.Header.Get("X-Amz-Content-Sha256") {
+		http.Error(w, "XAmzContentSHA256Mismatch", http.StatusBadRequest)
+		return
+	}
+
+	amzDate, err := time.Parse(amzDateFormat, r.Header.Get("X-Amz-Date"))
+	if err != nil {
+		http.Error(w, "AccessDenied", http.StatusForbidden)
+		return
+	}
+
+	verify, _ := http.NewRequest(r.Method, "http://"+r.Host+r.URL.RequestURI(), nil)
+	verify.Header.Set("Content-Type", r.Header.Get("Content-Type"))
+	if verify.Header.Get("Content-Type") == "" {
+		verify.Header.Del("Content-Type")
+	}
+	signRequest(verify, r.Header.Get("X-Amz-Content-Sha256"), testAccessKey, testSecretKey, testRegion, amzDate)
+
+	if verify.Header.Get("Authorization") != r.Header.Get("Authorization") {
+		http.Error(w, "SignatureDoesNotMatch", http.StatusForbidden)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodPut:
+		f.objects[r.URL.Path] = body
+		f.contentTypes[r.URL.Path] = r.Header.Get("Content-Type")
+		w.WriteHeader(http.StatusOK)
+	case http.MethodDelete:
+		delete(f.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// servePresigned answers GETs authorized by query string, as S3 does for
+// presigned URLs, rejecting bad signatures and expired links.
+func (f *fakeS3) servePresigned(w http.ResponseWriter, r *http.Request) {
+
+	query := r.URL.Query()
+
+	amzDate, err := time.Parse(amzDateFormat, query.Get("X-Amz-Date"))
+	if err != nil {
+		http.Error(w, "AccessDenied", http.StatusForbidden)
+		return
+	}
+	expires, err := strconv.Atoi(query.Get("X-Amz-Expires"))
+	if err != nil {
+		http.Error(w, "AccessDenied", http.StatusForbidden)
+		return
+	}
+
+	objectUrl, _ := url.Parse("http://" + r.Host + r.URL.EscapedPath())
+	expected, _ := url.Parse(presignUrl(objectUrl, testAccessKey, testSecretKey, testRegion, amzDate, time.Duration(expires)*time.Second))
+
+	if expected.Query().Get("X-Amz-Signature") != query.Get("X-Amz-Signature") {
+		http.Error(w, "SignatureDoesNotMatch", http.StatusForbidden)
+		return
+	}
+	if time.Now().After(amzDate.Add(time.Duration(expires) * time.Second)) {
+		http.Error(w, "AccessDenied: Request has expired", http.StatusForbidden)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	object, ok := f.objects[r.URL.Path]
+	if !ok {
+		http.Error(w, "NoSuchKey", http.StatusNotFound)
+		return
+	}
+	w.Write(object)
+}
+
+func newTestAdapter(t *testing.T, server *httptest.Server, secretKey string) *StorageAdapter {
+	adapter, err := New(&config.ImageCloud{
+		S3Endpoint:  server.URL,
+		S3Region:    testRegion,
+		S3Bucket:    testBucket,
+		S3AccessKey: testAccessKey,
+		S3SecretKey: secretKey,
+		UrlTTL:      time.Minute,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return adapter
+}
+
+// tempFile writes content to a temporary file, which satisfies multipart.File.
+func tempFile(t *testing.T, content []byte) *os.File {
+	file, err := os.CreateTemp(t.TempDir(), "upload")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(func() { file.Close() })
+	if _, err := file.Write(content); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return file
+}
+
+func TestUploadFile_StoresObjectAndDeleteRemovesIt(t *testing.T) {
+	fake := newFakeS3()
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	adapter := newTestAdapter(t, server, testSecretKey)
+	content := []byte("%PDF-1.4\n% receipt\n")
+
+	stored, err := adapter.UploadFile(context.Background(), tempFile(t, content), "attachments/user_1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.HasPrefix(stored.PublicId, "attachments/user_1/") || !strings.HasSuffix(stored.PublicId, ".pdf") {
+		t.Errorf("unexpected public id %q", stored.PublicId)
+	}
+	if !strings.HasPrefix(stored.SecureUrl, server.URL+"/"+testBucket+"/"+stored.PublicId+"?") {
+		t.Errorf("unexpected url %q", stored.SecureUrl)
+	}
+
+	objectPath := "/" + testBucket + "/" + stored.PublicId
+	if !bytes.Equal(fake.objects[objectPath], content) {
+		t.Fatalf("object not stored at %s", objectPath)
+	}
+	if got := fake.contentTypes[objectPath]; got != "application/pdf" {
+		t.Errorf("expected content type application/pdf, got %q", got)
+	}
+
+	if err := adapter.DeleteFile(context.Background(), stored.PublicId); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := fake.objects[objectPath]; ok {
+		t.Errorf("expected object to be deleted")
+	}
+}
+
+func TestFileUrl_PresignsGet(t *testing.T) {
+	fake := newFakeS3()
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	adapter := newTestAdapter(t, server, testSecretKey)
+	content := []byte("%PDF-1.4\n% receipt\n")
+
+	stored, err := adapter.UploadFile(context.Background(), tempFile(t, content), "attachments/user_1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	fileUrl, err := adapter.FileUrl(context.Background(), stored.PublicId)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	response, err := http.Get(fileUrl)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	body, _ := io.ReadAll(response.Body)
+	response.Body.Close()
+
+	if response.StatusCode != http.StatusOK || !bytes.Equal(body, content) {
+		t.Fatalf("expected the object back, got %s: %s", response.Status, body)
+	}
+
+	tampered := strings.Replace(fileUrl, "X-Amz-Expires=60", "X-Amz-Expires=604800", 1)
+	response, err = http.Get(tampered)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	response.Body.Close()
+
+	if response.StatusCode != http.StatusForbidden {
+		t.Errorf("expected an extended link to be rejected, got %s", response.Status)
+	}
+}
+
+func TestUploadImageFromFile_ResizesTo300x300(t *testing.T) {
+	fake := newFakeS3()
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	source := image.NewRGBA(image.Rect(0, 0, 640, 480))
+	for y := 0; y < 480; y++ {
+		for x := 0; x < 640; x++ {
+			source.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	var encoded bytes.Buffer
+	if err := png.Encode(&encoded, source); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	adapter := newTestAdapter(t, server, testSecretKey)
+
+	uploaded, err := adapter.UploadImageFromFile(context.Background(), tempFile(t, encoded.Bytes()), "42")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if uploaded.PublicId != "profile_images/user_42.jpg" {
+		t.Errorf("unexpected public id %q", uploaded.PublicId)
+	}
+
+	stored := fake.objects["/"+testBucket+"/profile_images/user_42.jpg"]
+	config, format, err := image.DecodeConfig(bytes.NewReader(stored))
+	if err != nil {
+		t.Fatalf("stored object is not an image: %v", err)
+	}
+	if format != "jpeg" || config.Width != 300 || config.Height != 300 {
+		t.Errorf("expected 300x300 jpeg, got %dx%d %s", config.Width, config.Height, format)
+	}
+}
+
+func TestUploadFile_WrongSecretIsRejected(t *testing.T) {
+	server := httptest.NewServer(newFakeS3())
+	defer server.Close()
+
+	adapter := newTestAdapter(t, server, "wrong-secret")
+
+	_, err := adapter.UploadFile(context.Background(), tempFile(t, []byte("hello")), "attachments")
+	if err == nil || !strings.Contains(err.Error(), "SignatureDoesNotMatch") {
+		t.Fatalf("expected signature error, got %v", err)
+	}
+}
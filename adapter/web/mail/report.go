@@ -1,149 +1,89 @@
 package mail
 
 import (
-	"crypto/tls"
-	"html/template"
-	"log"
+	"bytes"
+	"context"
+	"fmt"
 	"personal-finance/adapter/config"
 	"personal-finance/core/domain"
-	"strconv"
-
-	"github.com/wneessen/go-mail"
-	"golang.org/x/text/language"
-	"golang.org/x/text/message"
+	"personal-finance/core/port"
+	"strings"
 )
 
 type MailReportAdapter struct {
-	config *config.Mail
+	outbox    port.OutboxService
+	templates map[string]*reportTemplates
+	brand     brand
+	renderer  port.ReportRenderer
 }
 
-func NewMailReportAdapter(config *config.Mail) *MailReportAdapter {
+// NewMailReportAdapter loads the report templates from config.TemplatesDir,
+// or the embedded ones when it is not set. Reports are sent with a PDF copy
+// attached when renderer is not nil.
+func NewMailReportAdapter(outbox port.OutboxService, config *config.Mail, renderer port.ReportRenderer) (*MailReportAdapter, error) {
 
-	return &MailReportAdapter{
-		config,
+	templates, err := loadTemplates(config.TemplatesDir)
+	if err != nil {
+		return nil, err
 	}
-}
 
-func formatMoney(amount float64) string {
-	p := message.NewPrinter(language.English)
-	return p.Sprintf("$%.2f", amount)
+	return &MailReportAdapter{
+		outbox,
+		templates,
+		brand{
+			Name:    config.BrandName,
+			AppURL:  config.AppURL,
+			LogoURL: config.LogoURL,
+		},
+		renderer,
+	}, nil
 }
 
-const (
-	htmlBodyTemplate = `<p>&#10024; Hi {{.Username}} we hope you are having a great day!</p>
-
-<p>Here is the monthly summary of your personal finances for <strong>{{.Month}}</strong> of <strong>{{.Year}}</strong>.</p>
-
-<p>We invite you to continue recording all your expenses and incomes in the app.</p>
+// SendMail renders the report email in the user's language and queues it in
+// the outbox. A report is queued once per idempotency key; repeated calls
+// return the queued message.
+func (ra *MailReportAdapter) SendMail(ctx context.Context, report domain.Report, idempotencyKey string) (*domain.OutboxMessage, error) {
 
-<p>&#128640; You can login in the following link: <a href="https://tavo826.github.io/Finance-With-Angular-Front/Home" target="_blank">Personal Finance</a></p>
-
-<div style="display: flex; justify-content: center; margin-top: 20px;">
-  <table style="border-collapse: collapse; font-family: Arial, sans-serif; width: 50%; box-shadow: 0 0 10px rgba(0,0,0,0.1);">
-    <tr style="background-color: #f2f2f2;">
-      <td style="border: 1px solid #ddd; padding: 12px; font-weight: bold;">Total income</td>
-      <td style="border: 1px solid #ddd; padding: 12px;">{{formatMoney .TotalIncome}}</td>
-    </tr>
-    <tr>
-      <td style="border: 1px solid #ddd; padding: 12px; font-weight: bold;">Total expenses</td>
-      <td style="border: 1px solid #ddd; padding: 12px;">{{formatMoney .TotalExpenses}}</td>
-    </tr>
-    <tr style="background-color: #f9f9f9;">
-      <td style="border: 1px solid #ddd; padding: 12px; font-weight: bold;">Net balance</td>
-      <td style="border: 1px solid #ddd; padding: 12px;">{{formatMoney .NetBalance}}</td>
-    </tr>
-  </table>
-</div>
-
-<h3 style="text-align: center; margin-top: 40px;">Details by origin</h3>
-
-<div style="display: flex; justify-content: center; margin-top: 10px;">
-  <table style="border-collapse: collapse; font-family: Arial, sans-serif; width: 80%; box-shadow: 0 0 10px rgba(0,0,0,0.1);">
-    <tr style="background-color: #f2f2f2;">
-      <th style="border: 1px solid #ddd; padding: 12px;">Origin</th>
-      <th style="border: 1px solid #ddd; padding: 12px;">Income</th>
-      <th style="border: 1px solid #ddd; padding: 12px;">Expenses</th>
-      <th style="border: 1px solid #ddd; padding: 12px;">Balance</th>
-    </tr>
-    {{range .OriginSummary}}
-    <tr>
-      <td style="border: 1px solid #ddd; padding: 12px;">{{.OriginName}}</td>
-      <td style="border: 1px solid #ddd; padding: 12px;">{{formatMoney .TotalIncome}}</td>
-      <td style="border: 1px solid #ddd; padding: 12px;">{{formatMoney .TotalExpenses}}</td>
-      <td style="border: 1px solid #ddd; padding: 12px;">{{formatMoney .OriginBalance}}</td>
-    </tr>
-    {{end}}
-  </table>
-</div>
-
-{{if .CategorySummary}}
-<h3 style="text-align: center; margin-top: 40px;">Details by category</h3>
-
-<div style="display: flex; justify-content: center; margin-top: 10px;">
-  <table style="border-collapse: collapse; font-family: Arial, sans-serif; width: 80%; box-shadow: 0 0 10px rgba(0,0,0,0.1);">
-    <tr style="background-color: #f2f2f2;">
-      <th style="border: 1px solid #ddd; padding: 12px;">Category</th>
-      <th style="border: 1px solid #ddd; padding: 12px;">Transactions</th>
-      <th style="border: 1px solid #ddd; padding: 12px;">Total expenses</th>
-    </tr>
-    {{range .CategorySummary}}
-    <tr>
-      <td style="border: 1px solid #ddd; padding: 12px;">{{.OutputCategory}}</td>
-      <td style="border: 1px solid #ddd; padding: 12px;">{{.Count}}</td>
-      <td style="border: 1px solid #ddd; padding: 12px;">{{formatMoney .TotalExpenses}}</td>
-    </tr>
-    {{end}}
-  </table>
-</div>
-{{end}}`
-)
+	lang := templateLanguage(ra.templates, report.Locale)
+	templates := ra.templates[lang]
+	view := newReportView(report, ra.brand, lang)
 
-func (ra *MailReportAdapter) SendMail(report domain.Report) error {
+	var subject, html, text bytes.Buffer
 
-	message := mail.NewMsg()
-
-	htmlTpl, err := template.New("htmltpl").Funcs(template.FuncMap{
-		"formatMoney": formatMoney,
-	}).Parse(htmlBodyTemplate)
-	if err != nil {
-		return err
-	}
-
-	if err := message.EnvelopeFrom(ra.config.Username); err != nil {
-		return err
-	}
-	if err := message.FromFormat("Personal finance", ra.config.Username); err != nil {
-		return err
-	}
-	if err := message.AddToFormat(report.Username, report.UserEmail); err != nil {
-		log.Print("Error con el correo: ", err)
-		return err
+	if err := templates.subject.Execute(&subject, view); err != nil {
+		return nil, err
 	}
-
-	message.Subject("¡Toc-toc! Your personal finance summary is here")
-	if err := message.AddAlternativeHTMLTemplate(htmlTpl, report); err != nil {
-		return err
+	if err := templates.html.Execute(&html, view); err != nil {
+		return nil, err
 	}
-
-	port, err := strconv.Atoi(ra.config.Port)
-	if err != nil {
-		return err
+	if err := templates.text.Execute(&text, view); err != nil {
+		return nil, err
 	}
 
-	client, err := mail.NewClient(
-		ra.config.Host,
-		mail.WithPort(port),
-		mail.WithSMTPAuth(mail.SMTPAuthPlain),
-		mail.WithTLSConfig(&tls.Config{ServerName: ra.config.Host}),
-		mail.WithUsername(ra.config.Username),
-		mail.WithPassword(ra.config.Password))
-	if err != nil {
-		return err
+	var attachments []domain.MailAttachment
+	if ra.renderer != nil {
+		document, err := ra.renderer.RenderPDF(report)
+		if err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, domain.MailAttachment{
+			Filename:    reportFilename(report),
+			ContentType: "application/pdf",
+			Content:     document,
+		})
 	}
 
-	if err := client.DialAndSend(message); err != nil {
-		return err
-	}
+	return ra.outbox.EnqueueMessage(ctx, &domain.OutboxMessage{
+		IdempotencyKey: idempotencyKey,
+		ToName:         report.Username,
+		ToEmail:        report.UserEmail,
+		Subject:        strings.TrimSpace(subject.String()),
+		HTMLBody:       html.String(),
+		TextBody:       text.String(),
+		Attachments:    attachments,
+	})
+}
 
-	return nil
+func reportFilename(report domain.Report) string {
+	return fmt.Sprintf("report-%s.pdf", report.Period.Start.Format("2006-01"))
 }
@@ -0,0 +1,106 @@
+package mail
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"personal-finance/adapter/config"
+	"personal-finance/core/domain"
+	"personal-finance/core/port"
+	"personal-finance/core/service"
+)
+
+type stubRenderer struct{}
+
+func (stubRenderer) RenderPDF(report domain.Report) ([]byte, error) {
+	return []byte("%PDF-1.3"), nil
+}
+
+func renderReport(t *testing.T, report domain.Report) *domain.OutboxMessage {
+	return renderReportWith(t, report, nil)
+}
+
+func renderReportWith(t *testing.T, report domain.Report, renderer port.ReportRenderer) *domain.OutboxMessage {
+	outbox := &memoryOutbox{messages: map[string]*domain.OutboxMessage{}}
+	outboxService := service.NewOutboxService(outbox, nil, 1, time.Minute)
+
+	adapter, err := NewMailReportAdapter(outboxService, &config.Mail{BrandName: "Finanzas", AppURL: "https://app.example.com"}, renderer)
+	if err != nil {
+		t.Fatalf("cannot load templates: %v", err)
+	}
+
+	if _, err := adapter.SendMail(context.Background(), report, "report:test"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, message := range outbox.messages {
+		return message
+	}
+	t.Fatal("expected a queued message")
+	return nil
+}
+
+func TestSendMail_RendersUserLocaleAndCurrency(t *testing.T) {
+	message := renderReport(t, domain.Report{
+		UserId:        "u1",
+		Username:      "Ana",
+		Locale:        "es-CO",
+		Currency:      "COP",
+		Month:         time.September,
+		Year:          2026,
+		TotalIncome:   2500000,
+		OriginSummary: []domain.OriginSummary{{OriginName: "Nequi", OriginBalance: 1200.5}},
+	})
+
+	if message.Subject != "¡Toc-toc! Tu resumen de Finanzas de septiembre está aquí" {
+		t.Errorf("unexpected subject %q", message.Subject)
+	}
+
+	for _, body := range []string{message.HTMLBody, message.TextBody} {
+		if !strings.Contains(body, "$ 2.500.000") {
+			t.Errorf("expected amounts in Colombian pesos, got:\n%s", body)
+		}
+		if !strings.Contains(body, "https://app.example.com") {
+			t.Errorf("expected configured link, got:\n%s", body)
+		}
+	}
+
+	if strings.Contains(message.TextBody, "<") {
+		t.Errorf("expected plain-text body without markup, got:\n%s", message.TextBody)
+	}
+}
+
+func TestSendMail_FallsBackToEnglish(t *testing.T) {
+	message := renderReport(t, domain.Report{UserId: "u1", Locale: "fr", Month: time.March, Year: 2026})
+
+	if !strings.Contains(message.Subject, "March") {
+		t.Errorf("expected English templates, got %q", message.Subject)
+	}
+
+	message = renderReport(t, domain.Report{UserId: "u1", Month: time.March, Year: 2026, TotalIncome: 10})
+
+	if !strings.Contains(message.TextBody, "$ 10.00") {
+		t.Errorf("expected USD by default, got:\n%s", message.TextBody)
+	}
+}
+
+func TestSendMail_AttachesPDFWhenEnabled(t *testing.T) {
+	period, _ := domain.MonthPeriod(2026, time.September)
+	report := domain.Report{UserId: "u1", Month: time.September, Year: 2026, Period: period}
+
+	if message := renderReport(t, report); len(message.Attachments) != 0 {
+		t.Errorf("expected no attachment by default, got %d", len(message.Attachments))
+	}
+
+	message := renderReportWith(t, report, stubRenderer{})
+	if len(message.Attachments) != 1 {
+		t.Fatalf("expected one attachment, got %d", len(message.Attachments))
+	}
+
+	attachment := message.Attachments[0]
+	if attachment.Filename != "report-2026-09.pdf" || attachment.ContentType != "application/pdf" {
+		t.Errorf("unexpected attachment %s (%s)", attachment.Filename, attachment.ContentType)
+	}
+}
@@ -0,0 +1,82 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"personal-finance/adapter/config"
+	"personal-finance/core/domain"
+	"strconv"
+
+	"github.com/wneessen/go-mail"
+)
+
+// SMTPSender delivers outbox messages through the configured SMTP server.
+type SMTPSender struct {
+	config *config.Mail
+}
+
+func NewSMTPSender(config *config.Mail) *SMTPSender {
+
+	return &SMTPSender{
+		config,
+	}
+}
+
+func (ss *SMTPSender) SendMessage(ctx context.Context, outboxMessage *domain.OutboxMessage) error {
+
+	message := mail.NewMsg()
+
+	if err := message.EnvelopeFrom(ss.config.Username); err != nil {
+		return err
+	}
+	if err := message.FromFormat(ss.config.BrandName, ss.config.Username); err != nil {
+		return err
+	}
+	if err := message.AddToFormat(outboxMessage.ToName, outboxMessage.ToEmail); err != nil {
+		return err
+	}
+
+	// a stable Message-ID lets receivers drop duplicates of a retried message
+	message.SetMessageIDWithValue(outboxMessage.IdempotencyKey + "@personal-finance")
+	message.Subject(outboxMessage.Subject)
+	if outboxMessage.TextBody != "" {
+		message.SetBodyString(mail.TypeTextPlain, outboxMessage.TextBody)
+		message.AddAlternativeString(mail.TypeTextHTML, outboxMessage.HTMLBody)
+	} else {
+		message.SetBodyString(mail.TypeTextHTML, outboxMessage.HTMLBody)
+	}
+
+	for _, attachment := range outboxMessage.Attachments {
+		err := message.AttachReader(attachment.Filename, bytes.NewReader(attachment.Content),
+			mail.WithFileContentType(mail.ContentType(attachment.ContentType)))
+		if err != nil {
+			return err
+		}
+	}
+
+	port, err := strconv.Atoi(ss.config.Port)
+	if err != nil {
+		return err
+	}
+
+	options := []mail.Option{
+		mail.WithPort(port),
+		mail.WithSMTPAuth(mail.SMTPAuthPlain),
+		mail.WithUsername(ss.config.Username),
+		mail.WithPassword(ss.config.Password),
+	}
+
+	if ss.config.TLS {
+		options = append(options, mail.WithTLSConfig(&tls.Config{ServerName: ss.config.Host}))
+	} else {
+		options = append(options, mail.WithTLSPolicy(mail.NoTLS))
+	}
+
+	client, err := mail.NewClient(ss.config.Host, options...)
+	if err != nil {
+		return err
+	}
+
+	return client.DialAndSendWithContext(ctx, message)
+}
@@ -0,0 +1,286 @@
+package mail
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"personal-finance/adapter/config"
+	"personal-finance/core/domain"
+	"personal-finance/core/service"
+)
+
+// --- local SMTP stand-in ---
+
+// smtpStandIn is a minimal plain-text SMTP server that records received
+// messages and rejects the first failures DATA commands with a 451.
+type smtpStandIn struct {
+	listener net.Listener
+	mu       sync.Mutex
+	failures int
+	messages []string
+}
+
+func newSMTPStandIn(t *testing.T, failures int) *smtpStandIn {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("cannot listen: %v", err)
+	}
+
+	server := &smtpStandIn{listener: listener, failures: failures}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+
+	return server
+}
+
+func (s *smtpStandIn) config() *config.Mail {
+	_, port, _ := net.SplitHostPort(s.listener.Addr().String())
+
+	return &config.Mail{
+		Host:     "127.0.0.1",
+		Port:     port,
+		Username: "reports@example.com",
+		Password: "secret",
+	}
+}
+
+func (s *smtpStandIn) received() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.messages...)
+}
+
+func (s *smtpStandIn) serve(conn net.Conn) {
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+	reply("220 localhost ESMTP")
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+
+		command := strings.ToUpper(strings.Fields(line + " ")[0])
+
+		switch command {
+		case "EHLO":
+			reply("250-localhost")
+			reply("250 AUTH PLAIN")
+		case "AUTH":
+			reply("235 2.7.0 Authentication successful")
+		case "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+
+			var data strings.Builder
+			for {
+				dataLine, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if dataLine == ".\r\n" {
+					break
+				}
+				data.WriteString(dataLine)
+			}
+
+			s.mu.Lock()
+			if s.failures > 0 {
+				s.failures--
+				s.mu.Unlock()
+				reply("451 4.3.0 Try again later")
+				continue
+			}
+			s.messages = append(s.messages, data.String())
+			s.mu.Unlock()
+
+			reply("250 2.0.0 OK")
+		case "QUIT":
+			reply("221 2.0.0 Bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+// --- in-memory outbox ---
+
+type memoryOutbox struct {
+	messages map[string]*domain.OutboxMessage
+}
+
+func (m *memoryOutbox) GetMessages(ctx context.Context, status string, page, limit uint64) ([]domain.OutboxMessage, int64, int, error) {
+	return nil, 0, 0, nil
+}
+
+func (m *memoryOutbox) GetMessageById(ctx context.Context, id string) (*domain.OutboxMessage, error) {
+	message, ok := m.messages[id]
+	if !ok {
+		return nil, domain.ErrDataNotFound
+	}
+	copy := *message
+	return &copy, nil
+}
+
+func (m *memoryOutbox) EnqueueMessage(ctx context.Context, message *domain.OutboxMessage) (*domain.OutboxMessage, error) {
+	for _, existing := range m.messages {
+		if existing.IdempotencyKey == message.IdempotencyKey {
+			copy := *existing
+			return &copy, nil
+		}
+	}
+	message.ID = message.IdempotencyKey
+	copy := *message
+	m.messages[message.ID] = &copy
+	return message, nil
+}
+
+func (m *memoryOutbox) ClaimDueMessage(ctx context.Context, now time.Time, leaseUntil time.Time) (*domain.OutboxMessage, error) {
+	for _, message := range m.messages {
+		if message.Status == domain.OutboxPending && !message.NextAttemptAt.After(now) {
+			message.NextAttemptAt = leaseUntil
+			message.Attempts++
+			copy := *message
+			return &copy, nil
+		}
+	}
+	return nil, domain.ErrDataNotFound
+}
+
+func (m *memoryOutbox) UpdateMessageStatus(ctx context.Context, message *domain.OutboxMessage) error {
+	copy := *message
+	m.messages[message.ID] = &copy
+	return nil
+}
+
+func (m *memoryOutbox) RequeueMessage(ctx context.Context, id string, now time.Time) (*domain.OutboxMessage, error) {
+	message := m.messages[id]
+	message.Status = domain.OutboxPending
+	message.Attempts = 0
+	message.NextAttemptAt = now
+	copy := *message
+	return &copy, nil
+}
+
+// --- tests ---
+
+func TestSMTPSender_DeliversWithStableMessageId(t *testing.T) {
+	server := newSMTPStandIn(t, 0)
+	sender := NewSMTPSender(server.config())
+
+	err := sender.SendMessage(context.Background(), &domain.OutboxMessage{
+		IdempotencyKey: "report:u1:2026-09",
+		ToName:         "Ana",
+		ToEmail:        "ana@example.com",
+		Subject:        "Summary",
+		HTMLBody:       "<p>Hello</p>",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	messages := server.received()
+	if len(messages) != 1 {
+		t.Fatalf("expected 1 message, got %d", len(messages))
+	}
+	if !strings.Contains(messages[0], "Message-ID: <report:u1:2026-09@personal-finance>") {
+		t.Errorf("expected stable Message-ID, got:\n%s", messages[0])
+	}
+	if !strings.Contains(messages[0], "<p>Hello</p>") {
+		t.Errorf("expected HTML body, got:\n%s", messages[0])
+	}
+}
+
+func TestOutbox_RetriesWithBackoffUntilSent(t *testing.T) {
+	server := newSMTPStandIn(t, 2)
+	outbox := &memoryOutbox{messages: map[string]*domain.OutboxMessage{}}
+	outboxService := service.NewOutboxService(outbox, NewSMTPSender(server.config()), 5, time.Minute)
+
+	ctx := context.Background()
+
+	queued, _ := outboxService.EnqueueMessage(ctx, &domain.OutboxMessage{IdempotencyKey: "k1", ToEmail: "ana@example.com", HTMLBody: "<p>1</p>"})
+	if again, _ := outboxService.EnqueueMessage(ctx, &domain.OutboxMessage{IdempotencyKey: "k1", ToEmail: "ana@example.com", HTMLBody: "<p>2</p>"}); again.ID != queued.ID {
+		t.Fatalf("expected the idempotency key to return the queued message")
+	}
+
+	// first attempt fails and is retried after the base backoff
+	now := time.Now()
+	if sent, err := outboxService.ProcessDueMessages(ctx, now); err != nil || sent != 0 {
+		t.Fatalf("expected no message sent, got %d (%v)", sent, err)
+	}
+	first := outbox.messages["k1"].NextAttemptAt
+	if first.Sub(now) != time.Minute {
+		t.Errorf("expected retry in 1m, got %v", first.Sub(now))
+	}
+
+	// second attempt fails and waits twice as long
+	outboxService.ProcessDueMessages(ctx, first)
+	second := outbox.messages["k1"].NextAttemptAt
+	if second.Sub(first) != 2*time.Minute {
+		t.Errorf("expected retry in 2m, got %v", second.Sub(first))
+	}
+
+	if sent, err := outboxService.ProcessDueMessages(ctx, second); err != nil || sent != 1 {
+		t.Fatalf("expected the message to be sent, got %d (%v)", sent, err)
+	}
+
+	message := outbox.messages["k1"]
+	if message.Status != domain.OutboxSent || message.Attempts != 3 {
+		t.Errorf("unexpected message %+v", message)
+	}
+	if len(server.received()) != 1 {
+		t.Errorf("expected exactly one delivered copy, got %d", len(server.received()))
+	}
+}
+
+func TestOutbox_DeadLettersAfterMaxAttemptsAndResends(t *testing.T) {
+	server := newSMTPStandIn(t, 2)
+	outbox := &memoryOutbox{messages: map[string]*domain.OutboxMessage{}}
+	outboxService := service.NewOutboxService(outbox, NewSMTPSender(server.config()), 2, time.Minute)
+
+	ctx := context.Background()
+
+	outboxService.EnqueueMessage(ctx, &domain.OutboxMessage{IdempotencyKey: "k1", ToEmail: "ana@example.com"})
+
+	outboxService.ProcessDueMessages(ctx, time.Now())
+	outboxService.ProcessDueMessages(ctx, time.Now().Add(time.Hour))
+
+	message := outbox.messages["k1"]
+	if message.Status != domain.OutboxDead || message.LastError == "" {
+		t.Fatalf("expected dead-lettered message, got %+v", message)
+	}
+
+	// dead messages are not retried on their own
+	if sent, _ := outboxService.ProcessDueMessages(ctx, time.Now().Add(24*time.Hour)); sent != 0 {
+		t.Errorf("expected dead message to stay unsent")
+	}
+
+	if _, err := outboxService.ResendMessage(ctx, "k1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sent, _ := outboxService.ProcessDueMessages(ctx, time.Now()); sent != 1 {
+		t.Errorf("expected resent message to be delivered")
+	}
+
+	if _, err := outboxService.ResendMessage(ctx, "k1"); err != domain.ErrMessageAlreadySent {
+		t.Errorf("expected ErrMessageAlreadySent, got %v", err)
+	}
+}
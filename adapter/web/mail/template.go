@@ -0,0 +1,152 @@
+package mail
+
+import (
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"os"
+	"personal-finance/core/domain"
+	"strings"
+	texttemplate "text/template"
+
+	"golang.org/x/text/currency"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// defaultTemplates are the report emails shipped with the binary. Each
+// directory is a language with a subject.txt, report.html and report.txt.
+//
+//go:embed templates
+var defaultTemplates embed.FS
+
+const defaultLocale = "en"
+
+var monthNames = map[string][12]string{
+	"es": {"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+}
+
+type reportTemplates struct {
+	subject *texttemplate.Template
+	html    *htmltemplate.Template
+	text    *texttemplate.Template
+}
+
+// loadTemplates parses the templates of every language directory in dir, or
+// in the embedded templates when dir is empty.
+func loadTemplates(dir string) (map[string]*reportTemplates, error) {
+
+	var templatesFS fs.FS = os.DirFS(dir)
+	if dir == "" {
+		sub, err := fs.Sub(defaultTemplates, "templates")
+		if err != nil {
+			return nil, err
+		}
+		templatesFS = sub
+	}
+
+	entries, err := fs.ReadDir(templatesFS, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	templates := map[string]*reportTemplates{}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		lang := entry.Name()
+
+		subject, err := texttemplate.ParseFS(templatesFS, lang+"/subject.txt")
+		if err != nil {
+			return nil, err
+		}
+		html, err := htmltemplate.ParseFS(templatesFS, lang+"/report.html")
+		if err != nil {
+			return nil, err
+		}
+		text, err := texttemplate.ParseFS(templatesFS, lang+"/report.txt")
+		if err != nil {
+			return nil, err
+		}
+
+		templates[lang] = &reportTemplates{subject, html, text}
+	}
+
+	if templates[defaultLocale] == nil {
+		return nil, fmt.Errorf("missing %q report templates", defaultLocale)
+	}
+
+	return templates, nil
+}
+
+type brand struct {
+	Name    string
+	AppURL  string
+	LogoURL string
+}
+
+// reportView is the data the report templates are rendered with: the report
+// plus branding and formatting helpers for the user's locale and currency.
+type reportView struct {
+	domain.Report
+	Brand brand
+
+	lang    string
+	printer *message.Printer
+	unit    currency.Unit
+}
+
+func newReportView(report domain.Report, brand brand, lang string) reportView {
+
+	tag, err := language.Parse(report.Locale)
+	if err != nil {
+		tag = language.English
+	}
+
+	unit, err := currency.ParseISO(report.Currency)
+	if err != nil {
+		unit = currency.USD
+	}
+
+	return reportView{
+		Report:  report,
+		Brand:   brand,
+		lang:    lang,
+		printer: message.NewPrinter(tag),
+		unit:    unit,
+	}
+}
+
+// Money formats amount in the user's currency with the user's number format.
+func (rv reportView) Money(amount float64) string {
+	return rv.printer.Sprint(currency.Symbol(rv.unit.Amount(amount)))
+}
+
+func (rv reportView) MonthName() string {
+	if names, ok := monthNames[rv.lang]; ok {
+		return names[rv.Month-1]
+	}
+	return rv.Month.String()
+}
+
+// templateLanguage picks the templates' language for locale: its base
+// language if there are templates for it, English otherwise.
+func templateLanguage(templates map[string]*reportTemplates, locale string) string {
+
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return defaultLocale
+	}
+
+	base, _ := tag.Base()
+	lang := strings.ToLower(base.String())
+
+	if templates[lang] == nil {
+		return defaultLocale
+	}
+
+	return lang
+}
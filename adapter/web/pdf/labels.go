@@ -0,0 +1,90 @@
+package pdf
+
+import "golang.org/x/text/language"
+
+type labels struct {
+	Period        string
+	User          string
+	Summary       string
+	TotalIncome   string
+	TotalExpenses string
+	Assets        string
+	Liabilities   string
+	NetBalance    string
+	ByOrigin      string
+	BySource      string
+	ByCategory    string
+	ByTag         string
+	CategorySplit string
+	Origin        string
+	Source        string
+	Category      string
+	Tag           string
+	Income        string
+	Expenses      string
+	Balance       string
+	Owed          string
+	Transactions  string
+	Other         string
+}
+
+var englishLabels = labels{
+	Period:        "Period",
+	User:          "User",
+	Summary:       "Summary",
+	TotalIncome:   "Total income",
+	TotalExpenses: "Total expenses",
+	Assets:        "Assets",
+	Liabilities:   "Liabilities",
+	NetBalance:    "Net balance",
+	ByOrigin:      "Details by origin",
+	BySource:      "Income by source",
+	ByCategory:    "Details by category",
+	ByTag:         "Details by tag",
+	CategorySplit: "Expenses by category",
+	Origin:        "Origin",
+	Source:        "Source",
+	Category:      "Category",
+	Tag:           "Tag",
+	Income:        "Income",
+	Expenses:      "Expenses",
+	Balance:       "Balance",
+	Owed:          "owed",
+	Transactions:  "Transactions",
+	Other:         "Other",
+}
+
+var spanishLabels = labels{
+	Period:        "Periodo",
+	User:          "Usuario",
+	Summary:       "Resumen",
+	TotalIncome:   "Ingresos totales",
+	TotalExpenses: "Gastos totales",
+	Assets:        "Activos totales",
+	Liabilities:   "Pasivos totales",
+	NetBalance:    "Balance neto",
+	ByOrigin:      "Detalle por origen",
+	BySource:      "Ingresos por fuente",
+	ByCategory:    "Detalle por categoría",
+	ByTag:         "Detalle por etiqueta",
+	CategorySplit: "Gastos por categoría",
+	Origin:        "Origen",
+	Source:        "Fuente",
+	Category:      "Categoría",
+	Tag:           "Etiqueta",
+	Income:        "Ingresos",
+	Expenses:      "Gastos",
+	Balance:       "Balance",
+	Owed:          "adeudado",
+	Transactions:  "Transacciones",
+	Other:         "Otros",
+}
+
+func labelsFor(tag language.Tag) labels {
+
+	if base, _ := tag.Base(); base.String() == "es" {
+		return spanishLabels
+	}
+
+	return englishLabels
+}
@@ -0,0 +1,329 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"personal-finance/core/domain"
+	"sort"
+	"strings"
+
+	"github.com/jung-kurt/gofpdf"
+	"golang.org/x/text/currency"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+const (
+	pageWidth  = 210.0
+	margin     = 15.0
+	lineHeight = 8.0
+
+	// maxChartSlices is how many categories the chart shows before grouping
+	// the rest as "other".
+	maxChartSlices = 6
+)
+
+var chartColors = [][3]int{
+	{54, 162, 235},
+	{255, 99, 132},
+	{255, 205, 86},
+	{75, 192, 192},
+	{153, 102, 255},
+	{255, 159, 64},
+	{201, 203, 207},
+}
+
+// ReportRenderer renders reports as printable A4 PDF documents.
+type ReportRenderer struct {
+	brandName string
+}
+
+func NewReportRenderer(brandName string) *ReportRenderer {
+
+	return &ReportRenderer{
+		brandName,
+	}
+}
+
+type document struct {
+	*gofpdf.Fpdf
+	tr      func(string) string
+	labels  labels
+	printer *message.Printer
+	unit    currency.Unit
+}
+
+func (d *document) money(amount float64) string {
+	return d.tr(d.printer.Sprint(currency.Symbol(d.unit.Amount(amount))))
+}
+
+// RenderPDF renders the report summary, the per-origin, per-category and
+// per-tag tables and a chart of how expenses split across categories.
+func (rr *ReportRenderer) RenderPDF(report domain.Report) ([]byte, error) {
+
+	tag, err := language.Parse(report.Locale)
+	if err != nil {
+		tag = language.English
+	}
+
+	unit, err := currency.ParseISO(report.Currency)
+	if err != nil {
+		unit = currency.USD
+	}
+
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(margin, margin, margin)
+	pdf.SetAutoPageBreak(true, margin)
+	pdf.SetTitle(rr.brandName+" - "+report.Period.Label, true)
+	pdf.AddPage()
+
+	doc := &document{
+		Fpdf:    pdf,
+		tr:      pdf.UnicodeTranslatorFromDescriptor(""),
+		labels:  labelsFor(tag),
+		printer: message.NewPrinter(tag),
+		unit:    unit,
+	}
+
+	doc.header(rr.brandName, report)
+	doc.summary(report)
+	doc.originTable(report.OriginSummary)
+	doc.incomeTable(report.IncomeSummary)
+	doc.categoryTable(report.CategorySummary)
+	doc.categoryChart(report.CategorySummary)
+	doc.tagTable(report.TagSummary)
+
+	var buffer bytes.Buffer
+	if err := pdf.Output(&buffer); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+func (d *document) header(brandName string, report domain.Report) {
+
+	d.SetFont("Helvetica", "B", 18)
+	d.CellFormat(0, 10, d.tr(brandName), "", 1, "L", false, 0, "")
+
+	d.SetFont("Helvetica", "", 11)
+	d.SetTextColor(100, 100, 100)
+	d.CellFormat(0, 6, d.tr(fmt.Sprintf("%s: %s", d.labels.Period, report.Period.Label)), "", 1, "L", false, 0, "")
+	d.CellFormat(0, 6, d.tr(fmt.Sprintf("%s: %s <%s>", d.labels.User, report.Username, report.UserEmail)), "", 1, "L", false, 0, "")
+	d.SetTextColor(0, 0, 0)
+	d.Ln(4)
+}
+
+func (d *document) summary(report domain.Report) {
+
+	d.sectionTitle(d.labels.Summary)
+
+	rows := [][2]string{
+		{d.labels.TotalIncome, d.money(report.TotalIncome)},
+		{d.labels.TotalExpenses, d.money(report.TotalExpenses)},
+	}
+
+	if report.TotalLiabilities != 0 {
+		rows = append(rows,
+			[2]string{d.labels.Assets, d.money(report.TotalAssets)},
+			[2]string{d.labels.Liabilities, d.money(report.TotalLiabilities)})
+	}
+
+	rows = append(rows, [2]string{d.labels.NetBalance, d.money(report.NetBalance)})
+
+	for i, row := range rows {
+		d.SetFillColor(242, 242, 242)
+		d.SetFont("Helvetica", "B", 10)
+		d.CellFormat(60, lineHeight, d.tr(row[0]), "1", 0, "L", i%2 == 0, 0, "")
+		d.SetFont("Helvetica", "", 10)
+		d.CellFormat(50, lineHeight, row[1], "1", 1, "R", i%2 == 0, 0, "")
+	}
+}
+
+func (d *document) originTable(origins []domain.OriginSummary) {
+
+	d.sectionTitle(d.labels.ByOrigin)
+
+	widths := []float64{60, 40, 40, 40}
+	d.tableHeader(widths, d.labels.Origin, d.labels.Income, d.labels.Expenses, d.labels.Balance)
+
+	for _, origin := range origins {
+		balance := d.money(origin.OriginBalance)
+		if origin.Liability {
+			balance = fmt.Sprintf("%s (%s)", balance, d.tr(d.labels.Owed))
+		}
+		d.tableRow(widths, d.tr(origin.OriginName), d.money(origin.TotalIncome), d.money(origin.TotalExpenses), balance)
+	}
+}
+
+func (d *document) incomeTable(sources []domain.IncomeSummary) {
+
+	if len(sources) == 0 {
+		return
+	}
+
+	d.sectionTitle(d.labels.BySource)
+
+	widths := []float64{80, 40, 60}
+	d.tableHeader(widths, d.labels.Source, d.labels.Transactions, d.labels.TotalIncome)
+
+	for _, source := range sources {
+		d.tableRow(widths, d.tr(source.Source), fmt.Sprint(source.Count), d.money(source.TotalIncome))
+	}
+}
+
+func (d *document) categoryTable(categories []domain.CategorySummary) {
+
+	if len(categories) == 0 {
+		return
+	}
+
+	d.sectionTitle(d.labels.ByCategory)
+
+	widths := []float64{80, 40, 60}
+	d.tableHeader(widths, d.labels.Category, d.labels.Transactions, d.labels.TotalExpenses)
+
+	for _, category := range categories {
+		d.tableRow(widths, d.tr(category.OutputCategory), fmt.Sprint(category.Count), d.money(category.TotalExpenses))
+	}
+}
+
+func (d *document) tagTable(tags []domain.TagSummary) {
+
+	if len(tags) == 0 {
+		return
+	}
+
+	d.sectionTitle(d.labels.ByTag)
+
+	widths := []float64{60, 30, 45, 45}
+	d.tableHeader(widths, d.labels.Tag, d.labels.Transactions, d.labels.Income, d.labels.Expenses)
+
+	for _, tag := range tags {
+		d.tableRow(widths, d.tr(tag.Tag), fmt.Sprint(tag.Count), d.money(tag.TotalIncome), d.money(tag.TotalExpenses))
+	}
+}
+
+// categoryChart draws a pie chart of the expenses by category with its legend.
+func (d *document) categoryChart(categories []domain.CategorySummary) {
+
+	slices := chartSlices(categories, d.labels.Other)
+	if len(slices) == 0 {
+		return
+	}
+
+	const radius = 30.0
+
+	if d.GetY()+2*radius+20 > 297-margin {
+		d.AddPage()
+	}
+
+	d.sectionTitle(d.labels.CategorySplit)
+
+	total := 0.0
+	for _, slice := range slices {
+		total += slice.TotalExpenses
+	}
+
+	centerX := margin + radius
+	centerY := d.GetY() + radius + 2
+	legendY := centerY - radius
+
+	start := 90.0
+	for i, slice := range slices {
+		color := chartColors[i%len(chartColors)]
+		sweep := 360 * slice.TotalExpenses / total
+
+		d.SetFillColor(color[0], color[1], color[2])
+		d.Polygon(sectorPoints(centerX, centerY, radius, start, start+sweep), "F")
+
+		d.Rect(centerX+radius+15, legendY+float64(i)*lineHeight+2, 4, 4, "F")
+		d.SetXY(centerX+radius+21, legendY+float64(i)*lineHeight)
+		d.SetFont("Helvetica", "", 10)
+		d.CellFormat(0, lineHeight, d.tr(fmt.Sprintf("%s  %.1f%%  (%s)", slice.OutputCategory, 100*slice.TotalExpenses/total, d.printer.Sprint(currency.Symbol(d.unit.Amount(slice.TotalExpenses))))), "", 0, "L", false, 0, "")
+
+		start += sweep
+	}
+
+	d.SetXY(margin, centerY+radius+4)
+}
+
+// chartSlices returns the categories with expenses, largest first, grouping
+// those past maxChartSlices under other.
+func chartSlices(categories []domain.CategorySummary, other string) []domain.CategorySummary {
+
+	var slices []domain.CategorySummary
+	for _, category := range categories {
+		if category.TotalExpenses > 0 {
+			slices = append(slices, category)
+		}
+	}
+
+	sort.SliceStable(slices, func(i, j int) bool {
+		return slices[i].TotalExpenses > slices[j].TotalExpenses
+	})
+
+	if len(slices) <= maxChartSlices {
+		return slices
+	}
+
+	rest := domain.CategorySummary{OutputCategory: other}
+	for _, category := range slices[maxChartSlices-1:] {
+		rest.TotalExpenses += category.TotalExpenses
+		rest.Count += category.Count
+	}
+
+	return append(slices[:maxChartSlices-1], rest)
+}
+
+// sectorPoints approximates the pie sector between the given angles (degrees,
+// counter-clockwise from 3 o'clock) with one point per degree.
+func sectorPoints(x, y, radius, from, to float64) []gofpdf.PointType {
+
+	points := []gofpdf.PointType{{X: x, Y: y}}
+
+	steps := int(math.Ceil(to - from))
+	for i := 0; i <= steps; i++ {
+		angle := math.Min(from+float64(i), to) * math.Pi / 180
+		points = append(points, gofpdf.PointType{
+			X: x + radius*math.Cos(angle),
+			Y: y - radius*math.Sin(angle),
+		})
+	}
+
+	return points
+}
+
+func (d *document) sectionTitle(title string) {
+
+	d.Ln(4)
+	d.SetFont("Helvetica", "B", 13)
+	d.CellFormat(0, 9, d.tr(title), "", 1, "L", false, 0, "")
+}
+
+func (d *document) tableHeader(widths []float64, titles ...string) {
+
+	d.SetFont("Helvetica", "B", 10)
+	d.SetFillColor(242, 242, 242)
+
+	for i, title := range titles {
+		d.CellFormat(widths[i], lineHeight, d.tr(title), "1", 0, "C", true, 0, "")
+	}
+	d.Ln(-1)
+}
+
+// tableRow writes one row; every column but the first is right aligned.
+func (d *document) tableRow(widths []float64, values ...string) {
+
+	d.SetFont("Helvetica", "", 10)
+
+	for i, value := range values {
+		align := "R"
+		if i == 0 {
+			align = "L"
+		}
+		d.CellFormat(widths[i], lineHeight, strings.TrimSpace(value), "1", 0, align, false, 0, "")
+	}
+	d.Ln(-1)
+}
@@ -0,0 +1,55 @@
+package pdf
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"personal-finance/core/domain"
+)
+
+func TestRenderPDF(t *testing.T) {
+	period, _ := domain.MonthPeriod(2026, time.September)
+
+	document, err := NewReportRenderer("Personal finance").RenderPDF(domain.Report{
+		Username:      "Ana",
+		Locale:        "es",
+		Currency:      "COP",
+		Period:        period,
+		TotalExpenses: 300,
+		OriginSummary: []domain.OriginSummary{{OriginName: "Bancolombia", TotalExpenses: 300}},
+		CategorySummary: []domain.CategorySummary{
+			{OutputCategory: "Alimentación", TotalExpenses: 200, Count: 3},
+			{OutputCategory: "Transporte", TotalExpenses: 100, Count: 2},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !bytes.HasPrefix(document, []byte("%PDF-")) {
+		t.Errorf("expected a PDF document, got %q", document[:10])
+	}
+}
+
+func TestChartSlices_GroupsSmallCategoriesAsOther(t *testing.T) {
+	var categories []domain.CategorySummary
+	for i := 1; i <= 8; i++ {
+		categories = append(categories, domain.CategorySummary{OutputCategory: string(rune('A' + i)), TotalExpenses: float64(i), Count: 1})
+	}
+	categories = append(categories, domain.CategorySummary{OutputCategory: "Refund", TotalExpenses: 0})
+
+	slices := chartSlices(categories, "Other")
+
+	if len(slices) != maxChartSlices {
+		t.Fatalf("expected %d slices, got %d", maxChartSlices, len(slices))
+	}
+	if slices[0].TotalExpenses != 8 {
+		t.Errorf("expected largest category first, got %+v", slices[0])
+	}
+
+	other := slices[len(slices)-1]
+	if other.OutputCategory != "Other" || other.TotalExpenses != 1+2+3 || other.Count != 3 {
+		t.Errorf("unexpected other slice %+v", other)
+	}
+}
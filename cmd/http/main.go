@@ -8,11 +8,17 @@ import (
 
 	"personal-finance/adapter/config"
 	"personal-finance/adapter/handler/http"
+	"personal-finance/adapter/scheduler"
 	"personal-finance/adapter/storage/cloud"
 	"personal-finance/adapter/storage/cloud/adapter"
 	"personal-finance/adapter/storage/db"
 	"personal-finance/adapter/storage/db/repository"
+	"personal-finance/adapter/storage/local"
+	"personal-finance/adapter/storage/s3"
 	"personal-finance/adapter/web/mail"
+	"personal-finance/adapter/web/pdf"
+	"personal-finance/core/domain"
+	"personal-finance/core/port"
 	"personal-finance/core/service"
 
 	"github.com/go-playground/validator/v10"
@@ -37,7 +43,7 @@ func main() {
 	}
 	txManager := db.NewMongoTransactionManager(dbClient)
 
-	storage, err := cloud.New(ctx, config.ImageCloud)
+	imageAdapter, fileAdapter, fileReader, err := newStorage(ctx, config.ImageCloud)
 	if err != nil {
 		slog.Error("Error connecting to storage service", "error", err)
 		os.Exit(1)
@@ -46,23 +52,122 @@ func main() {
 	validate := validator.New()
 
 	originRepo := repository.NewOriginRepository(database, config.DB)
-	originService := service.NewOriginService(originRepo)
-	originHandler := http.NewOriginHandler(originService, validate)
+
+	snapshotRepo := repository.NewSnapshotRepository(database, config.DB)
+	if err := snapshotRepo.CreateIndexes(ctx); err != nil {
+		slog.Error("Error creating snapshot indexes", "error", err)
+	}
+	snapshotService := service.NewSnapshotService(snapshotRepo, originRepo)
+	snapshotHandler := http.NewSnapshotHandler(snapshotService)
+
+	go scheduler.NewSnapshotScheduler(snapshotService, config.Scheduler).Start(ctx)
 
 	transactionRepo := repository.NewTransactionRepository(database, config.DB)
-	transactionService := service.NewTransactionService(transactionRepo, originRepo, txManager)
+	if err := transactionRepo.CreateIndexes(ctx); err != nil {
+		slog.Error("Error creating transaction indexes", "error", err)
+	}
+
+	auditRepo := repository.NewAuditRepository(database, config.DB)
+	if err := auditRepo.CreateIndexes(ctx); err != nil {
+		slog.Error("Error creating audit indexes", "error", err)
+	}
+	auditService := service.NewAuditService(auditRepo)
+	auditHandler := http.NewAuditHandler(auditService)
+
+	ledgerRepo := repository.NewLedgerRepository(database, config.DB)
+	if err := ledgerRepo.CreateIndexes(ctx); err != nil {
+		slog.Error("Error creating ledger indexes", "error", err)
+	}
+	ledgerService := service.NewLedgerService(ledgerRepo, originRepo, transactionRepo, txManager)
+	ledgerHandler := http.NewLedgerHandler(ledgerService)
+
+	debtRepo := repository.NewDebtRepository(database, config.DB)
+
+	originService := service.NewOriginService(originRepo, transactionRepo, debtRepo, snapshotService, ledgerService, auditService, txManager)
+	originHandler := http.NewOriginHandler(originService, validate)
+
+	attachmentRepo := repository.NewAttachmentRepository(database, config.DB)
+	attachmentService := service.NewAttachmentService(attachmentRepo, transactionRepo, fileAdapter)
+	attachmentHandler := http.NewAttachmentHandler(attachmentService)
+
+	transactionService := service.NewTransactionService(transactionRepo, originRepo, txManager, attachmentService, snapshotService, ledgerService, auditService)
 	transactionHandler := http.NewTransactionHandler(transactionService, validate)
 
+	go scheduler.NewTrashScheduler(transactionService, originService, config.Scheduler).Start(ctx)
+
+	debtService := service.NewDebtService(debtRepo, originRepo, transactionService, txManager)
+	debtHandler := http.NewDebtHandler(debtService)
+
+	reconciliationRepo := repository.NewReconciliationRepository(database, config.DB)
+	if err := reconciliationRepo.CreateIndexes(ctx); err != nil {
+		slog.Error("Error creating reconciliation indexes", "error", err)
+	}
+	reconciliationService := service.NewReconciliationService(reconciliationRepo, originRepo, transactionService, txManager)
+	reconciliationHandler := http.NewReconciliationHandler(reconciliationService)
+
+	balanceRepo := repository.NewBalanceRepository(database, config.DB)
+	balanceService := service.NewBalanceService(balanceRepo, originRepo, transactionService, ledgerService, txManager)
+	balanceHandler := http.NewBalanceHandler(balanceService)
+
+	statementService := service.NewStatementService(originRepo, transactionRepo)
+	statementHandler := http.NewStatementHandler(statementService)
+
+	expectedItemRepo := repository.NewExpectedItemRepository(database, config.DB)
+	forecastService := service.NewForecastService(expectedItemRepo, originRepo, transactionRepo)
+	forecastHandler := http.NewForecastHandler(forecastService)
+
+	tagRepo := repository.NewTagRepository(database, config.DB)
+	if err := tagRepo.CreateIndexes(ctx); err != nil {
+		slog.Error("Error creating tag indexes", "error", err)
+	}
+	tagService := service.NewTagService(tagRepo, transactionRepo, txManager)
+	tagHandler := http.NewTagHandler(tagService, validate)
+
 	authRepo := repository.NewAuthRepository(database, config.DB)
-	imageAdapter := adapter.NewImageAdapter(storage)
-	authService := service.NewAuthService(authRepo, transactionRepo, imageAdapter)
+	authService := service.NewAuthService(authRepo, transactionRepo, imageAdapter, auditService, txManager)
 	authHandler := http.NewAuthHandler(authService, validate, config.Token)
 
-	mailAdapter := mail.NewMailReportAdapter(config.Mail)
-	reportService := service.NewReportService(authService, transactionService, originService, mailAdapter)
-	reportHandler := http.NewReportHandler(reportService)
+	outboxRepo := repository.NewOutboxRepository(database, config.DB)
+	if err := outboxRepo.CreateIndexes(ctx); err != nil {
+		slog.Error("Error creating outbox indexes", "error", err)
+	}
+	outboxService := service.NewOutboxService(outboxRepo, mail.NewSMTPSender(config.Mail), config.Mail.MaxAttempts, config.Mail.RetryBackoff)
+	outboxHandler := http.NewOutboxHandler(outboxService)
+
+	go scheduler.NewOutboxWorker(outboxService, config.Scheduler.OutboxInterval).Start(ctx)
 
-	router, err := http.NewRouter(config, *transactionHandler, *authHandler, *originHandler, *reportHandler)
+	rules := reportRules(config.Report)
+
+	analyticsRepo := repository.NewAnalyticsRepository(database, config.DB)
+	analyticsService := service.NewAnalyticsService(analyticsRepo, rules)
+	analyticsHandler := http.NewAnalyticsHandler(analyticsService)
+
+	pdfRenderer := pdf.NewReportRenderer(config.Mail.BrandName)
+
+	var attachmentRenderer port.ReportRenderer
+	if config.Mail.AttachPDF {
+		attachmentRenderer = pdfRenderer
+	}
+
+	mailAdapter, err := mail.NewMailReportAdapter(outboxService, config.Mail, attachmentRenderer)
+	if err != nil {
+		slog.Error("Error loading mail templates", "error", err)
+		os.Exit(1)
+	}
+	reportService := service.NewReportService(authService, transactionService, originService, mailAdapter, pdfRenderer, rules)
+
+	deliveryRepo := repository.NewReportDeliveryRepository(database, config.DB)
+	if err := deliveryRepo.CreateIndexes(ctx); err != nil {
+		slog.Error("Error creating report delivery indexes", "error", err)
+	}
+	deliveryService := service.NewReportDeliveryService(authRepo, deliveryRepo, reportService, outboxService, config.Scheduler.ReportConcurrency, config.Scheduler.ReportDay)
+	reportHandler := http.NewReportHandler(reportService, deliveryService)
+
+	go scheduler.NewReportScheduler(deliveryService, config.Scheduler).Start(ctx)
+
+	fileHandler := http.NewFileHandler(fileReader)
+
+	router, err := http.NewRouter(config, *transactionHandler, *authHandler, *originHandler, *reportHandler, *tagHandler, *attachmentHandler, *fileHandler, *outboxHandler, *analyticsHandler, *snapshotHandler, *forecastHandler, *statementHandler, *debtHandler, *reconciliationHandler, *balanceHandler, *ledgerHandler, *auditHandler)
 	if err != nil {
 		slog.Error("Error initializing router", "error", err)
 		os.Exit(1)
@@ -77,3 +182,48 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// reportRules overrides the default report rules with the configured subjects.
+func reportRules(config *config.Report) domain.ReportRules {
+
+	rules := domain.DefaultReportRules()
+
+	if len(config.IncomeSubjects) > 0 {
+		rules.IncomeSubjects = config.IncomeSubjects
+	}
+	if len(config.ExpenseSubjects) > 0 {
+		rules.ExpenseSubjects = config.ExpenseSubjects
+	}
+
+	return rules
+}
+
+// newStorage builds the image and file adapters for the configured provider.
+// Only the local provider needs the API to serve its files.
+func newStorage(ctx context.Context, config *config.ImageCloud) (port.ImageAdapter, port.FileAdapter, port.SignedFileReader, error) {
+
+	switch config.Provider {
+	case "local":
+		storage, err := local.New(config)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		return storage, storage, storage, nil
+
+	case "s3":
+		storage, err := s3.New(config)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		return storage, storage, nil, nil
+
+	case "cloudinary":
+		storage, err := cloud.New(ctx, config)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		return adapter.NewImageAdapter(storage), adapter.NewFileAdapter(storage), nil, nil
+	}
+
+	return nil, nil, nil, fmt.Errorf("unknown storage provider %q", config.Provider)
+}
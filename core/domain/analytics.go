@@ -0,0 +1,64 @@
+package domain
+
+import "time"
+
+// MonthlyTotals are the income and expenses counted in one calendar month.
+type MonthlyTotals struct {
+	Month       string  `json:"month"`
+	Income      float64 `json:"income"`
+	Expenses    float64 `json:"expenses"`
+	Savings     float64 `json:"savings"`
+	SavingsRate float64 `json:"savings_rate"`
+}
+
+// CategoryMonthTotal is what was spent on one category in one month.
+type CategoryMonthTotal struct {
+	Month    string  `json:"month"`
+	Category string  `json:"category"`
+	Expenses float64 `json:"expenses"`
+}
+
+type CategoryTrend struct {
+	Category string               `json:"category"`
+	Total    float64              `json:"total"`
+	Months   []CategoryMonthTotal `json:"months"`
+}
+
+type PeriodTotals struct {
+	From        string  `json:"from"`
+	To          string  `json:"to"`
+	Income      float64 `json:"income"`
+	Expenses    float64 `json:"expenses"`
+	Savings     float64 `json:"savings"`
+	SavingsRate float64 `json:"savings_rate"`
+}
+
+// PeriodDelta is the change from a base period. The percentages are nil when
+// the base value is zero.
+type PeriodDelta struct {
+	Income         float64  `json:"income"`
+	Expenses       float64  `json:"expenses"`
+	Savings        float64  `json:"savings"`
+	SavingsRate    float64  `json:"savings_rate"`
+	IncomePercent  *float64 `json:"income_percent"`
+	ExpensePercent *float64 `json:"expense_percent"`
+}
+
+type PeriodComparison struct {
+	Current        PeriodTotals `json:"current"`
+	Previous       PeriodTotals `json:"previous"`
+	LastYear       PeriodTotals `json:"last_year"`
+	VersusPrevious PeriodDelta  `json:"versus_previous"`
+	VersusLastYear PeriodDelta  `json:"versus_last_year"`
+}
+
+type Trends struct {
+	Series     []MonthlyTotals  `json:"series"`
+	Categories []CategoryTrend  `json:"categories"`
+	Comparison PeriodComparison `json:"comparison"`
+}
+
+// MonthKey identifies the calendar month of t in analytics series.
+func MonthKey(t time.Time) string {
+	return t.Format("2006-01")
+}
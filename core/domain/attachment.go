@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+type Attachment struct {
+	ID            string    `json:"_id" bson:"_id,omitempty"`
+	UserId        string    `json:"user_id" bson:"user_id"`
+	TransactionId string    `json:"transaction_id" bson:"transaction_id"`
+	FileName      string    `json:"file_name" bson:"file_name"`
+	ContentType   string    `json:"content_type" bson:"content_type"`
+	Size          int64     `json:"size" bson:"size"`
+	SecureUrl     string    `json:"secure_url" bson:"-"`
+	PublicId      string    `json:"public_id" bson:"public_id"`
+	CreatedAt     time.Time `json:"created_at" bson:"created_at"`
+}
+
+type StoredFile struct {
+	SecureUrl string `json:"secure_url"`
+	PublicId  string `json:"public_id"`
+	Size      int64  `json:"size"`
+}
@@ -0,0 +1,184 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// Audited entities.
+const (
+	AuditTransaction = "transaction"
+	AuditOrigin      = "origin"
+	AuditUser        = "user"
+)
+
+const (
+	AuditCreate  = "create"
+	AuditUpdate  = "update"
+	AuditDelete  = "delete"
+	AuditRestore = "restore"
+)
+
+// ActorContextKey is the context key holding the id of the authenticated
+// user making a change.
+const ActorContextKey = "userID"
+
+// Fields left out of the diffs: identifiers and timestamps every write
+// touches, and the origin joined into a transaction when it is read.
+var auditIgnoredFields = map[string]bool{"_id": true, "updated_at": true, "origin": true}
+
+// Fields whose values are never stored, only the fact they changed.
+var auditRedactedFields = map[string]bool{"password": true}
+
+const redactedValue = "[redacted]"
+
+type FieldChange struct {
+	Field  string `json:"field" bson:"field"`
+	Before any    `json:"before,omitempty" bson:"before,omitempty"`
+	After  any    `json:"after,omitempty" bson:"after,omitempty"`
+}
+
+// AuditRecord is a change made by ActorId to an entity owned by UserId.
+type AuditRecord struct {
+	ID        string        `json:"_id" bson:"_id,omitempty"`
+	UserId    string        `json:"user_id" bson:"user_id"`
+	ActorId   string        `json:"actor_id,omitempty" bson:"actor_id,omitempty"`
+	Entity    string        `json:"entity" bson:"entity"`
+	EntityId  string        `json:"entity_id" bson:"entity_id"`
+	Action    string        `json:"action" bson:"action"`
+	Changes   []FieldChange `json:"changes" bson:"changes"`
+	CreatedAt time.Time     `json:"created_at" bson:"created_at"`
+}
+
+// ActorFromContext returns the id of the user making the request, if any.
+func ActorFromContext(ctx context.Context) string {
+
+	actor, _ := ctx.Value(ActorContextKey).(string)
+	return actor
+}
+
+// Diff lists the fields that differ between before and after, compared by
+// their JSON representation. A field missing from one side was left out for
+// holding its zero value, so a change to or from zero is still listed. A nil
+// before lists every set field of after as created and a nil after every set
+// field of before as deleted.
+func Diff(before, after any) []FieldChange {
+
+	beforeFields, hasBefore := auditFields(before)
+	afterFields, hasAfter := auditFields(after)
+
+	names := make(map[string]bool, len(beforeFields)+len(afterFields))
+	for name := range beforeFields {
+		names[name] = true
+	}
+	for name := range afterFields {
+		names[name] = true
+	}
+
+	changes := []FieldChange{}
+	for name := range names {
+
+		if auditIgnoredFields[name] {
+			continue
+		}
+
+		beforeValue, inBefore := beforeFields[name]
+		afterValue, inAfter := afterFields[name]
+
+		if hasBefore && hasAfter {
+			if !inBefore {
+				beforeValue = zeroLike(afterValue)
+			}
+			if !inAfter {
+				afterValue = zeroLike(beforeValue)
+			}
+		} else if isEmptyJSON(beforeValue) && isEmptyJSON(afterValue) {
+			// nothing to compare with on create and delete: unset fields are
+			// left out
+			continue
+		}
+
+		if reflect.DeepEqual(beforeValue, afterValue) {
+			continue
+		}
+
+		if auditRedactedFields[name] {
+			beforeValue, afterValue = redact(beforeValue), redact(afterValue)
+		}
+
+		changes = append(changes, FieldChange{Field: name, Before: beforeValue, After: afterValue})
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+
+	return changes
+}
+
+// auditFields decodes the JSON fields of value. It reports false for a nil
+// value.
+func auditFields(value any) (map[string]any, bool) {
+
+	fields := map[string]any{}
+
+	if value == nil || (reflect.ValueOf(value).Kind() == reflect.Pointer && reflect.ValueOf(value).IsNil()) {
+		return fields, false
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fields, false
+	}
+
+	_ = json.Unmarshal(data, &fields)
+
+	return fields, true
+}
+
+// isEmptyJSON reports whether value is the zero value of its JSON type.
+func isEmptyJSON(value any) bool {
+
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == "" || v == "0001-01-01T00:00:00Z"
+	case bool:
+		return !v
+	case float64:
+		return v == 0
+	case []any:
+		return len(v) == 0
+	case map[string]any:
+		return len(v) == 0
+	}
+
+	return false
+}
+
+// zeroLike is the zero value of value's JSON type, the one an omitempty field
+// left out of the JSON holds. Objects and arrays left out are nil.
+func zeroLike(value any) any {
+
+	switch value.(type) {
+	case string:
+		return ""
+	case bool:
+		return false
+	case float64:
+		return float64(0)
+	}
+
+	return nil
+}
+
+func redact(value any) any {
+
+	if value == nil {
+		return nil
+	}
+
+	return redactedValue
+}
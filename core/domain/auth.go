@@ -3,15 +3,25 @@ package domain
 import "time"
 
 type User struct {
-	ID            string    `json:"_id" bson:"_id,omitempty"`
-	Username      string    `json:"username"`
-	Email         string    `json:"email"`
-	Password      string    `json:"password"`
-	Role          string    `json:"role"`
-	ProfileImage  string    `json:"profile_image"`
-	PublicIdImage string    `json:"public_id"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	ID                  string    `json:"_id" bson:"_id,omitempty"`
+	Username            string    `json:"username"`
+	Email               string    `json:"email"`
+	Password            string    `json:"password"`
+	Role                string    `json:"role"`
+	ProfileImage        string    `json:"profile_image"`
+	PublicIdImage       string    `json:"public_id"`
+	MonthlyReportOptOut bool      `json:"monthly_report_opt_out" bson:"monthly_report_opt_out"`
+	Locale              string    `json:"locale,omitempty" bson:"locale,omitempty"`
+	Currency            string    `json:"currency,omitempty" bson:"currency,omitempty"`
+	CreatedAt           time.Time `json:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at"`
+}
+
+// UserPreferences holds the preferences to change; nil fields are left as they are.
+type UserPreferences struct {
+	MonthlyReportOptOut *bool
+	Locale              *string
+	Currency            *string
 }
 
 type Image struct {
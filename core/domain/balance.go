@@ -0,0 +1,36 @@
+package domain
+
+// OriginActivity totals the transactions booked on an origin.
+type OriginActivity struct {
+	OriginId     string  `bson:"_id"`
+	Income       float64 `bson:"income"`
+	Output       float64 `bson:"output"`
+	Transactions int     `bson:"transactions"`
+}
+
+// BalanceCheck compares an origin's stored Total with the one computed from
+// its opening balance and transactions. Origins without an opening balance
+// cannot be checked; repairing them takes the stored Total as correct and
+// derives the opening balance from it. Ledger is the origin's balance in the
+// journal, left nil while the origin has not been journaled yet.
+type BalanceCheck struct {
+	OriginId         string   `json:"origin_id"`
+	Name             string   `json:"name"`
+	Kind             string   `json:"kind"`
+	OpeningBalance   float64  `json:"opening_balance"`
+	Transactions     int      `json:"transactions"`
+	Stored           float64  `json:"stored"`
+	Computed         float64  `json:"computed"`
+	Difference       float64  `json:"difference"`
+	Ledger           *float64 `json:"ledger,omitempty"`
+	LedgerDifference float64  `json:"ledger_difference"`
+	MissingOpening   bool     `json:"missing_opening,omitempty"`
+	Repaired         bool     `json:"repaired"`
+}
+
+type BalanceAudit struct {
+	UserId        string         `json:"user_id"`
+	Discrepancies int            `json:"discrepancies"`
+	Repaired      bool           `json:"repaired"`
+	Origins       []BalanceCheck `json:"origins"`
+}
@@ -0,0 +1,135 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+// maxAmortizationPayments caps a schedule at a hundred years of payments.
+const maxAmortizationPayments = 1200
+
+// Debt is an amortizing loan (a car loan, a mortgage) tracked against a loan
+// origin. Payments are due monthly starting one month after StartDate.
+type Debt struct {
+	ID           string    `json:"_id" bson:"_id,omitempty"`
+	UserId       string    `json:"user_id" bson:"user_id"`
+	OriginId     string    `json:"origin_id" bson:"origin_id"`
+	Name         string    `json:"name" bson:"name"`
+	Principal    float64   `json:"principal" bson:"principal"`
+	InterestRate float64   `json:"interest_rate" bson:"interest_rate"`
+	TermMonths   int       `json:"term_months" bson:"term_months"`
+	StartDate    time.Time `json:"start_date" bson:"start_date"`
+	CreatedAt    time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at,omitempty" bson:"updated_at"`
+}
+
+// DebtPayment is how a loan payment splits between principal and interest.
+type DebtPayment struct {
+	DebtId    string  `json:"debt_id" bson:"debt_id"`
+	Principal float64 `json:"principal" bson:"principal"`
+	Interest  float64 `json:"interest" bson:"interest"`
+}
+
+type AmortizationRow struct {
+	Number    int       `json:"number"`
+	Date      time.Time `json:"date"`
+	Payment   float64   `json:"payment"`
+	Principal float64   `json:"principal"`
+	Interest  float64   `json:"interest"`
+	Extra     float64   `json:"extra,omitempty"`
+	Balance   float64   `json:"balance"`
+}
+
+type DebtStatus struct {
+	Debt             Debt       `json:"debt"`
+	MonthlyPayment   float64    `json:"monthly_payment"`
+	PrincipalPaid    float64    `json:"principal_paid"`
+	InterestPaid     float64    `json:"interest_paid"`
+	RemainingBalance float64    `json:"remaining_balance"`
+	PaymentsMade     int        `json:"payments_made"`
+	NextPaymentDate  *time.Time `json:"next_payment_date,omitempty"`
+	PayoffDate       *time.Time `json:"payoff_date,omitempty"`
+}
+
+// DebtSimulation compares paying off the remaining balance on schedule with
+// paying ExtraMonthly more each month plus LumpSum right away.
+type DebtSimulation struct {
+	ExtraMonthly       float64           `json:"extra_monthly"`
+	LumpSum            float64           `json:"lump_sum"`
+	BaselinePayoffDate *time.Time        `json:"baseline_payoff_date,omitempty"`
+	BaselineInterest   float64           `json:"baseline_interest"`
+	PayoffDate         *time.Time        `json:"payoff_date,omitempty"`
+	TotalInterest      float64           `json:"total_interest"`
+	InterestSaved      float64           `json:"interest_saved"`
+	MonthsSaved        int               `json:"months_saved"`
+	Schedule           []AmortizationRow `json:"schedule"`
+}
+
+// MonthlyRate is the debt's interest rate per month, as a fraction.
+func (d Debt) MonthlyRate() float64 {
+	return d.InterestRate / 12 / 100
+}
+
+// MonthlyPayment is the fixed payment that amortizes Principal over
+// TermMonths at InterestRate.
+func (d Debt) MonthlyPayment() float64 {
+
+	if d.TermMonths <= 0 {
+		return 0
+	}
+
+	rate := d.MonthlyRate()
+	if rate == 0 {
+		return math.Round(d.Principal/float64(d.TermMonths)*100) / 100
+	}
+
+	payment := d.Principal * rate / (1 - math.Pow(1+rate, -float64(d.TermMonths)))
+
+	return math.Round(payment*100) / 100
+}
+
+// PaymentDate returns the due date of the n-th payment, counting from 1.
+func (d Debt) PaymentDate(n int) time.Time {
+	return NextOccurrence(SnapshotDay(d.StartDate), FrequencyMonthly, n)
+}
+
+// Amortize schedules the payments that pay off balance, the first one due on
+// the firstPayment-th payment date of the debt. Every payment is the debt's
+// monthly payment plus extra, the last one reduced to what is left.
+func (d Debt) Amortize(balance float64, firstPayment int, extra float64) []AmortizationRow {
+
+	var rows []AmortizationRow
+
+	rate := d.MonthlyRate()
+	payment := d.MonthlyPayment()
+
+	// a payment that does not cover the interest never pays the debt off
+	if payment+extra <= round2(balance*rate) {
+		return rows
+	}
+
+	for n := firstPayment; balance > 0.005 && n < firstPayment+maxAmortizationPayments; n++ {
+
+		interest := round2(balance * rate)
+		principal := math.Min(balance, round2(payment-interest))
+		rowExtra := math.Min(balance-principal, extra)
+
+		balance = round2(balance - principal - rowExtra)
+
+		rows = append(rows, AmortizationRow{
+			Number:    n,
+			Date:      d.PaymentDate(n),
+			Payment:   round2(interest + principal + rowExtra),
+			Principal: principal,
+			Interest:  interest,
+			Extra:     rowExtra,
+			Balance:   balance,
+		})
+	}
+
+	return rows
+}
+
+func round2(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -13,6 +13,7 @@ var (
 	ErrGettingFile                = errors.New("cannot get the file from request")
 	ErrFileSize                   = errors.New("file size exceeds 5MB")
 	ErrFileType                   = errors.New("invalid file type")
+	ErrAttachmentSize             = errors.New("attachment size exceeds 10MB")
 	ErrTokenDuration              = errors.New("invalid token duration format")
 	ErrTokenCreation              = errors.New("error creating token")
 	ErrExpiredToken               = errors.New("access token has expired")
@@ -23,4 +24,25 @@ var (
 	ErrInvalidAuthorizationType   = errors.New("authorization type is not supported")
 	ErrUnauthorized               = errors.New("user is unauthorized to access the resource")
 	ErrForbidden                  = errors.New("user is forbidden to access the resource")
+	ErrInvalidCursor              = errors.New("pagination cursor is invalid")
+	ErrCursorSort                 = errors.New("cursor pagination only supports sorting by date")
+	ErrEmptySearch                = errors.New("search text is required")
+	ErrInvalidPeriod              = errors.New("report period is invalid")
+	ErrMessageAlreadySent         = errors.New("message was already sent")
+	ErrInvalidLocale              = errors.New("locale is not a valid language tag")
+	ErrInvalidCurrency            = errors.New("currency is not a valid ISO 4217 code")
+	ErrInvalidOriginKind          = errors.New("origin kind is not supported")
+	ErrInvalidOriginFields        = errors.New("origin fields do not apply to its kind")
+	ErrNoStatementCycle           = errors.New("origin is not a credit card with a statement day")
+	ErrInvalidDebtOrigin          = errors.New("debt must reference a loan origin of the same user")
+	ErrDebtPaidOff                = errors.New("debt is already paid off")
+	ErrPaymentBelowInterest       = errors.New("payment does not cover the accrued interest")
+	ErrReconciledPeriod           = errors.New("transaction falls in a reconciled period")
+	ErrReconciliationClosed       = errors.New("reconciliation is already completed")
+	ErrOriginInUse                = errors.New("origin still has transactions")
+	ErrOriginHasDebt              = errors.New("origin still backs a debt")
+	ErrInvalidOriginDeletion      = errors.New("origin deletion mode or target is invalid")
+	ErrArchivedOrigin             = errors.New("origin is archived")
+	ErrOriginKindChange           = errors.New("origin kind cannot change between asset and liability")
+	ErrDebtPaymentEdit            = errors.New("debt payment amount, type, origin and date cannot be edited")
 )
@@ -0,0 +1,115 @@
+package domain
+
+import "time"
+
+const (
+	FrequencyOnce     = "once"
+	FrequencyWeekly   = "weekly"
+	FrequencyBiweekly = "biweekly"
+	FrequencyMonthly  = "monthly"
+)
+
+// ExpectedItem is a future income or expense the user declares for the
+// forecast, either a one-off on StartDate or repeating until EndDate. An item
+// with a PersonOrBusiness replaces the recurring pattern detected for that
+// payee on the same origin.
+type ExpectedItem struct {
+	ID               string     `json:"_id" bson:"_id,omitempty"`
+	UserId           string     `json:"user_id" bson:"user_id"`
+	OriginId         string     `json:"origin_id" bson:"origin_id"`
+	Type             string     `json:"type" bson:"type"`
+	Amount           float64    `json:"amount" bson:"amount"`
+	Subject          string     `json:"subject" bson:"subject"`
+	PersonOrBusiness string     `json:"person_business,omitempty" bson:"person_business,omitempty"`
+	Description      string     `json:"description,omitempty" bson:"description,omitempty"`
+	Frequency        string     `json:"frequency" bson:"frequency"`
+	StartDate        time.Time  `json:"start_date" bson:"start_date"`
+	EndDate          *time.Time `json:"end_date,omitempty" bson:"end_date,omitempty"`
+	CreatedAt        time.Time  `json:"created_at" bson:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at,omitempty" bson:"updated_at"`
+}
+
+// Occurrences returns the days in [from, to] the item falls on.
+func (ei ExpectedItem) Occurrences(from, to time.Time) []time.Time {
+
+	var days []time.Time
+
+	start := SnapshotDay(ei.StartDate)
+	for i := 0; ; i++ {
+		day := NextOccurrence(start, ei.Frequency, i)
+		if day.After(to) || (ei.EndDate != nil && day.After(SnapshotDay(*ei.EndDate))) {
+			break
+		}
+		if !day.Before(from) {
+			days = append(days, day)
+		}
+		if ei.Frequency == FrequencyOnce || ei.Frequency == "" {
+			break
+		}
+	}
+
+	return days
+}
+
+// NextOccurrence returns the n-th repetition of start at frequency. Monthly
+// repetitions keep the day of the month, clamped to the month's last day.
+func NextOccurrence(start time.Time, frequency string, n int) time.Time {
+
+	switch frequency {
+	case FrequencyWeekly:
+		return start.AddDate(0, 0, 7*n)
+	case FrequencyBiweekly:
+		return start.AddDate(0, 0, 14*n)
+	case FrequencyMonthly:
+		month := time.Date(start.Year(), start.Month()+time.Month(n), 1, 0, 0, 0, 0, start.Location())
+		lastDay := month.AddDate(0, 1, -1).Day()
+		day := start.Day()
+		if day > lastDay {
+			day = lastDay
+		}
+		return time.Date(month.Year(), month.Month(), day, 0, 0, 0, 0, start.Location())
+	default:
+		return start
+	}
+}
+
+// RecurringPattern is a series of similar transactions detected in the
+// user's history, repeating at a regular interval.
+type RecurringPattern struct {
+	OriginId         string    `json:"origin_id"`
+	Type             string    `json:"type"`
+	Subject          string    `json:"subject"`
+	PersonOrBusiness string    `json:"person_business"`
+	Amount           float64   `json:"amount"`
+	Frequency        string    `json:"frequency"`
+	Occurrences      int       `json:"occurrences"`
+	LastDate         time.Time `json:"last_date"`
+}
+
+type ForecastPoint struct {
+	Date           string  `json:"date"`
+	Balance        float64 `json:"balance"`
+	BelowThreshold bool    `json:"below_threshold"`
+}
+
+type OriginForecast struct {
+	OriginId           string          `json:"origin_id"`
+	OriginName         string          `json:"origin_name"`
+	StartBalance       float64         `json:"start_balance"`
+	EndBalance         float64         `json:"end_balance"`
+	LowestBalance      float64         `json:"lowest_balance"`
+	LowestDate         string          `json:"lowest_date"`
+	DailyDiscretionary float64         `json:"daily_discretionary"`
+	Alerts             []string        `json:"alerts"`
+	Points             []ForecastPoint `json:"points"`
+}
+
+// Forecast projects the user's origin balances from the day after From
+// through To. Alerts list the days a balance falls below Threshold.
+type Forecast struct {
+	From      string             `json:"from"`
+	To        string             `json:"to"`
+	Threshold float64            `json:"threshold"`
+	Origins   []OriginForecast   `json:"origins"`
+	Patterns  []RecurringPattern `json:"patterns"`
+}
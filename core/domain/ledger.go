@@ -0,0 +1,166 @@
+package domain
+
+import (
+	"math"
+	"strings"
+	"time"
+)
+
+// Account prefixes of the ledger. Origins are balance sheet accounts; income
+// and expense accounts are keyed by the transaction's source or category, and
+// opening balances and hand-made corrections are booked against equity.
+const (
+	AccountOrigin  = "origin"
+	AccountIncome  = "income"
+	AccountExpense = "expense"
+	AccountEquity  = "equity"
+
+	OpeningEquityAccount = AccountEquity + ":opening"
+)
+
+const (
+	EntryTransaction = "transaction"
+	EntryReversal    = "reversal"
+	EntryOpening     = "opening"
+	EntryAdjustment  = "adjustment"
+)
+
+// Posting is one line of a journal entry: either a debit or a credit of
+// Amount on Account.
+type Posting struct {
+	Account string  `json:"account" bson:"account"`
+	Debit   float64 `json:"debit" bson:"debit"`
+	Credit  float64 `json:"credit" bson:"credit"`
+}
+
+// JournalEntry is an immutable, balanced set of postings. Entries are never
+// updated nor deleted: a changed or deleted transaction is undone by a
+// reversal entry.
+type JournalEntry struct {
+	ID            string    `json:"_id" bson:"_id,omitempty"`
+	UserId        string    `json:"user_id" bson:"user_id"`
+	Kind          string    `json:"kind" bson:"kind"`
+	TransactionId string    `json:"transaction_id,omitempty" bson:"transaction_id,omitempty"`
+	OriginId      string    `json:"origin_id,omitempty" bson:"origin_id,omitempty"`
+	Date          time.Time `json:"date" bson:"date"`
+	Memo          string    `json:"memo,omitempty" bson:"memo,omitempty"`
+	Postings      []Posting `json:"postings" bson:"postings"`
+	CreatedAt     time.Time `json:"created_at" bson:"created_at"`
+}
+
+// AccountTotals sums the debits and credits posted to an account.
+type AccountTotals struct {
+	Account string  `bson:"_id"`
+	Debit   float64 `bson:"debit"`
+	Credit  float64 `bson:"credit"`
+}
+
+// AccountBalance is an account's balance on its normal side: debits minus
+// credits for assets and expenses, credits minus debits for liabilities,
+// income and equity.
+type AccountBalance struct {
+	Account   string  `json:"account"`
+	Name      string  `json:"name"`
+	Debit     float64 `json:"debit"`
+	Credit    float64 `json:"credit"`
+	Balance   float64 `json:"balance"`
+	Liability bool    `json:"liability,omitempty"`
+}
+
+type TrialBalance struct {
+	Accounts    []AccountBalance `json:"accounts"`
+	TotalDebit  float64          `json:"total_debit"`
+	TotalCredit float64          `json:"total_credit"`
+	Balanced    bool             `json:"balanced"`
+}
+
+func OriginAccount(originId string) string {
+	return AccountOrigin + ":" + originId
+}
+
+// AccountKind returns the prefix of account.
+func AccountKind(account string) string {
+
+	kind, _, _ := strings.Cut(account, ":")
+	return kind
+}
+
+// Balanced reports whether the entry's debits equal its credits.
+func (e JournalEntry) Balanced() bool {
+
+	var debit, credit float64
+	for _, posting := range e.Postings {
+		debit += posting.Debit
+		credit += posting.Credit
+	}
+
+	return len(e.Postings) > 1 && math.Abs(debit-credit) < 0.005
+}
+
+// Reversed returns the postings with debits and credits swapped.
+func (e JournalEntry) Reversed() []Posting {
+
+	postings := make([]Posting, len(e.Postings))
+	for i, posting := range e.Postings {
+		postings[i] = Posting{Account: posting.Account, Debit: posting.Credit, Credit: posting.Debit}
+	}
+
+	return postings
+}
+
+// TransactionPostings books the transaction against its origin: an Income
+// debits the origin and credits its income account, an Output debits its
+// expense account and credits the origin. Transactions without an origin have
+// no effect on any balance and post nothing.
+func TransactionPostings(transaction Transaction) []Posting {
+
+	if transaction.OriginId == nil || *transaction.OriginId == "" || transaction.Amount == 0 {
+		return nil
+	}
+
+	origin := OriginAccount(*transaction.OriginId)
+
+	if transaction.Type == "Income" {
+		return []Posting{
+			{Account: origin, Debit: transaction.Amount},
+			{Account: AccountIncome + ":" + firstNonEmpty(transaction.IncomeSource, transaction.Subject), Credit: transaction.Amount},
+		}
+	}
+
+	return []Posting{
+		{Account: AccountExpense + ":" + firstNonEmpty(transaction.OutputCategory, transaction.Subject), Debit: transaction.Amount},
+		{Account: origin, Credit: transaction.Amount},
+	}
+}
+
+// OpeningPostings moves amount into the origin's Total against opening
+// equity; a negative amount takes it out.
+func OpeningPostings(origin Origin, amount float64) []Posting {
+
+	if amount == 0 {
+		return nil
+	}
+
+	debit, credit := OriginAccount(origin.ID), OpeningEquityAccount
+	if (amount < 0) != origin.IsLiability() {
+		debit, credit = credit, debit
+	}
+
+	amount = math.Abs(amount)
+
+	return []Posting{
+		{Account: debit, Debit: amount},
+		{Account: credit, Credit: amount},
+	}
+}
+
+func firstNonEmpty(values ...string) string {
+
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			return value
+		}
+	}
+
+	return "Other"
+}
@@ -2,21 +2,152 @@ package domain
 
 import "time"
 
+const (
+	OriginCash       = "cash"
+	OriginChecking   = "checking"
+	OriginSavings    = "savings"
+	OriginCreditCard = "credit_card"
+	OriginLoan       = "loan"
+	OriginInvestment = "investment"
+)
+
+// Ways to delete an origin that transactions still reference.
+const (
+	OriginDeleteRefuse   = "refuse"
+	OriginDeleteReassign = "reassign"
+	OriginDeleteArchive  = "archive"
+)
+
+// OriginDeletion tells what to do with the transactions of a deleted origin:
+// refuse the deletion while there are any, move them to TargetId, or archive
+// the origin instead of deleting it.
+type OriginDeletion struct {
+	Mode     string
+	TargetId string
+}
+
+// OriginKinds lists the supported origin kinds. Origins stored before kinds
+// existed have none and are handled as checking accounts.
+var OriginKinds = []string{OriginCash, OriginChecking, OriginSavings, OriginCreditCard, OriginLoan, OriginInvestment}
+
+// Origin is where money is held or owed. The Total of an asset is what it
+// holds; the Total of a liability (credit card, loan) is what is owed, so an
+// Output raises it and an Income (a payment) lowers it.
 type Origin struct {
-	ID          string    `json:"_id" bson:"_id,omitempty"`
-	UserId      string    `json:"user_id" bson:"user_id" validate:"required"`
-	Name        string    `json:"name" bson:"name" validate:"required"`
-	Total       float64   `json:"total" bson:"total" validate:"required"`
-	Description string    `json:"description,omitempty" bson:"description,omitempty"`
-	CreatedAt   time.Time `json:"created_at" bson:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at,omitempty" bson:"updated_at"`
+	ID           string    `json:"_id" bson:"_id,omitempty"`
+	UserId       string    `json:"user_id" bson:"user_id" validate:"required"`
+	Name         string    `json:"name" bson:"name" validate:"required"`
+	Kind         string    `json:"kind,omitempty" bson:"kind,omitempty"`
+	Total        float64   `json:"total" bson:"total" validate:"required"`
+	CreditLimit  *float64  `json:"credit_limit,omitempty" bson:"credit_limit,omitempty"`
+	StatementDay int       `json:"statement_day,omitempty" bson:"statement_day,omitempty"`
+	InterestRate *float64  `json:"interest_rate,omitempty" bson:"interest_rate,omitempty"`
+	Description  string    `json:"description,omitempty" bson:"description,omitempty"`
+	CreatedAt    time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at,omitempty" bson:"updated_at"`
+
+	// Credit card payment terms: days from closing to the due date and
+	// the minimum payment as a percentage of the closing balance.
+	PaymentDueDays     int      `json:"payment_due_days,omitempty" bson:"payment_due_days,omitempty"`
+	MinimumPaymentRate *float64 `json:"minimum_payment_rate,omitempty" bson:"minimum_payment_rate,omitempty"`
+
+	// Last completed reconciliation: transactions dated on or before
+	// ReconciledThrough are locked.
+	ReconciledThrough *time.Time `json:"reconciled_through,omitempty" bson:"reconciled_through,omitempty"`
+	ReconciledBalance float64    `json:"reconciled_balance,omitempty" bson:"reconciled_balance,omitempty"`
+
+	// Total before any transaction; it plus every transaction on the origin
+	// must add up to Total. Origins stored before it existed have none.
+	OpeningBalance *float64 `json:"opening_balance,omitempty" bson:"opening_balance,omitempty"`
+
+	// Set while the origin is in the trash, until it is restored or purged.
+	DeletedAt *time.Time `json:"deleted_at,omitempty" bson:"deleted_at,omitempty"`
+
+	// Set once the origin is archived: it is left out of the listings and
+	// reports and takes no new transactions, but keeps the ones it has.
+	ArchivedAt *time.Time `json:"archived_at,omitempty" bson:"archived_at,omitempty"`
 }
 
 type OriginRequest struct {
-	UserId      string    `json:"user_id" bson:"user_id" validate:"required"`
-	Name        string    `json:"name" bson:"name" validate:"required"`
-	Total       float64   `json:"total" bson:"total" validate:"required"`
-	Description string    `json:"description,omitempty" bson:"description,omitempty"`
-	CreatedAt   time.Time `json:"created_at" bson:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at,omitempty" bson:"updated_at"`
+	UserId       string    `json:"user_id" bson:"user_id" validate:"required"`
+	Name         string    `json:"name" bson:"name" validate:"required"`
+	Kind         string    `json:"kind,omitempty" bson:"kind,omitempty"`
+	Total        float64   `json:"total" bson:"total" validate:"required"`
+	CreditLimit  *float64  `json:"credit_limit,omitempty" bson:"credit_limit"`
+	StatementDay int       `json:"statement_day,omitempty" bson:"statement_day"`
+	InterestRate *float64  `json:"interest_rate,omitempty" bson:"interest_rate"`
+	Description  string    `json:"description,omitempty" bson:"description,omitempty"`
+	CreatedAt    time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at,omitempty" bson:"updated_at"`
+
+	PaymentDueDays     int      `json:"payment_due_days,omitempty" bson:"payment_due_days"`
+	MinimumPaymentRate *float64 `json:"minimum_payment_rate,omitempty" bson:"minimum_payment_rate"`
+
+	OpeningBalance *float64 `json:"opening_balance,omitempty" bson:"opening_balance,omitempty"`
+}
+
+// OriginKind returns the origin's kind, defaulting to checking.
+func (o Origin) OriginKind() string {
+
+	if o.Kind == "" {
+		return OriginChecking
+	}
+
+	return o.Kind
+}
+
+func (o Origin) IsLiability() bool {
+
+	kind := o.OriginKind()
+
+	return kind == OriginCreditCard || kind == OriginLoan
+}
+
+// NetWorth is the origin's contribution to the user's net worth: its Total
+// for assets and minus its Total for liabilities.
+func (o Origin) NetWorth() float64 {
+
+	if o.IsLiability() {
+		return -o.Total
+	}
+
+	return o.Total
+}
+
+// ApplyTransaction updates Total with a transaction of transactionType.
+func (o *Origin) ApplyTransaction(transactionType string, amount float64) {
+
+	if o.IsLiability() {
+		amount = -amount
+	}
+
+	if transactionType == "Income" {
+		o.Total += amount
+	} else if transactionType == "Output" {
+		o.Total -= amount
+	}
+}
+
+// IsOriginKind reports whether kind is one of OriginKinds.
+func IsOriginKind(kind string) bool {
+
+	for _, k := range OriginKinds {
+		if k == kind {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (o Origin) IsArchived() bool {
+
+	return o.ArchivedAt != nil
+}
+
+// IsLocked reports whether transactions dated at date fall in the origin's
+// reconciled period.
+func (o Origin) IsLocked(date time.Time) bool {
+
+	return o.ReconciledThrough != nil && !SnapshotDay(date).After(SnapshotDay(*o.ReconciledThrough))
 }
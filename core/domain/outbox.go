@@ -0,0 +1,37 @@
+package domain
+
+import "time"
+
+type MailAttachment struct {
+	Filename    string `json:"filename" bson:"filename"`
+	ContentType string `json:"content_type" bson:"content_type"`
+	Content     []byte `json:"-" bson:"content"`
+}
+
+const (
+	OutboxPending = "pending"
+	OutboxSent    = "sent"
+	OutboxDead    = "dead"
+)
+
+// OutboxMessage is an email queued for delivery. Messages are rendered when
+// queued and sent by a background worker, which retries failures with
+// exponential backoff until MaxAttempts and then dead-letters them.
+// IdempotencyKey is unique: queueing the same key twice keeps the first message.
+type OutboxMessage struct {
+	ID             string           `json:"_id" bson:"_id,omitempty"`
+	IdempotencyKey string           `json:"idempotency_key" bson:"idempotency_key"`
+	ToName         string           `json:"to_name" bson:"to_name"`
+	ToEmail        string           `json:"to_email" bson:"to_email"`
+	Subject        string           `json:"subject" bson:"subject"`
+	HTMLBody       string           `json:"-" bson:"html_body"`
+	TextBody       string           `json:"-" bson:"text_body,omitempty"`
+	Attachments    []MailAttachment `json:"attachments,omitempty" bson:"attachments,omitempty"`
+	Status         string           `json:"status" bson:"status"`
+	Attempts       int              `json:"attempts" bson:"attempts"`
+	LastError      string           `json:"last_error,omitempty" bson:"last_error,omitempty"`
+	NextAttemptAt  time.Time        `json:"next_attempt_at" bson:"next_attempt_at"`
+	SentAt         *time.Time       `json:"sent_at,omitempty" bson:"sent_at,omitempty"`
+	CreatedAt      time.Time        `json:"created_at" bson:"created_at"`
+	UpdatedAt      time.Time        `json:"updated_at" bson:"updated_at"`
+}
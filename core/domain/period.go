@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// ReportPeriod is the [Start, End) interval a report covers.
+type ReportPeriod struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	Label string    `json:"label"`
+}
+
+func MonthPeriod(year int, month time.Month) (ReportPeriod, error) {
+
+	if month < time.January || month > time.December {
+		return ReportPeriod{}, ErrInvalidPeriod
+	}
+
+	start := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+
+	return ReportPeriod{
+		Start: start,
+		End:   start.AddDate(0, 1, 0),
+		Label: fmt.Sprintf("%s %d", month, year),
+	}, nil
+}
+
+func QuarterPeriod(year int, quarter int) (ReportPeriod, error) {
+
+	if quarter < 1 || quarter > 4 {
+		return ReportPeriod{}, ErrInvalidPeriod
+	}
+
+	start := time.Date(year, time.Month(3*(quarter-1)+1), 1, 0, 0, 0, 0, time.UTC)
+
+	return ReportPeriod{
+		Start: start,
+		End:   start.AddDate(0, 3, 0),
+		Label: fmt.Sprintf("Q%d %d", quarter, year),
+	}, nil
+}
+
+func YearPeriod(year int) ReportPeriod {
+
+	start := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	return ReportPeriod{
+		Start: start,
+		End:   start.AddDate(1, 0, 0),
+		Label: fmt.Sprintf("%d", year),
+	}
+}
+
+// CustomPeriod covers the days from and to, both included.
+func CustomPeriod(from time.Time, to time.Time) (ReportPeriod, error) {
+
+	start := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	end := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, 1)
+
+	if !start.Before(end) {
+		return ReportPeriod{}, ErrInvalidPeriod
+	}
+
+	return ReportPeriod{
+		Start: start,
+		End:   end,
+		Label: fmt.Sprintf("%s - %s", start.Format("2006-01-02"), end.AddDate(0, 0, -1).Format("2006-01-02")),
+	}, nil
+}
+
+// PreviousMonthPeriod is the calendar month before the one containing now,
+// rolling back the year in January.
+func PreviousMonthPeriod(now time.Time) ReportPeriod {
+
+	firstOfMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	previous := firstOfMonth.AddDate(0, -1, 0)
+
+	period, _ := MonthPeriod(previous.Year(), previous.Month())
+
+	return period
+}
@@ -0,0 +1,34 @@
+package domain
+
+import "time"
+
+const (
+	ReconciliationOpen      = "open"
+	ReconciliationCompleted = "completed"
+)
+
+// Reconciliation matches an origin against a bank statement: the
+// transactions the bank has cleared through StatementDate must add up to
+// StatementBalance. Completing it books any difference as an adjustment and
+// locks the origin's transactions through StatementDate.
+type Reconciliation struct {
+	ID               string     `json:"_id" bson:"_id,omitempty"`
+	UserId           string     `json:"user_id" bson:"user_id"`
+	OriginId         string     `json:"origin_id" bson:"origin_id"`
+	StatementDate    time.Time  `json:"statement_date" bson:"statement_date"`
+	StatementBalance float64    `json:"statement_balance" bson:"statement_balance"`
+	Status           string     `json:"status" bson:"status"`
+	Adjustment       float64    `json:"adjustment,omitempty" bson:"adjustment,omitempty"`
+	AdjustmentId     string     `json:"adjustment_id,omitempty" bson:"adjustment_id,omitempty"`
+	CreatedAt        time.Time  `json:"created_at" bson:"created_at"`
+	CompletedAt      *time.Time `json:"completed_at,omitempty" bson:"completed_at,omitempty"`
+}
+
+// ReconciliationState is a reconciliation together with the origin's
+// balance counting only cleared transactions and what is left to clear.
+type ReconciliationState struct {
+	Reconciliation Reconciliation `json:"reconciliation"`
+	ClearedBalance float64        `json:"cleared_balance"`
+	Difference     float64        `json:"difference"`
+	Uncleared      []Transaction  `json:"uncleared"`
+}
@@ -3,20 +3,31 @@ package domain
 import "time"
 
 type Report struct {
-	UserId          string            `json:"user_id"`
-	Username        string            `json:"username"`
-	UserEmail       string            `json:"email"`
-	Month           time.Month        `json:"month"`
-	Year            int               `json:"year"`
-	TotalIncome     float64           `json:"total_income"`
-	TotalExpenses   float64           `json:"total_expenses"`
-	NetBalance      float64           `json:"net_balance"`
-	OriginSummary   []OriginSummary   `json:"origin_summary"`
-	CategorySummary []CategorySummary `json:"category_summary"`
+	UserId           string            `json:"user_id"`
+	Username         string            `json:"username"`
+	UserEmail        string            `json:"email"`
+	Locale           string            `json:"locale"`
+	Currency         string            `json:"currency"`
+	Month            time.Month        `json:"month"`
+	Year             int               `json:"year"`
+	Period           ReportPeriod      `json:"period"`
+	TotalIncome      float64           `json:"total_income"`
+	TotalExpenses    float64           `json:"total_expenses"`
+	TotalAssets      float64           `json:"total_assets"`
+	TotalLiabilities float64           `json:"total_liabilities"`
+	NetBalance       float64           `json:"net_balance"`
+	OriginSummary    []OriginSummary   `json:"origin_summary"`
+	CategorySummary  []CategorySummary `json:"category_summary"`
+	IncomeSummary    []IncomeSummary   `json:"income_summary"`
+	TagSummary       []TagSummary      `json:"tag_summary"`
 }
 
+// OriginSummary reports the balance of an asset, or what is owed on a
+// liability, at the time the report is generated.
 type OriginSummary struct {
 	OriginName    string  `json:"origin_name"`
+	Kind          string  `json:"kind"`
+	Liability     bool    `json:"liability"`
 	TotalIncome   float64 `json:"total_income"`
 	TotalExpenses float64 `json:"total_expenses"`
 	OriginBalance float64 `json:"origin_balance"`
@@ -27,3 +38,17 @@ type CategorySummary struct {
 	TotalExpenses  float64 `json:"total_expenses"`
 	Count          int     `json:"count"`
 }
+
+// IncomeSummary totals the income of one source (salary, freelance, ...).
+type IncomeSummary struct {
+	Source      string  `json:"source"`
+	TotalIncome float64 `json:"total_income"`
+	Count       int     `json:"count"`
+}
+
+type TagSummary struct {
+	Tag           string  `json:"tag"`
+	TotalIncome   float64 `json:"total_income"`
+	TotalExpenses float64 `json:"total_expenses"`
+	Count         int     `json:"count"`
+}
@@ -0,0 +1,57 @@
+package domain
+
+import "time"
+
+const (
+	DeliveryStatusSent   = "sent"
+	DeliveryStatusFailed = "failed"
+
+	ReportRunRunning   = "running"
+	ReportRunPartial   = "partial"
+	ReportRunCompleted = "completed"
+
+	// MaxReportDeliveryAttempts is how many runs may try to mail a report
+	// before it is left as failed.
+	MaxReportDeliveryAttempts = 3
+)
+
+// ReportDelivery records the monthly report mailed to one user for one
+// period ("2006-01"), so a restarted scheduler does not send it twice. Once
+// the report is queued, Status follows the outbox message (pending, sent or
+// dead); it is failed only when the report could not be queued.
+type ReportDelivery struct {
+	ID        string     `json:"_id" bson:"_id,omitempty"`
+	UserId    string     `json:"user_id" bson:"user_id"`
+	Period    string     `json:"period" bson:"period"`
+	Status    string     `json:"status" bson:"status"`
+	MessageId string     `json:"message_id,omitempty" bson:"message_id,omitempty"`
+	Attempts  int        `json:"attempts" bson:"attempts"`
+	Error     string     `json:"error,omitempty" bson:"error,omitempty"`
+	SentAt    *time.Time `json:"sent_at,omitempty" bson:"sent_at,omitempty"`
+	UpdatedAt time.Time  `json:"updated_at" bson:"updated_at"`
+}
+
+// ReportRun summarizes one scheduled delivery of the monthly reports. A run
+// is partial while some failed reports may still be retried. LastUserId is the
+// last subscriber of the batches a running run has finished.
+type ReportRun struct {
+	ID         string     `json:"_id" bson:"_id,omitempty"`
+	Period     string     `json:"period" bson:"period"`
+	Status     string     `json:"status" bson:"status"`
+	LastUserId string     `json:"last_user_id,omitempty" bson:"last_user_id,omitempty"`
+	Sent       int        `json:"sent" bson:"sent"`
+	Failed     int        `json:"failed" bson:"failed"`
+	Skipped    int        `json:"skipped" bson:"skipped"`
+	StartedAt  time.Time  `json:"started_at" bson:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty" bson:"finished_at,omitempty"`
+}
+
+type ReportDeliveryStatus struct {
+	Run        *ReportRun       `json:"run"`
+	Deliveries []ReportDelivery `json:"deliveries"`
+}
+
+// PeriodKey identifies a monthly period in delivery records.
+func PeriodKey(period ReportPeriod) string {
+	return period.Start.Format("2006-01")
+}
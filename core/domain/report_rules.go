@@ -0,0 +1,39 @@
+package domain
+
+import "strings"
+
+// AnySubject in a ReportRules list accepts every subject.
+const AnySubject = "*"
+
+// ReportRules decide which transactions a report counts, by subject: income
+// transactions count when their subject is in IncomeSubjects and outputs when
+// it is in ExpenseSubjects. Subjects match case-insensitively.
+type ReportRules struct {
+	IncomeSubjects  []string
+	ExpenseSubjects []string
+}
+
+// DefaultReportRules counts every income and the output subjects reports have
+// always counted. Income subjects are only restricted when configured.
+func DefaultReportRules() ReportRules {
+	return ReportRules{
+		IncomeSubjects:  []string{AnySubject},
+		ExpenseSubjects: []string{"Payment", "Expense"},
+	}
+}
+
+func (rr ReportRules) Counts(transaction Transaction) bool {
+
+	subjects := rr.ExpenseSubjects
+	if transaction.Type == "Income" {
+		subjects = rr.IncomeSubjects
+	}
+
+	for _, subject := range subjects {
+		if subject == AnySubject || strings.EqualFold(subject, transaction.Subject) {
+			return true
+		}
+	}
+
+	return false
+}
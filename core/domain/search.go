@@ -0,0 +1,61 @@
+package domain
+
+import "time"
+
+// TransactionFilter combines the optional criteria of a transaction search.
+// Zero values leave a criterion out; To is an exclusive upper bound. Tags
+// must all be present unless AnyTag is set.
+type TransactionFilter struct {
+	UserId        string
+	From          *time.Time
+	To            *time.Time
+	Type          string
+	OriginIds     []string
+	Category      string
+	Payee         string
+	MinAmount     *float64
+	MaxAmount     *float64
+	Text          string
+	Tags          []string
+	AnyTag        bool
+	SortField     string
+	SortAscending bool
+}
+
+// Sortable fields of a transaction search, keyed by their API name.
+var TransactionSortFields = map[string]string{
+	"date":     "created_at",
+	"amount":   "amount",
+	"payee":    "person_business",
+	"category": "output_category",
+	"type":     "type",
+}
+
+// MonthRange returns the [start, end) bounds of the given month, or of the
+// whole year when month is 0.
+func MonthRange(year int, month int) (time.Time, time.Time) {
+
+	if month == 0 {
+		startDate := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+		return startDate, startDate.AddDate(1, 0, 0)
+	}
+
+	startDate := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+	return startDate, startDate.AddDate(0, 1, 0)
+}
+
+// TextSearchResult is a transaction matched by a full-text search, with its
+// relevance score and the highlighted fragments of the fields that matched.
+type TextSearchResult struct {
+	Transaction Transaction     `json:"transaction"`
+	Score       float64         `json:"score"`
+	Highlights  []TextHighlight `json:"highlights"`
+}
+
+// TextHighlight is a fragment of Field; Ranges holds the [start, end) rune
+// offsets of every matched term inside Snippet.
+type TextHighlight struct {
+	Field   string   `json:"field"`
+	Snippet string   `json:"snippet"`
+	Ranges  [][2]int `json:"ranges"`
+}
@@ -0,0 +1,39 @@
+package domain
+
+import "time"
+
+// BalanceSnapshot is the balance an origin had at the end of Date (midnight
+// UTC). The snapshot of the current day follows the balance as it changes.
+type BalanceSnapshot struct {
+	ID        string    `json:"_id" bson:"_id,omitempty"`
+	UserId    string    `json:"user_id" bson:"user_id"`
+	OriginId  string    `json:"origin_id" bson:"origin_id"`
+	Date      time.Time `json:"date" bson:"date"`
+	Balance   float64   `json:"balance" bson:"balance"`
+	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+// DailyOriginNet is the net effect the transactions of one day had on an
+// origin's balance.
+type DailyOriginNet struct {
+	OriginId string    `bson:"origin_id"`
+	Date     time.Time `bson:"date"`
+	Net      float64   `bson:"net"`
+}
+
+type OriginBalance struct {
+	OriginId string  `json:"origin_id"`
+	Balance  float64 `json:"balance"`
+}
+
+type NetWorthPoint struct {
+	Date     string          `json:"date"`
+	NetWorth float64         `json:"net_worth"`
+	Origins  []OriginBalance `json:"origins"`
+}
+
+// SnapshotDay truncates t to the day its snapshot is recorded under.
+func SnapshotDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
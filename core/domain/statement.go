@@ -0,0 +1,57 @@
+package domain
+
+import "time"
+
+const (
+	StatementOpen        = "open"
+	StatementDue         = "due"
+	StatementMinimumPaid = "minimum_paid"
+	StatementPaid        = "paid"
+	StatementOverdue     = "overdue"
+)
+
+const (
+	DefaultPaymentDueDays     = 20
+	DefaultMinimumPaymentRate = 5.0
+)
+
+// Statement is one billing cycle of a credit card, from the day after the
+// previous closing through ClosingDate. The open cycle, still running, has
+// no due date nor minimum payment.
+type Statement struct {
+	OriginId         string        `json:"origin_id"`
+	PeriodStart      time.Time     `json:"period_start"`
+	ClosingDate      time.Time     `json:"closing_date"`
+	DueDate          *time.Time    `json:"due_date,omitempty"`
+	OpeningBalance   float64       `json:"opening_balance"`
+	Purchases        float64       `json:"purchases"`
+	Payments         float64       `json:"payments"`
+	ClosingBalance   float64       `json:"closing_balance"`
+	MinimumPayment   float64       `json:"minimum_payment"`
+	PaidSinceClosing float64       `json:"paid_since_closing"`
+	Status           string        `json:"status"`
+	Transactions     []Transaction `json:"transactions"`
+}
+
+// ClosingDate returns the day the cycle of month closes on, clamping
+// statementDay to the month's last day.
+func ClosingDate(year int, month time.Month, statementDay int) time.Time {
+
+	lastDay := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	if statementDay > lastDay {
+		statementDay = lastDay
+	}
+
+	return time.Date(year, month, statementDay, 0, 0, 0, 0, time.UTC)
+}
+
+// LastClosingDate returns the latest closing date on or before day.
+func LastClosingDate(day time.Time, statementDay int) time.Time {
+
+	closing := ClosingDate(day.Year(), day.Month(), statementDay)
+	if closing.After(day) {
+		closing = ClosingDate(day.Year(), day.Month()-1, statementDay)
+	}
+
+	return closing
+}
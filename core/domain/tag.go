@@ -0,0 +1,13 @@
+package domain
+
+import "time"
+
+type Tag struct {
+	ID          string    `json:"_id" bson:"_id,omitempty"`
+	UserId      string    `json:"user_id" bson:"user_id" validate:"required"`
+	Name        string    `json:"name" bson:"name" validate:"required"`
+	Color       string    `json:"color,omitempty" bson:"color,omitempty"`
+	Description string    `json:"description,omitempty" bson:"description,omitempty"`
+	CreatedAt   time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at,omitempty" bson:"updated_at"`
+}
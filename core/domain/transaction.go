@@ -5,17 +5,23 @@ import (
 )
 
 type Transaction struct {
-	ID               string    `json:"_id" bson:"_id,omitempty"`
-	UserId           string    `json:"user_id" bson:"user_id" validate:"required"`
-	OriginId         *string   `json:"origin_id,omitempty" bson:"origin_id,omitempty"`
-	Amount           float64   `json:"amount" validate:"required"`
-	Type             string    `json:"type" validate:"required"`
-	OutputCategory   string    `json:"output_category" bson:"output_category"`
-	Subject          string    `json:"subject" validate:"required"`
-	PersonOrBusiness string    `json:"person_business" bson:"person_business" validate:"required"`
-	Description      string    `json:"description" validate:"required"`
-	CreatedAtString  string    `json:"created" bson:"created" validate:"required"`
-	CreatedAt        time.Time `json:"created_at" bson:"created_at"`
-	UpdatedAt        time.Time `json:"updated_at,omitempty" bson:"updated_at"`
-	Origin           *Origin   `json:"origin,imitempty" bson:"origin,omitempty"`
+	ID               string       `json:"_id" bson:"_id,omitempty"`
+	UserId           string       `json:"user_id" bson:"user_id" validate:"required"`
+	OriginId         *string      `json:"origin_id,omitempty" bson:"origin_id,omitempty"`
+	Amount           float64      `json:"amount" validate:"required"`
+	Type             string       `json:"type" validate:"required"`
+	OutputCategory   string       `json:"output_category" bson:"output_category"`
+	IncomeSource     string       `json:"income_source,omitempty" bson:"income_source,omitempty"`
+	Subject          string       `json:"subject" validate:"required"`
+	PersonOrBusiness string       `json:"person_business" bson:"person_business" validate:"required"`
+	Description      string       `json:"description" validate:"required"`
+	Tags             []string     `json:"tags,omitempty" bson:"tags,omitempty"`
+	DebtPayment      *DebtPayment `json:"debt_payment,omitempty" bson:"debt_payment,omitempty"`
+	Cleared          bool         `json:"cleared,omitempty" bson:"cleared,omitempty"`
+	Reconciled       bool         `json:"reconciled,omitempty" bson:"reconciled,omitempty"`
+	CreatedAtString  string       `json:"created" bson:"created" validate:"required"`
+	CreatedAt        time.Time    `json:"created_at" bson:"created_at"`
+	UpdatedAt        time.Time    `json:"updated_at,omitempty" bson:"updated_at"`
+	DeletedAt        *time.Time   `json:"deleted_at,omitempty" bson:"deleted_at,omitempty"`
+	Origin           *Origin      `json:"origin,imitempty" bson:"origin,omitempty"`
 }
@@ -0,0 +1,16 @@
+package port
+
+import (
+	"context"
+	"personal-finance/core/domain"
+	"time"
+)
+
+type AnalyticsRepository interface {
+	GetMonthlyTotals(ctx context.Context, userId string, from, to time.Time, rules domain.ReportRules) ([]domain.MonthlyTotals, error)
+	GetMonthlyCategoryTotals(ctx context.Context, userId string, from, to time.Time, rules domain.ReportRules) ([]domain.CategoryMonthTotal, error)
+}
+
+type AnalyticsService interface {
+	GetTrends(ctx context.Context, userId string, from, to time.Time) (*domain.Trends, error)
+}
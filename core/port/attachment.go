@@ -0,0 +1,31 @@
+package port
+
+import (
+	"context"
+	"mime/multipart"
+	"personal-finance/core/domain"
+)
+
+// FileAdapter stores arbitrary user documents (receipts, invoices) in the
+// file storage backend, grouped by folder. FileUrl may return a short-lived
+// link, so it is resolved each time the file is read rather than stored.
+type FileAdapter interface {
+	UploadFile(ctx context.Context, file multipart.File, folder string) (*domain.StoredFile, error)
+	DeleteFile(ctx context.Context, publicId string) error
+	FileUrl(ctx context.Context, publicId string) (string, error)
+}
+
+type AttachmentRepository interface {
+	GetAttachmentsByTransactionId(ctx context.Context, transactionId string) ([]domain.Attachment, error)
+	GetAttachmentById(ctx context.Context, id string) (*domain.Attachment, error)
+	CreateAttachment(ctx context.Context, attachment *domain.Attachment) (*domain.Attachment, error)
+	DeleteAttachment(ctx context.Context, id string) error
+	DeleteAttachmentsByTransactionId(ctx context.Context, transactionId string) error
+}
+
+type AttachmentService interface {
+	GetAttachmentsByTransactionId(ctx context.Context, transactionId string) ([]domain.Attachment, error)
+	UploadAttachment(ctx context.Context, transactionId string, file multipart.File, fileName string, contentType string) (*domain.Attachment, error)
+	DeleteAttachment(ctx context.Context, transactionId string, id string) error
+	DeleteAttachmentsByTransactionId(ctx context.Context, transactionId string) error
+}
@@ -0,0 +1,16 @@
+package port
+
+import (
+	"context"
+	"personal-finance/core/domain"
+)
+
+type AuditRepository interface {
+	CreateRecord(ctx context.Context, record *domain.AuditRecord) error
+	GetHistory(ctx context.Context, userId, entity, entityId string, page, limit uint64) ([]domain.AuditRecord, int64, int, error)
+}
+
+type AuditService interface {
+	Record(ctx context.Context, userId, entity, entityId, action string, before, after any) error
+	GetHistory(ctx context.Context, userId, entity, entityId string, page, limit uint64) ([]domain.AuditRecord, int64, int, error)
+}
@@ -9,8 +9,10 @@ import (
 type AuthRepository interface {
 	GetUserById(ctx context.Context, id string) (*domain.User, error)
 	GetUserByEmail(ctx context.Context, email string) (*domain.User, error)
+	GetReportSubscribers(ctx context.Context, afterId string, limit int64) ([]domain.User, error)
 	CreateUser(ctx context.Context, createUser *domain.User) (*domain.User, error)
 	UpdateUser(ctx context.Context, id string, updateUser *domain.User) (*domain.User, error)
+	UpdatePreferences(ctx context.Context, id string, preferences domain.UserPreferences) error
 	DeleteUser(ctx context.Context, id string) error
 }
 
@@ -20,6 +22,7 @@ type AuthService interface {
 	VerifyUserEmail(ctx context.Context, email string) (bool, error)
 	CreateUser(ctx context.Context, createUser *domain.User) (*domain.User, error)
 	UpdateUser(ctx context.Context, id string, updateUser *domain.User) (*domain.User, error)
+	UpdatePreferences(ctx context.Context, id string, preferences domain.UserPreferences) error
 	UpdateUserProfileImage(ctx context.Context, file multipart.File, userId string) (*domain.Image, error)
 	DeleteUserProfileImage(ctx context.Context, publicId string) error
 	DeleteUser(ctx context.Context, id string) error
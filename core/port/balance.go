@@ -0,0 +1,15 @@
+package port
+
+import (
+	"context"
+	"personal-finance/core/domain"
+)
+
+type BalanceRepository interface {
+	GetOriginActivity(ctx context.Context, userId string) ([]domain.OriginActivity, error)
+}
+
+type BalanceService interface {
+	AuditBalances(ctx context.Context, userId string) (*domain.BalanceAudit, error)
+	RepairBalances(ctx context.Context, userId string) (*domain.BalanceAudit, error)
+}
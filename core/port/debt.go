@@ -0,0 +1,28 @@
+package port
+
+import (
+	"context"
+	"personal-finance/core/domain"
+	"time"
+)
+
+type DebtRepository interface {
+	GetDebtsByUserId(ctx context.Context, userId string) ([]domain.Debt, error)
+	GetDebtById(ctx context.Context, id string) (*domain.Debt, error)
+	CreateDebt(ctx context.Context, debt *domain.Debt) (*domain.Debt, error)
+	UpdateDebt(ctx context.Context, id string, debt *domain.Debt) (*domain.Debt, error)
+	DeleteDebt(ctx context.Context, id string) error
+	GetDebtPayments(ctx context.Context, debt *domain.Debt) ([]domain.Transaction, error)
+}
+
+type DebtService interface {
+	GetDebtsByUserId(ctx context.Context, userId string) ([]domain.Debt, error)
+	GetDebtById(ctx context.Context, id string) (*domain.Debt, error)
+	CreateDebt(ctx context.Context, debt *domain.Debt) (*domain.Debt, error)
+	UpdateDebt(ctx context.Context, id string, debt *domain.Debt) (*domain.Debt, error)
+	DeleteDebt(ctx context.Context, id string) error
+	GetDebtStatus(ctx context.Context, id string) (*domain.DebtStatus, error)
+	GetSchedule(ctx context.Context, id string) ([]domain.AmortizationRow, error)
+	RecordPayment(ctx context.Context, id string, amount float64, date time.Time, fromOriginId string) (*domain.Transaction, error)
+	SimulateExtraPayments(ctx context.Context, id string, extraMonthly, lumpSum float64) (*domain.DebtSimulation, error)
+}
@@ -0,0 +1,24 @@
+package port
+
+import (
+	"context"
+	"personal-finance/core/domain"
+	"time"
+)
+
+type ExpectedItemRepository interface {
+	GetExpectedItemsByUserId(ctx context.Context, userId string) ([]domain.ExpectedItem, error)
+	GetExpectedItemById(ctx context.Context, id string) (*domain.ExpectedItem, error)
+	CreateExpectedItem(ctx context.Context, item *domain.ExpectedItem) (*domain.ExpectedItem, error)
+	UpdateExpectedItem(ctx context.Context, id string, item *domain.ExpectedItem) (*domain.ExpectedItem, error)
+	DeleteExpectedItem(ctx context.Context, id string) error
+}
+
+type ForecastService interface {
+	GetExpectedItemsByUserId(ctx context.Context, userId string) ([]domain.ExpectedItem, error)
+	GetExpectedItemById(ctx context.Context, id string) (*domain.ExpectedItem, error)
+	CreateExpectedItem(ctx context.Context, item *domain.ExpectedItem) (*domain.ExpectedItem, error)
+	UpdateExpectedItem(ctx context.Context, id string, item *domain.ExpectedItem) (*domain.ExpectedItem, error)
+	DeleteExpectedItem(ctx context.Context, id string) error
+	GetForecast(ctx context.Context, userId string, months int, threshold float64, now time.Time) (*domain.Forecast, error)
+}
@@ -2,6 +2,7 @@ package port
 
 import (
 	"context"
+	"io"
 	"mime/multipart"
 	"personal-finance/core/domain"
 )
@@ -10,3 +11,10 @@ type ImageAdapter interface {
 	UploadImageFromFile(ctx context.Context, file multipart.File, userId string) (*domain.Image, error)
 	DeleteImage(ctx context.Context, publicId string) error
 }
+
+// SignedFileReader is implemented by storage backends without a public
+// endpoint of their own; their files are served by the API behind a signature
+// that is only valid until expires (unix seconds).
+type SignedFileReader interface {
+	OpenSignedFile(ctx context.Context, publicId string, expires string, signature string) (io.ReadSeekCloser, error)
+}
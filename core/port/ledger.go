@@ -0,0 +1,27 @@
+package port
+
+import (
+	"context"
+	"personal-finance/core/domain"
+)
+
+// LedgerRepository stores the journal. It only appends: entries are never
+// updated nor deleted.
+type LedgerRepository interface {
+	AppendEntries(ctx context.Context, entries []domain.JournalEntry) error
+	GetEntries(ctx context.Context, userId string, account string, page, limit uint64) ([]domain.JournalEntry, int64, int, error)
+	GetLastTransactionEntry(ctx context.Context, transactionId string) (*domain.JournalEntry, error)
+	GetAccountTotals(ctx context.Context, userId string) ([]domain.AccountTotals, error)
+	GetJournaledTransactionIds(ctx context.Context, userId string) ([]string, error)
+	GetJournaledOriginIds(ctx context.Context, userId string) ([]string, error)
+}
+
+type LedgerService interface {
+	PostTransaction(ctx context.Context, transaction *domain.Transaction) error
+	ReverseTransaction(ctx context.Context, transaction *domain.Transaction) error
+	PostOpening(ctx context.Context, origin *domain.Origin, amount float64, kind string) error
+	GetEntries(ctx context.Context, userId string, account string, page, limit uint64) ([]domain.JournalEntry, int64, int, error)
+	GetTrialBalance(ctx context.Context, userId string) (*domain.TrialBalance, error)
+	GetOriginBalances(ctx context.Context, userId string, origins []domain.Origin) (map[string]float64, error)
+	MigrateUser(ctx context.Context, userId string) (int, error)
+}
@@ -3,20 +3,32 @@ package port
 import (
 	"context"
 	"personal-finance/core/domain"
+	"time"
 )
 
 type OriginRepository interface {
-	GetOriginsByUserId(ctx context.Context, userId string) ([]domain.Origin, error)
+	GetOriginsByUserId(ctx context.Context, userId string, includeArchived bool) ([]domain.Origin, error)
 	GetOriginById(ctx context.Context, id string) (*domain.Origin, error)
 	CreateOrigin(ctx context.Context, origin *domain.Origin) (*domain.Origin, error)
 	UpdateOrigin(ctx context.Context, id string, updatedOrigin *domain.Origin) (*domain.Origin, error)
+	ArchiveOrigin(ctx context.Context, id string) error
+	UnarchiveOrigin(ctx context.Context, id string) error
 	DeleteOrigin(ctx context.Context, id string) error
+	GetDeletedOrigins(ctx context.Context, userId string) ([]domain.Origin, error)
+	GetDeletedOriginById(ctx context.Context, id string) (*domain.Origin, error)
+	RestoreOrigin(ctx context.Context, id string) error
+	PurgeOrigins(ctx context.Context, before time.Time) (int64, error)
 }
 
 type OriginService interface {
-	GetOriginsByUserId(ctx context.Context, userId string) ([]domain.Origin, error)
+	GetOriginsByUserId(ctx context.Context, userId string, includeArchived bool) ([]domain.Origin, error)
 	GetOriginById(ctx context.Context, id string) (*domain.Origin, error)
 	CreateOrigin(ctx context.Context, origin *domain.Origin) (*domain.Origin, error)
 	UpdateOrigin(ctx context.Context, id string, origin *domain.Origin) (*domain.Origin, error)
-	DeleteOrigin(ctx context.Context, id string) error
+	ArchiveOrigin(ctx context.Context, id string) (*domain.Origin, error)
+	UnarchiveOrigin(ctx context.Context, id string) (*domain.Origin, error)
+	DeleteOrigin(ctx context.Context, id string, deletion domain.OriginDeletion) error
+	GetDeletedOrigins(ctx context.Context, userId string) ([]domain.Origin, error)
+	RestoreOrigin(ctx context.Context, id string) (*domain.Origin, error)
+	PurgeDeletedOrigins(ctx context.Context, before time.Time) (int64, error)
 }
@@ -0,0 +1,29 @@
+package port
+
+import (
+	"context"
+	"personal-finance/core/domain"
+	"time"
+)
+
+type OutboxRepository interface {
+	GetMessages(ctx context.Context, status string, page, limit uint64) ([]domain.OutboxMessage, int64, int, error)
+	GetMessageById(ctx context.Context, id string) (*domain.OutboxMessage, error)
+	EnqueueMessage(ctx context.Context, message *domain.OutboxMessage) (*domain.OutboxMessage, error)
+	ClaimDueMessage(ctx context.Context, now time.Time, leaseUntil time.Time) (*domain.OutboxMessage, error)
+	UpdateMessageStatus(ctx context.Context, message *domain.OutboxMessage) error
+	RequeueMessage(ctx context.Context, id string, now time.Time) (*domain.OutboxMessage, error)
+}
+
+type OutboxService interface {
+	GetMessages(ctx context.Context, status string, page, limit uint64) ([]domain.OutboxMessage, int64, int, error)
+	GetMessageById(ctx context.Context, id string) (*domain.OutboxMessage, error)
+	EnqueueMessage(ctx context.Context, message *domain.OutboxMessage) (*domain.OutboxMessage, error)
+	ProcessDueMessages(ctx context.Context, now time.Time) (int, error)
+	ResendMessage(ctx context.Context, id string) (*domain.OutboxMessage, error)
+}
+
+// MailSender delivers one outbox message over the wire.
+type MailSender interface {
+	SendMessage(ctx context.Context, message *domain.OutboxMessage) error
+}
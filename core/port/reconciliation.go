@@ -0,0 +1,27 @@
+package port
+
+import (
+	"context"
+	"personal-finance/core/domain"
+	"time"
+)
+
+type ReconciliationRepository interface {
+	GetReconciliationById(ctx context.Context, id string) (*domain.Reconciliation, error)
+	GetOpenReconciliation(ctx context.Context, originId string) (*domain.Reconciliation, error)
+	CreateReconciliation(ctx context.Context, reconciliation *domain.Reconciliation) (*domain.Reconciliation, error)
+	UpdateReconciliation(ctx context.Context, reconciliation *domain.Reconciliation) error
+	DeleteReconciliation(ctx context.Context, id string) error
+	GetUnreconciledTransactions(ctx context.Context, originId string) ([]domain.Transaction, error)
+	SetCleared(ctx context.Context, originId string, ids []string, cleared bool) error
+	MarkReconciled(ctx context.Context, originId string, through time.Time) error
+	SetOriginReconciled(ctx context.Context, originId string, through time.Time, balance float64) error
+}
+
+type ReconciliationService interface {
+	StartReconciliation(ctx context.Context, originId string, statementDate time.Time, statementBalance float64) (*domain.ReconciliationState, error)
+	GetReconciliation(ctx context.Context, id string) (*domain.ReconciliationState, error)
+	SetCleared(ctx context.Context, id string, transactionIds []string, cleared bool) (*domain.ReconciliationState, error)
+	CompleteReconciliation(ctx context.Context, id string) (*domain.Reconciliation, error)
+	CancelReconciliation(ctx context.Context, id string) error
+}
@@ -6,9 +6,18 @@ import (
 )
 
 type ReportService interface {
+	GetReport(ctx context.Context, userId string, period domain.ReportPeriod, includeArchived bool) (*domain.Report, error)
 	GenerateMonthlyReport(ctx context.Context, userId string) error
+	SendReport(ctx context.Context, userId string, period domain.ReportPeriod) (*domain.OutboxMessage, error)
+	GetReportPDF(ctx context.Context, userId string, period domain.ReportPeriod, includeArchived bool) ([]byte, error)
 }
 
+type ReportRenderer interface {
+	RenderPDF(report domain.Report) ([]byte, error)
+}
+
+// MailReportAdapter queues the report email in the outbox under
+// idempotencyKey and returns the queued message.
 type MailReportAdapter interface {
-	SendMail(report domain.Report) error
+	SendMail(ctx context.Context, report domain.Report, idempotencyKey string) (*domain.OutboxMessage, error)
 }
@@ -0,0 +1,20 @@
+package port
+
+import (
+	"context"
+	"personal-finance/core/domain"
+	"time"
+)
+
+type ReportDeliveryRepository interface {
+	GetDelivery(ctx context.Context, userId string, period string) (*domain.ReportDelivery, error)
+	GetDeliveriesByPeriod(ctx context.Context, period string) ([]domain.ReportDelivery, error)
+	SaveDelivery(ctx context.Context, delivery *domain.ReportDelivery) error
+	GetRun(ctx context.Context, period string) (*domain.ReportRun, error)
+	SaveRun(ctx context.Context, run *domain.ReportRun) error
+}
+
+type ReportDeliveryService interface {
+	DeliverMonthlyReports(ctx context.Context, now time.Time) (*domain.ReportRun, error)
+	GetDeliveryStatus(ctx context.Context, period string) (*domain.ReportDeliveryStatus, error)
+}
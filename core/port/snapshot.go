@@ -0,0 +1,23 @@
+package port
+
+import (
+	"context"
+	"personal-finance/core/domain"
+	"time"
+)
+
+type SnapshotRepository interface {
+	SaveSnapshot(ctx context.Context, snapshot *domain.BalanceSnapshot) error
+	InsertMissingSnapshots(ctx context.Context, snapshots []domain.BalanceSnapshot) (int, error)
+	SnapshotAllOrigins(ctx context.Context, day time.Time) error
+	GetSnapshots(ctx context.Context, userId string, from, to time.Time) ([]domain.BalanceSnapshot, error)
+	GetLatestSnapshotsBefore(ctx context.Context, userId string, day time.Time) ([]domain.BalanceSnapshot, error)
+	GetDailyOriginNet(ctx context.Context, userId string) ([]domain.DailyOriginNet, error)
+}
+
+type SnapshotService interface {
+	RecordBalance(ctx context.Context, origin *domain.Origin) error
+	SnapshotAllOrigins(ctx context.Context, now time.Time) error
+	BackfillSnapshots(ctx context.Context, userId string) (int, error)
+	GetNetWorthHistory(ctx context.Context, userId string, from, to time.Time) ([]domain.NetWorthPoint, error)
+}
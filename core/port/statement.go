@@ -0,0 +1,11 @@
+package port
+
+import (
+	"context"
+	"personal-finance/core/domain"
+	"time"
+)
+
+type StatementService interface {
+	GetStatements(ctx context.Context, originId string, cycles int, now time.Time) ([]domain.Statement, error)
+}
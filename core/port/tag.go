@@ -0,0 +1,23 @@
+package port
+
+import (
+	"context"
+	"personal-finance/core/domain"
+)
+
+type TagRepository interface {
+	GetTagsByUserId(ctx context.Context, userId string) ([]domain.Tag, error)
+	GetTagById(ctx context.Context, id string) (*domain.Tag, error)
+	GetTagByName(ctx context.Context, userId string, name string) (*domain.Tag, error)
+	CreateTag(ctx context.Context, tag *domain.Tag) (*domain.Tag, error)
+	UpdateTag(ctx context.Context, id string, updatedTag *domain.Tag) (*domain.Tag, error)
+	DeleteTag(ctx context.Context, id string) error
+}
+
+type TagService interface {
+	GetTagsByUserId(ctx context.Context, userId string) ([]domain.Tag, error)
+	GetTagById(ctx context.Context, id string) (*domain.Tag, error)
+	CreateTag(ctx context.Context, tag *domain.Tag) (*domain.Tag, error)
+	UpdateTag(ctx context.Context, id string, tag *domain.Tag) (*domain.Tag, error)
+	DeleteTag(ctx context.Context, id string) error
+}
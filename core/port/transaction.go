@@ -3,16 +3,31 @@ package port
 import (
 	"context"
 	"personal-finance/core/domain"
+	"time"
 )
 
 type TransactionRepository interface {
 	GetTransactionsByUserId(ctx context.Context, page, limit uint64, userId string) ([]domain.Transaction, int64, int, error)
 	GetTransactionsByDate(ctx context.Context, userId string, page, limit uint64, year int, month int) ([]domain.Transaction, int64, int, error)
 	GetTransactionsByType(ctx context.Context, userId string, page, limit uint64, transaction_type string) ([]domain.Transaction, int64, int, error)
+	GetTransactionsByTags(ctx context.Context, userId string, page, limit uint64, tags []string, matchAll bool) ([]domain.Transaction, int64, int, error)
+	SearchTransactions(ctx context.Context, filter domain.TransactionFilter, page, limit uint64) ([]domain.Transaction, int64, int, error)
+	GetTransactionsByCursor(ctx context.Context, filter domain.TransactionFilter, cursor string, limit uint64) ([]domain.Transaction, int64, string, error)
+	TextSearchTransactions(ctx context.Context, userId string, query string, page, limit uint64) ([]domain.TextSearchResult, int64, int, error)
 	GetTransactionById(ctx context.Context, id string) (*domain.Transaction, error)
 	CreateTransaction(ctx context.Context, createTransaction *domain.Transaction) (*domain.Transaction, error)
 	UpdateTransaction(ctx context.Context, id string, updatedTransaction *domain.Transaction) (*domain.Transaction, error)
+	RenameTag(ctx context.Context, userId string, oldName string, newName string) error
+	RemoveTag(ctx context.Context, userId string, name string) error
+	CountTransactionsByOriginId(ctx context.Context, originId string) (int64, error)
+	GetTransactionsByOriginId(ctx context.Context, originId string) ([]domain.Transaction, error)
+	ReassignTransactions(ctx context.Context, fromOriginId string, toOriginId string) error
 	DeleteTransaction(ctx context.Context, id string) error
+	GetDeletedTransactions(ctx context.Context, userId string, page, limit uint64) ([]domain.Transaction, int64, int, error)
+	GetDeletedTransactionById(ctx context.Context, id string) (*domain.Transaction, error)
+	RestoreTransaction(ctx context.Context, id string) error
+	GetPurgeableTransactionIds(ctx context.Context, before time.Time) ([]string, error)
+	PurgeTransaction(ctx context.Context, id string, before time.Time) error
 	DeleteTransactionsByUserId(ctx context.Context, id string) error
 }
 
@@ -21,9 +36,17 @@ type TransactionService interface {
 	GetTransactionsByUserId(ctx context.Context, page, limit uint64, userId string) ([]domain.Transaction, int64, int, error)
 	GetTransactionsByDate(ctx context.Context, userId string, page, limit uint64, year int, month int) ([]domain.Transaction, int64, int, error)
 	GetTransactionsByType(ctx context.Context, userId string, page, limit uint64, transaction_type string) ([]domain.Transaction, int64, int, error)
+	GetTransactionsByTags(ctx context.Context, userId string, page, limit uint64, tags []string, matchAll bool) ([]domain.Transaction, int64, int, error)
+	SearchTransactions(ctx context.Context, filter domain.TransactionFilter, page, limit uint64) ([]domain.Transaction, int64, int, error)
+	GetTransactionsByCursor(ctx context.Context, filter domain.TransactionFilter, cursor string, limit uint64) ([]domain.Transaction, int64, string, error)
+	TextSearchTransactions(ctx context.Context, userId string, query string, page, limit uint64) ([]domain.TextSearchResult, int64, int, error)
 	GetTransactionById(ctx context.Context, id string) (*domain.Transaction, error)
 	CreateTransaction(ctx context.Context, createTransaction *domain.Transaction) (*domain.Transaction, error)
 	UpdateTransaction(ctx context.Context, id string, updatedTransaction *domain.Transaction) (*domain.Transaction, error)
+	BookTransaction(ctx context.Context, transaction *domain.Transaction) error
 	UpdateTotalOrigin(ctx context.Context, originId string, transactionType string, amount float64) error
 	DeleteTransaction(ctx context.Context, id string) error
+	GetDeletedTransactions(ctx context.Context, userId string, page, limit uint64) ([]domain.Transaction, int64, int, error)
+	RestoreTransaction(ctx context.Context, id string) (*domain.Transaction, error)
+	PurgeDeletedTransactions(ctx context.Context, before time.Time) (int, error)
 }
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"personal-finance/core/domain"
+	"personal-finance/core/port"
+	"sort"
+	"time"
+)
+
+// maxTrendMonths bounds the range of a trends request.
+const maxTrendMonths = 60
+
+type AnalyticsService struct {
+	analyticsRepo port.AnalyticsRepository
+	rules         domain.ReportRules
+}
+
+func NewAnalyticsService(analyticsRepo port.AnalyticsRepository, rules domain.ReportRules) *AnalyticsService {
+
+	return &AnalyticsService{
+		analyticsRepo,
+		rules,
+	}
+}
+
+// GetTrends returns the monthly series of the months from through to (both
+// included), the category trends over them, and the comparison of the whole
+// range with the range just before it and with the same range a year earlier.
+func (as *AnalyticsService) GetTrends(ctx context.Context, userId string, from, to time.Time) (*domain.Trends, error) {
+
+	start := time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(to.Year(), to.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, 1, 0)
+
+	months := monthsBetween(start, end)
+	if months < 1 || months > maxTrendMonths {
+		return nil, domain.ErrInvalidPeriod
+	}
+
+	previousStart := start.AddDate(0, -months, 0)
+	lastYearStart := start.AddDate(-1, 0, 0)
+
+	queryStart := previousStart
+	if lastYearStart.Before(queryStart) {
+		queryStart = lastYearStart
+	}
+
+	totals, err := as.analyticsRepo.GetMonthlyTotals(ctx, userId, queryStart, end, as.rules)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	categoryTotals, err := as.analyticsRepo.GetMonthlyCategoryTotals(ctx, userId, start, end, as.rules)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	byMonth := make(map[string]domain.MonthlyTotals)
+	for _, monthTotals := range totals {
+		byMonth[monthTotals.Month] = monthTotals
+	}
+
+	current := sumPeriod(byMonth, start, end)
+	previous := sumPeriod(byMonth, previousStart, start)
+	lastYear := sumPeriod(byMonth, lastYearStart, lastYearStart.AddDate(0, months, 0))
+
+	trends := domain.Trends{
+		Series:     monthlySeries(byMonth, start, end),
+		Categories: categoryTrends(categoryTotals, start, end),
+		Comparison: domain.PeriodComparison{
+			Current:        current,
+			Previous:       previous,
+			LastYear:       lastYear,
+			VersusPrevious: periodDelta(current, previous),
+			VersusLastYear: periodDelta(current, lastYear),
+		},
+	}
+
+	return &trends, nil
+}
+
+func monthsBetween(start, end time.Time) int {
+	return (end.Year()-start.Year())*12 + int(end.Month()) - int(start.Month())
+}
+
+func savingsRate(income, savings float64) float64 {
+	if income == 0 {
+		return 0
+	}
+	return savings / income
+}
+
+// monthlySeries lists every month in [start, end), with zero totals for the
+// months without transactions.
+func monthlySeries(byMonth map[string]domain.MonthlyTotals, start, end time.Time) []domain.MonthlyTotals {
+
+	var series []domain.MonthlyTotals
+
+	for month := start; month.Before(end); month = month.AddDate(0, 1, 0) {
+		monthTotals := byMonth[domain.MonthKey(month)]
+		monthTotals.Month = domain.MonthKey(month)
+		monthTotals.Savings = monthTotals.Income - monthTotals.Expenses
+		monthTotals.SavingsRate = savingsRate(monthTotals.Income, monthTotals.Savings)
+
+		series = append(series, monthTotals)
+	}
+
+	return series
+}
+
+func sumPeriod(byMonth map[string]domain.MonthlyTotals, start, end time.Time) domain.PeriodTotals {
+
+	totals := domain.PeriodTotals{
+		From: domain.MonthKey(start),
+		To:   domain.MonthKey(end.AddDate(0, -1, 0)),
+	}
+
+	for month := start; month.Before(end); month = month.AddDate(0, 1, 0) {
+		monthTotals := byMonth[domain.MonthKey(month)]
+		totals.Income += monthTotals.Income
+		totals.Expenses += monthTotals.Expenses
+	}
+
+	totals.Savings = totals.Income - totals.Expenses
+	totals.SavingsRate = savingsRate(totals.Income, totals.Savings)
+
+	return totals
+}
+
+func periodDelta(current, base domain.PeriodTotals) domain.PeriodDelta {
+
+	return domain.PeriodDelta{
+		Income:         current.Income - base.Income,
+		Expenses:       current.Expenses - base.Expenses,
+		Savings:        current.Savings - base.Savings,
+		SavingsRate:    current.SavingsRate - base.SavingsRate,
+		IncomePercent:  percentChange(current.Income, base.Income),
+		ExpensePercent: percentChange(current.Expenses, base.Expenses),
+	}
+}
+
+func percentChange(current, base float64) *float64 {
+
+	if base == 0 {
+		return nil
+	}
+
+	change := (current - base) / base * 100
+
+	return &change
+}
+
+// categoryTrends groups the totals by category with a zero-filled month series
+// each, the categories with the most expenses first.
+func categoryTrends(totals []domain.CategoryMonthTotal, start, end time.Time) []domain.CategoryTrend {
+
+	byCategory := make(map[string]map[string]float64)
+	for _, total := range totals {
+		if byCategory[total.Category] == nil {
+			byCategory[total.Category] = make(map[string]float64)
+		}
+		byCategory[total.Category][total.Month] += total.Expenses
+	}
+
+	var trends []domain.CategoryTrend
+
+	for category, expensesByMonth := range byCategory {
+		trend := domain.CategoryTrend{Category: category}
+
+		for month := start; month.Before(end); month = month.AddDate(0, 1, 0) {
+			expenses := expensesByMonth[domain.MonthKey(month)]
+			trend.Total += expenses
+			trend.Months = append(trend.Months, domain.CategoryMonthTotal{
+				Month:    domain.MonthKey(month),
+				Category: category,
+				Expenses: expenses,
+			})
+		}
+
+		trends = append(trends, trend)
+	}
+
+	sort.Slice(trends, func(i, j int) bool {
+		if trends[i].Total != trends[j].Total {
+			return trends[i].Total > trends[j].Total
+		}
+		return trends[i].Category < trends[j].Category
+	})
+
+	return trends
+}
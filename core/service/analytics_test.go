@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"personal-finance/core/domain"
+)
+
+type mockAnalyticsRepo struct {
+	totals     []domain.MonthlyTotals
+	categories []domain.CategoryMonthTotal
+	from, to   time.Time
+}
+
+func (m *mockAnalyticsRepo) GetMonthlyTotals(ctx context.Context, userId string, from, to time.Time, rules domain.ReportRules) ([]domain.MonthlyTotals, error) {
+	m.from, m.to = from, to
+	return m.totals, nil
+}
+
+func (m *mockAnalyticsRepo) GetMonthlyCategoryTotals(ctx context.Context, userId string, from, to time.Time, rules domain.ReportRules) ([]domain.CategoryMonthTotal, error) {
+	return m.categories, nil
+}
+
+func TestGetTrends_SeriesAndComparisons(t *testing.T) {
+	repo := &mockAnalyticsRepo{
+		totals: []domain.MonthlyTotals{
+			{Month: "2025-02", Income: 800, Expenses: 400},
+			{Month: "2025-12", Income: 900, Expenses: 900},
+			{Month: "2026-01", Income: 1000, Expenses: 500},
+			{Month: "2026-02", Income: 1000, Expenses: 750},
+		},
+		categories: []domain.CategoryMonthTotal{
+			{Month: "2026-01", Category: "Food", Expenses: 200},
+			{Month: "2026-02", Category: "Rent", Expenses: 500},
+			{Month: "2026-02", Category: "Food", Expenses: 250},
+		},
+	}
+
+	from := time.Date(2026, time.January, 15, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC)
+
+	trends, err := NewAnalyticsService(repo, domain.DefaultReportRules()).GetTrends(context.Background(), "u1", from, to)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// one aggregation covers the range, the previous two months and last year
+	if repo.from != time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC) || repo.to != time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC) {
+		t.Errorf("unexpected aggregation range %v - %v", repo.from, repo.to)
+	}
+
+	if len(trends.Series) != 2 || trends.Series[1].Savings != 250 || trends.Series[1].SavingsRate != 0.25 {
+		t.Errorf("unexpected series %+v", trends.Series)
+	}
+
+	comparison := trends.Comparison
+	if comparison.Current.Income != 2000 || comparison.Previous.Income != 900 || comparison.LastYear.Income != 800 {
+		t.Errorf("unexpected period totals %+v", comparison)
+	}
+	if comparison.VersusPrevious.Expenses != 350 || *comparison.VersusLastYear.IncomePercent != 150 {
+		t.Errorf("unexpected deltas %+v / %+v", comparison.VersusPrevious, comparison.VersusLastYear)
+	}
+
+	if len(trends.Categories) != 2 || trends.Categories[0].Category != "Rent" || trends.Categories[1].Months[0].Expenses != 200 {
+		t.Errorf("unexpected category trends %+v", trends.Categories)
+	}
+	if trends.Categories[0].Months[0].Expenses != 0 {
+		t.Errorf("expected months without expenses to be zero-filled")
+	}
+}
+
+func TestGetTrends_RejectsReversedRange(t *testing.T) {
+	from := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	if _, err := NewAnalyticsService(&mockAnalyticsRepo{}, domain.DefaultReportRules()).GetTrends(context.Background(), "u1", from, to); err != domain.ErrInvalidPeriod {
+		t.Errorf("expected ErrInvalidPeriod, got %v", err)
+	}
+}
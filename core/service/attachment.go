@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"mime/multipart"
+	"personal-finance/core/domain"
+	"personal-finance/core/port"
+	"time"
+)
+
+type AttachmentService struct {
+	attachmentRepo  port.AttachmentRepository
+	transactionRepo port.TransactionRepository
+	adapter         port.FileAdapter
+}
+
+func NewAttachmentService(attachmentRepo port.AttachmentRepository, transactionRepo port.TransactionRepository, adapter port.FileAdapter) *AttachmentService {
+
+	return &AttachmentService{
+		attachmentRepo,
+		transactionRepo,
+		adapter,
+	}
+}
+
+// GetAttachmentsByTransactionId returns the attachments with a fresh URL
+// each, since storage backends may hand out links that expire.
+func (as *AttachmentService) GetAttachmentsByTransactionId(ctx context.Context, transactionId string) ([]domain.Attachment, error) {
+
+	attachments, err := as.attachmentRepo.GetAttachmentsByTransactionId(ctx, transactionId)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	for i := range attachments {
+		if err := as.resolveUrl(ctx, &attachments[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return attachments, nil
+}
+
+// UploadAttachment stores the file and records it against the transaction.
+// The stored file is removed again if the record cannot be saved.
+func (as *AttachmentService) UploadAttachment(
+	ctx context.Context,
+	transactionId string,
+	file multipart.File,
+	fileName string,
+	contentType string,
+) (*domain.Attachment, error) {
+
+	transaction, err := as.transactionRepo.GetTransactionById(ctx, transactionId)
+	if err != nil {
+		if errors.Is(err, domain.ErrDataNotFound) {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, domain.ErrInternal
+	}
+
+	storedFile, err := as.adapter.UploadFile(ctx, file, "attachments/user_"+transaction.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	attachment := domain.Attachment{
+		UserId:        transaction.UserId,
+		TransactionId: transactionId,
+		FileName:      fileName,
+		ContentType:   contentType,
+		Size:          storedFile.Size,
+		PublicId:      storedFile.PublicId,
+		CreatedAt:     time.Now(),
+	}
+
+	created, err := as.attachmentRepo.CreateAttachment(ctx, &attachment)
+	if err != nil {
+		if err := as.adapter.DeleteFile(ctx, storedFile.PublicId); err != nil {
+			slog.Error("error removing orphan attachment file", "public_id", storedFile.PublicId, "error", err)
+		}
+		return nil, domain.ErrInternal
+	}
+
+	created.SecureUrl = storedFile.SecureUrl
+
+	return created, nil
+}
+
+func (as *AttachmentService) DeleteAttachment(ctx context.Context, transactionId string, id string) error {
+
+	attachment, err := as.attachmentRepo.GetAttachmentById(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrDataNotFound) {
+			return domain.ErrDataNotFound
+		}
+		return domain.ErrInternal
+	}
+
+	if attachment.TransactionId != transactionId {
+		return domain.ErrDataNotFound
+	}
+
+	if err := as.adapter.DeleteFile(ctx, attachment.PublicId); err != nil {
+		return err
+	}
+
+	return as.attachmentRepo.DeleteAttachment(ctx, id)
+}
+
+// DeleteAttachmentsByTransactionId removes every file attached to the
+// transaction and then their records. Files that fail to delete are logged
+// and skipped so a storage hiccup does not block the cleanup.
+func (as *AttachmentService) DeleteAttachmentsByTransactionId(ctx context.Context, transactionId string) error {
+
+	attachments, err := as.attachmentRepo.GetAttachmentsByTransactionId(ctx, transactionId)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	for _, attachment := range attachments {
+		if err := as.adapter.DeleteFile(ctx, attachment.PublicId); err != nil {
+			slog.Error("error deleting attachment file", "public_id", attachment.PublicId, "error", err)
+		}
+	}
+
+	if err := as.attachmentRepo.DeleteAttachmentsByTransactionId(ctx, transactionId); err != nil {
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+func (as *AttachmentService) resolveUrl(ctx context.Context, attachment *domain.Attachment) error {
+
+	secureUrl, err := as.adapter.FileUrl(ctx, attachment.PublicId)
+	if err != nil {
+		slog.Error("error resolving attachment url", "public_id", attachment.PublicId, "error", err)
+		return domain.ErrInternal
+	}
+
+	attachment.SecureUrl = secureUrl
+
+	return nil
+}
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"mime/multipart"
+	"testing"
+
+	"personal-finance/core/domain"
+)
+
+// --- mocks ---
+
+type mockAttachmentRepo struct {
+	attachments map[string]*domain.Attachment
+	createErr   error
+	deleted     []string
+}
+
+func (m *mockAttachmentRepo) GetAttachmentsByTransactionId(ctx context.Context, transactionId string) ([]domain.Attachment, error) {
+	var attachments []domain.Attachment
+	for _, attachment := range m.attachments {
+		if attachment.TransactionId == transactionId {
+			attachments = append(attachments, *attachment)
+		}
+	}
+	return attachments, nil
+}
+
+func (m *mockAttachmentRepo) GetAttachmentById(ctx context.Context, id string) (*domain.Attachment, error) {
+	attachment, ok := m.attachments[id]
+	if !ok {
+		return nil, domain.ErrDataNotFound
+	}
+	copy := *attachment
+	return &copy, nil
+}
+
+func (m *mockAttachmentRepo) CreateAttachment(ctx context.Context, attachment *domain.Attachment) (*domain.Attachment, error) {
+	if m.createErr != nil {
+		return nil, m.createErr
+	}
+	attachment.ID = "a-new"
+	return attachment, nil
+}
+
+func (m *mockAttachmentRepo) DeleteAttachment(ctx context.Context, id string) error {
+	m.deleted = append(m.deleted, id)
+	delete(m.attachments, id)
+	return nil
+}
+
+func (m *mockAttachmentRepo) DeleteAttachmentsByTransactionId(ctx context.Context, transactionId string) error {
+	for id, attachment := range m.attachments {
+		if attachment.TransactionId == transactionId {
+			m.deleted = append(m.deleted, id)
+			delete(m.attachments, id)
+		}
+	}
+	return nil
+}
+
+type mockFileAdapter struct {
+	deleteErr    error
+	deletedFiles []string
+}
+
+func (m *mockFileAdapter) UploadFile(ctx context.Context, file multipart.File, folder string) (*domain.StoredFile, error) {
+	return &domain.StoredFile{PublicId: folder + "/receipt", SecureUrl: "https://files/receipt", Size: 42}, nil
+}
+
+func (m *mockFileAdapter) DeleteFile(ctx context.Context, publicId string) error {
+	m.deletedFiles = append(m.deletedFiles, publicId)
+	return m.deleteErr
+}
+
+func (m *mockFileAdapter) FileUrl(ctx context.Context, publicId string) (string, error) {
+	return "https://files/" + publicId + "?expires=1", nil
+}
+
+func newAttachmentTransactionRepo() *mockTransactionRepo {
+	return &mockTransactionRepo{
+		getByIdFunc: func(ctx context.Context, id string) (*domain.Transaction, error) {
+			if id != "t1" {
+				return nil, domain.ErrDataNotFound
+			}
+			return &domain.Transaction{ID: "t1", UserId: "u1"}, nil
+		},
+	}
+}
+
+func TestUploadAttachment_RecordsFile(t *testing.T) {
+	repo := &mockAttachmentRepo{attachments: map[string]*domain.Attachment{}}
+	adapter := &mockFileAdapter{}
+	as := NewAttachmentService(repo, newAttachmentTransactionRepo(), adapter)
+
+	attachment, err := as.UploadAttachment(context.Background(), "t1", nil, "receipt.pdf", "application/pdf")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if attachment.UserId != "u1" || attachment.TransactionId != "t1" || attachment.PublicId != "attachments/user_u1/receipt" {
+		t.Errorf("unexpected attachment %+v", attachment)
+	}
+	if len(adapter.deletedFiles) != 0 {
+		t.Errorf("expected no file removed, got %v", adapter.deletedFiles)
+	}
+}
+
+func TestGetAttachmentsByTransactionId_ResolvesUrls(t *testing.T) {
+	repo := &mockAttachmentRepo{attachments: map[string]*domain.Attachment{
+		"a1": {ID: "a1", TransactionId: "t1", PublicId: "attachments/user_u1/receipt", SecureUrl: "https://files/stale"},
+	}}
+	as := NewAttachmentService(repo, newAttachmentTransactionRepo(), &mockFileAdapter{})
+
+	attachments, err := as.GetAttachmentsByTransactionId(context.Background(), "t1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(attachments) != 1 || attachments[0].SecureUrl != "https://files/attachments/user_u1/receipt?expires=1" {
+		t.Errorf("expected a freshly resolved url, got %+v", attachments)
+	}
+}
+
+func TestUploadAttachment_RollsBackFileWhenRecordFails(t *testing.T) {
+	repo := &mockAttachmentRepo{attachments: map[string]*domain.Attachment{}, createErr: errors.New("insert failed")}
+	adapter := &mockFileAdapter{}
+	as := NewAttachmentService(repo, newAttachmentTransactionRepo(), adapter)
+
+	if _, err := as.UploadAttachment(context.Background(), "t1", nil, "receipt.pdf", "application/pdf"); err != domain.ErrInternal {
+		t.Fatalf("expected ErrInternal, got %v", err)
+	}
+
+	if len(adapter.deletedFiles) != 1 || adapter.deletedFiles[0] != "attachments/user_u1/receipt" {
+		t.Errorf("expected the stored file removed, got %v", adapter.deletedFiles)
+	}
+}
+
+func TestUploadAttachment_UnknownTransaction(t *testing.T) {
+	adapter := &mockFileAdapter{}
+	as := NewAttachmentService(&mockAttachmentRepo{}, newAttachmentTransactionRepo(), adapter)
+
+	if _, err := as.UploadAttachment(context.Background(), "t9", nil, "receipt.pdf", "application/pdf"); err != domain.ErrDataNotFound {
+		t.Fatalf("expected ErrDataNotFound, got %v", err)
+	}
+}
+
+func TestDeleteAttachment_OtherTransaction(t *testing.T) {
+	repo := &mockAttachmentRepo{attachments: map[string]*domain.Attachment{
+		"a1": {ID: "a1", TransactionId: "t2", PublicId: "attachments/user_u2/receipt"},
+	}}
+	adapter := &mockFileAdapter{}
+	as := NewAttachmentService(repo, newAttachmentTransactionRepo(), adapter)
+
+	if err := as.DeleteAttachment(context.Background(), "t1", "a1"); err != domain.ErrDataNotFound {
+		t.Fatalf("expected ErrDataNotFound, got %v", err)
+	}
+
+	if len(adapter.deletedFiles) != 0 || len(repo.deleted) != 0 {
+		t.Errorf("expected nothing deleted, got files %v and records %v", adapter.deletedFiles, repo.deleted)
+	}
+}
+
+func TestDeleteAttachment_RemovesFileAndRecord(t *testing.T) {
+	repo := &mockAttachmentRepo{attachments: map[string]*domain.Attachment{
+		"a1": {ID: "a1", TransactionId: "t1", PublicId: "attachments/user_u1/receipt"},
+	}}
+	adapter := &mockFileAdapter{}
+	as := NewAttachmentService(repo, newAttachmentTransactionRepo(), adapter)
+
+	if err := as.DeleteAttachment(context.Background(), "t1", "a1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(adapter.deletedFiles) != 1 || len(repo.deleted) != 1 {
+		t.Errorf("expected the file and record deleted, got files %v and records %v", adapter.deletedFiles, repo.deleted)
+	}
+}
+
+func TestDeleteAttachmentsByTransactionId_SkipsFailedFiles(t *testing.T) {
+	repo := &mockAttachmentRepo{attachments: map[string]*domain.Attachment{
+		"a1": {ID: "a1", TransactionId: "t1", PublicId: "p1"},
+		"a2": {ID: "a2", TransactionId: "t1", PublicId: "p2"},
+		"a3": {ID: "a3", TransactionId: "t2", PublicId: "p3"},
+	}}
+	adapter := &mockFileAdapter{deleteErr: errors.New("storage unavailable")}
+	as := NewAttachmentService(repo, newAttachmentTransactionRepo(), adapter)
+
+	if err := as.DeleteAttachmentsByTransactionId(context.Background(), "t1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(adapter.deletedFiles) != 2 {
+		t.Errorf("expected both files of t1 attempted, got %v", adapter.deletedFiles)
+	}
+	if len(repo.attachments) != 1 || repo.attachments["a3"] == nil {
+		t.Errorf("expected only the attachment of t2 left, got %v", repo.attachments)
+	}
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"personal-finance/core/domain"
+	"personal-finance/core/port"
+	"time"
+)
+
+type AuditService struct {
+	repo port.AuditRepository
+}
+
+func NewAuditService(repo port.AuditRepository) *AuditService {
+
+	return &AuditService{
+		repo,
+	}
+}
+
+// Record stores the fields that changed between before and after, on behalf
+// of the user in ctx. Updates that change nothing are not recorded. It writes
+// with ctx so callers record within the transaction making the change.
+func (as *AuditService) Record(ctx context.Context, userId, entity, entityId, action string, before, after any) error {
+
+	changes := domain.Diff(before, after)
+	if action == domain.AuditUpdate && len(changes) == 0 {
+		return nil
+	}
+
+	record := &domain.AuditRecord{
+		UserId:    userId,
+		ActorId:   domain.ActorFromContext(ctx),
+		Entity:    entity,
+		EntityId:  entityId,
+		Action:    action,
+		Changes:   changes,
+		CreatedAt: time.Now(),
+	}
+
+	if err := as.repo.CreateRecord(ctx, record); err != nil {
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+func (as *AuditService) GetHistory(ctx context.Context, userId, entity, entityId string, page, limit uint64) ([]domain.AuditRecord, int64, int, error) {
+
+	records, totalDocuments, totalPages, err := as.repo.GetHistory(ctx, userId, entity, entityId, page, limit)
+	if err != nil {
+		return nil, 0, 0, domain.ErrInternal
+	}
+
+	return records, totalDocuments, totalPages, nil
+}
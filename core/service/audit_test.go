@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"personal-finance/core/domain"
+)
+
+type mockAuditRepo struct {
+	records []domain.AuditRecord
+}
+
+func (m *mockAuditRepo) CreateRecord(ctx context.Context, record *domain.AuditRecord) error {
+	m.records = append(m.records, *record)
+	return nil
+}
+
+func (m *mockAuditRepo) GetHistory(ctx context.Context, userId, entity, entityId string, page, limit uint64) ([]domain.AuditRecord, int64, int, error) {
+	return m.records, int64(len(m.records)), 1, nil
+}
+
+func TestDiff(t *testing.T) {
+	before := domain.User{ID: "u1", Username: "ana", Email: "ana@mail.com", Password: "old", Locale: "es"}
+	after := domain.User{ID: "u1", Username: "ana", Email: "ana@example.com", Password: "new"}
+
+	changes := domain.Diff(&before, &after)
+	if len(changes) != 3 {
+		t.Fatalf("expected email, locale and password changes, got %+v", changes)
+	}
+
+	if c := changes[0]; c.Field != "email" || c.Before != "ana@mail.com" || c.After != "ana@example.com" {
+		t.Errorf("email change = %+v", c)
+	}
+	if c := changes[1]; c.Field != "locale" || c.Before != "es" || c.After != "" {
+		t.Errorf("locale change = %+v", c)
+	}
+	if c := changes[2]; c.Field != "password" || c.Before != "[redacted]" || c.After != "[redacted]" {
+		t.Errorf("expected the password redacted, got %+v", c)
+	}
+
+	if created := domain.Diff(nil, &after); len(created) != 3 {
+		t.Errorf("expected every set field on create, got %+v", created)
+	}
+}
+
+func TestDiff_ChangesToZeroValues(t *testing.T) {
+	before := domain.Transaction{ID: "t1", UserId: "u1", Type: "Output", Amount: 100, Cleared: true, Description: "Lunch"}
+	after := domain.Transaction{ID: "t1", UserId: "u1", Type: "Output", Amount: 0, Cleared: false, Description: "Lunch"}
+
+	changes := domain.Diff(&before, &after)
+	if len(changes) != 2 {
+		t.Fatalf("expected amount and cleared changes, got %+v", changes)
+	}
+
+	if c := changes[0]; c.Field != "amount" || c.Before != float64(100) || c.After != float64(0) {
+		t.Errorf("amount change = %+v", c)
+	}
+	if c := changes[1]; c.Field != "cleared" || c.Before != true || c.After != false {
+		t.Errorf("cleared change = %+v", c)
+	}
+
+	// and back from zero
+	if changes := domain.Diff(&after, &before); len(changes) != 2 || changes[1].Before != false || changes[1].After != true {
+		t.Errorf("expected the cleared change from false, got %+v", changes)
+	}
+}
+
+func TestUpdateTransaction_RecordsAudit(t *testing.T) {
+	actual := &domain.Transaction{ID: "t1", UserId: "u1", OriginId: strPtr("o1"), Type: "Output", Amount: 100, Description: "Lunch"}
+	updated := &domain.Transaction{UserId: "u1", OriginId: strPtr("o1"), Type: "Output", Amount: 120, Description: "Lunch"}
+
+	oRepo := newMockOriginRepo(map[string]*domain.Origin{"o1": {ID: "o1", Total: 500}})
+	tRepo := &mockTransactionRepo{
+		getByIdFunc: func(ctx context.Context, id string) (*domain.Transaction, error) { return actual, nil },
+		updateFunc: func(ctx context.Context, id string, tx *domain.Transaction) (*domain.Transaction, error) {
+			return tx, nil
+		},
+	}
+	aRepo := &mockAuditRepo{}
+	ts := NewTransactionService(tRepo, oRepo, noopTxManager{}, nil, NewSnapshotService(newMockSnapshotRepo(), oRepo), NewLedgerService(&mockLedgerRepo{}, oRepo, tRepo, noopTxManager{}), NewAuditService(aRepo))
+
+	ctx := context.WithValue(context.Background(), domain.ActorContextKey, "u1")
+	if _, err := ts.UpdateTransaction(ctx, "t1", updated); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(aRepo.records) != 1 {
+		t.Fatalf("expected one audit record, got %+v", aRepo.records)
+	}
+
+	record := aRepo.records[0]
+	if record.Entity != domain.AuditTransaction || record.EntityId != "t1" || record.Action != domain.AuditUpdate || record.ActorId != "u1" {
+		t.Errorf("record = %+v", record)
+	}
+	if len(record.Changes) != 1 || record.Changes[0].Field != "amount" || record.Changes[0].Before != 100.0 || record.Changes[0].After != 120.0 {
+		t.Errorf("changes = %+v", record.Changes)
+	}
+}
@@ -6,19 +6,32 @@ import (
 	"mime/multipart"
 	"personal-finance/core/domain"
 	"personal-finance/core/port"
+
+	"golang.org/x/text/currency"
+	"golang.org/x/text/language"
 )
 
 type AuthService struct {
 	authRepo        port.AuthRepository
 	transactionRepo port.TransactionRepository
 	adapter         port.ImageAdapter
+	auditService    port.AuditService
+	txManager       port.TransactionManager
 }
 
-func NewAuthService(authRepo port.AuthRepository, transactionRepo port.TransactionRepository, adapter port.ImageAdapter) *AuthService {
+func NewAuthService(
+	authRepo port.AuthRepository,
+	transactionRepo port.TransactionRepository,
+	adapter port.ImageAdapter,
+	auditService port.AuditService,
+	txManager port.TransactionManager) *AuthService {
+
 	return &AuthService{
 		authRepo,
 		transactionRepo,
 		adapter,
+		auditService,
+		txManager,
 	}
 }
 
@@ -51,22 +64,86 @@ func (as *AuthService) CreateUser(ctx context.Context, user *domain.User) (*doma
 	return user, nil
 }
 
+// UpdateUser saves the user's profile and records what changed in the same
+// transaction.
 func (as *AuthService) UpdateUser(ctx context.Context, id string, user *domain.User) (*domain.User, error) {
 
-	_, err := as.authRepo.UpdateUser(ctx, id, user)
-	if err != nil {
-		if err == domain.ErrConflictingData {
-			return nil, err
+	err := as.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+
+		actual, err := as.GetUserById(txCtx, id)
+		if err != nil {
+			return err
 		}
-		if err == domain.ErrDataNotFound {
-			return nil, domain.ErrDataNotFound
+
+		_, err = as.authRepo.UpdateUser(txCtx, id, user)
+		if err != nil {
+			if err == domain.ErrConflictingData {
+				return err
+			}
+			if err == domain.ErrDataNotFound {
+				return domain.ErrDataNotFound
+			}
+			return domain.ErrInternal
 		}
-		return nil, domain.ErrInternal
+
+		return as.auditService.Record(txCtx, id, domain.AuditUser, id, domain.AuditUpdate, actual, user)
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return user, nil
 }
 
+// UpdatePreferences validates and saves the user's report preferences. The
+// locale is stored as a canonical BCP 47 tag and the currency as its ISO 4217 code.
+func (as *AuthService) UpdatePreferences(ctx context.Context, id string, preferences domain.UserPreferences) error {
+
+	if preferences.MonthlyReportOptOut == nil && preferences.Locale == nil && preferences.Currency == nil {
+		return domain.ErrNoUpdatedData
+	}
+
+	if preferences.Locale != nil {
+		tag, err := language.Parse(*preferences.Locale)
+		if err != nil {
+			return domain.ErrInvalidLocale
+		}
+		locale := tag.String()
+		preferences.Locale = &locale
+	}
+
+	if preferences.Currency != nil {
+		unit, err := currency.ParseISO(*preferences.Currency)
+		if err != nil {
+			return domain.ErrInvalidCurrency
+		}
+		code := unit.String()
+		preferences.Currency = &code
+	}
+
+	return as.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+
+		actual, err := as.GetUserById(txCtx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := as.authRepo.UpdatePreferences(txCtx, id, preferences); err != nil {
+			if err == domain.ErrDataNotFound {
+				return err
+			}
+			return domain.ErrInternal
+		}
+
+		updated, err := as.GetUserById(txCtx, id)
+		if err != nil {
+			return err
+		}
+
+		return as.auditService.Record(txCtx, id, domain.AuditUser, id, domain.AuditUpdate, actual, updated)
+	})
+}
+
 func (as *AuthService) UpdateUserProfileImage(ctx context.Context, file multipart.File, userId string) (*domain.Image, error) {
 
 	uploadedImage, err := as.adapter.UploadImageFromFile(ctx, file, userId)
@@ -114,7 +191,19 @@ func (as *AuthService) VerifyUserEmail(ctx context.Context, email string) (bool,
 
 func (as *AuthService) DeleteUser(ctx context.Context, id string) error {
 
-	return as.authRepo.DeleteUser(ctx, id)
+	return as.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+
+		user, err := as.GetUserById(txCtx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := as.authRepo.DeleteUser(txCtx, id); err != nil {
+			return err
+		}
+
+		return as.auditService.Record(txCtx, id, domain.AuditUser, id, domain.AuditDelete, user, nil)
+	})
 }
 
 func (as *AuthService) DeleteTransactionsByUserId(ctx context.Context, id string) error {
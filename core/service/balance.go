@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"math"
+	"personal-finance/core/domain"
+	"personal-finance/core/port"
+)
+
+type BalanceService struct {
+	repo               port.BalanceRepository
+	originRepo         port.OriginRepository
+	transactionService port.TransactionService
+	ledgerService      port.LedgerService
+	txManager          port.TransactionManager
+}
+
+func NewBalanceService(
+	repo port.BalanceRepository,
+	originRepo port.OriginRepository,
+	transactionService port.TransactionService,
+	ledgerService port.LedgerService,
+	txManager port.TransactionManager) *BalanceService {
+
+	return &BalanceService{
+		repo,
+		originRepo,
+		transactionService,
+		ledgerService,
+		txManager,
+	}
+}
+
+// AuditBalances recomputes every origin of the user from its opening balance
+// and transactions and reports where the stored Total or the origin's ledger
+// balance disagrees.
+func (bs *BalanceService) AuditBalances(ctx context.Context, userId string) (*domain.BalanceAudit, error) {
+
+	origins, err := bs.auditOrigins(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return balanceAudit(userId, origins, false), nil
+}
+
+// RepairBalances audits the user's origins and sets each stored Total to the
+// computed one, journaling an adjustment wherever the ledger balance is off.
+// Origins without an opening balance get one derived from their current
+// Total. All origins are repaired or none is.
+func (bs *BalanceService) RepairBalances(ctx context.Context, userId string) (*domain.BalanceAudit, error) {
+
+	var audit *domain.BalanceAudit
+
+	err := bs.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+
+		origins, err := bs.auditOrigins(txCtx, userId)
+		if err != nil {
+			return err
+		}
+
+		for i, check := range origins {
+
+			if check.MissingOpening {
+				if err := bs.setOpeningBalance(txCtx, check); err != nil {
+					return err
+				}
+				origins[i].Repaired = true
+			}
+
+			if check.Difference != 0 {
+				liability := domain.Origin{Kind: check.Kind}.IsLiability()
+				if err := bs.transactionService.UpdateTotalOrigin(txCtx, check.OriginId, adjustmentType(check.Difference, liability), math.Abs(check.Difference)); err != nil {
+					return err
+				}
+				origins[i].Repaired = true
+			}
+
+			if check.LedgerDifference != 0 {
+				if err := bs.postAdjustment(txCtx, check); err != nil {
+					return err
+				}
+				origins[i].Repaired = true
+			}
+		}
+
+		audit = balanceAudit(userId, origins, true)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return audit, nil
+}
+
+func (bs *BalanceService) setOpeningBalance(ctx context.Context, check domain.BalanceCheck) error {
+
+	origin, err := bs.originRepo.GetOriginById(ctx, check.OriginId)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return err
+		}
+		return domain.ErrInternal
+	}
+
+	opening := check.OpeningBalance
+	origin.OpeningBalance = &opening
+
+	if _, err := bs.originRepo.UpdateOrigin(ctx, check.OriginId, origin); err != nil {
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+// postAdjustment journals the amount that brings the origin's ledger balance
+// to the computed one.
+func (bs *BalanceService) postAdjustment(ctx context.Context, check domain.BalanceCheck) error {
+
+	origin, err := bs.originRepo.GetOriginById(ctx, check.OriginId)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return err
+		}
+		return domain.ErrInternal
+	}
+
+	return bs.ledgerService.PostOpening(ctx, origin, check.LedgerDifference, domain.EntryAdjustment)
+}
+
+func (bs *BalanceService) auditOrigins(ctx context.Context, userId string) ([]domain.BalanceCheck, error) {
+
+	origins, err := bs.originRepo.GetOriginsByUserId(ctx, userId, true)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	activity, err := bs.repo.GetOriginActivity(ctx, userId)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	trialBalance, err := bs.ledgerService.GetTrialBalance(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	byOrigin := make(map[string]domain.OriginActivity, len(activity))
+	for _, origin := range activity {
+		byOrigin[origin.OriginId] = origin
+	}
+
+	ledger := make(map[string]float64, len(trialBalance.Accounts))
+	for _, account := range trialBalance.Accounts {
+		ledger[account.Account] = account.Balance
+	}
+
+	checks := make([]domain.BalanceCheck, 0, len(origins))
+	for _, origin := range origins {
+		check := checkBalance(origin, byOrigin[origin.ID])
+		if balance, ok := ledger[domain.OriginAccount(origin.ID)]; ok {
+			check.Ledger = &balance
+			check.LedgerDifference = round2(check.Computed - balance)
+		}
+		checks = append(checks, check)
+	}
+
+	return checks, nil
+}
+
+// checkBalance replays the origin's activity over its opening balance. For an
+// origin without one, the opening balance is worked back from its Total.
+func checkBalance(origin domain.Origin, activity domain.OriginActivity) domain.BalanceCheck {
+
+	check := domain.BalanceCheck{
+		OriginId:     origin.ID,
+		Name:         origin.Name,
+		Kind:         origin.OriginKind(),
+		Transactions: activity.Transactions,
+		Stored:       round2(origin.Total),
+	}
+
+	if origin.OpeningBalance == nil {
+		opening := origin
+		opening.ApplyTransaction("Output", activity.Income)
+		opening.ApplyTransaction("Income", activity.Output)
+
+		check.OpeningBalance = round2(opening.Total)
+		check.Computed = check.Stored
+		check.MissingOpening = true
+
+		return check
+	}
+
+	computed := origin
+	computed.Total = *origin.OpeningBalance
+	computed.ApplyTransaction("Income", activity.Income)
+	computed.ApplyTransaction("Output", activity.Output)
+
+	check.OpeningBalance = round2(*origin.OpeningBalance)
+	check.Computed = round2(computed.Total)
+	check.Difference = round2(check.Computed - check.Stored)
+
+	return check
+}
+
+func balanceAudit(userId string, origins []domain.BalanceCheck, repaired bool) *domain.BalanceAudit {
+
+	audit := domain.BalanceAudit{
+		UserId:   userId,
+		Repaired: repaired,
+		Origins:  origins,
+	}
+
+	for _, check := range origins {
+		if check.Difference != 0 || check.LedgerDifference != 0 {
+			audit.Discrepancies++
+		}
+	}
+
+	return &audit
+}
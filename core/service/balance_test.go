@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"personal-finance/core/domain"
+)
+
+type mockBalanceRepo struct {
+	activity []domain.OriginActivity
+}
+
+func (m *mockBalanceRepo) GetOriginActivity(ctx context.Context, userId string) ([]domain.OriginActivity, error) {
+	return m.activity, nil
+}
+
+func newBalanceFixture() (*BalanceService, *mockOriginRepo, *LedgerService) {
+
+	oRepo := newMockOriginRepo(map[string]*domain.Origin{
+		"checking": {ID: "checking", UserId: "u1", Total: 900, OpeningBalance: floatPtr(500)},
+		"card":     {ID: "card", UserId: "u1", Kind: domain.OriginCreditCard, Total: 300, OpeningBalance: floatPtr(0)},
+		"cash":     {ID: "cash", UserId: "u1", Total: 80},
+		"savings":  {ID: "savings", UserId: "u1", Total: 1000, OpeningBalance: floatPtr(1000)},
+	})
+	bRepo := &mockBalanceRepo{activity: []domain.OriginActivity{
+		{OriginId: "checking", Income: 700, Output: 250, Transactions: 3},
+		{OriginId: "card", Income: 100, Output: 350, Transactions: 2},
+		{OriginId: "cash", Income: 100, Output: 40, Transactions: 2},
+	}}
+	ledger := NewLedgerService(&mockLedgerRepo{}, oRepo, &mockTransactionRepo{}, noopTxManager{})
+	// checking is journaled at its stored 900 and savings at 1000; card and
+	// cash are not journaled yet.
+	ledger.PostOpening(context.Background(), oRepo.origins["checking"], 900, domain.EntryOpening)
+	ledger.PostOpening(context.Background(), oRepo.origins["savings"], 1000, domain.EntryOpening)
+	svc := NewBalanceService(bRepo, oRepo, newTransactionService(&mockTransactionRepo{}, oRepo), ledger, noopTxManager{})
+
+	return svc, oRepo, ledger
+}
+
+func checksByOrigin(audit *domain.BalanceAudit) map[string]domain.BalanceCheck {
+	checks := map[string]domain.BalanceCheck{}
+	for _, check := range audit.Origins {
+		checks[check.OriginId] = check
+	}
+	return checks
+}
+
+func TestAuditBalances_ReportsDiscrepancies(t *testing.T) {
+	svc, _, _ := newBalanceFixture()
+
+	audit, err := svc.AuditBalances(context.Background(), "u1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	checks := checksByOrigin(audit)
+
+	if c := checks["checking"]; c.Computed != 950 || c.Difference != 50 || c.Ledger == nil || *c.Ledger != 900 || c.LedgerDifference != 50 {
+		t.Errorf("checking = %+v", c)
+	}
+	// 350 charged and 100 paid leave 250 owed.
+	if c := checks["card"]; c.Computed != 250 || c.Difference != -50 || c.Ledger != nil || c.LedgerDifference != 0 {
+		t.Errorf("card = %+v", c)
+	}
+	if c := checks["cash"]; !c.MissingOpening || c.OpeningBalance != 20 || c.Difference != 0 {
+		t.Errorf("cash = %+v", c)
+	}
+	if c := checks["savings"]; c.Difference != 0 || c.LedgerDifference != 0 {
+		t.Errorf("savings = %+v", c)
+	}
+	if audit.Discrepancies != 2 || audit.Repaired {
+		t.Errorf("audit = %+v", audit)
+	}
+}
+
+func TestRepairBalances(t *testing.T) {
+	svc, oRepo, ledger := newBalanceFixture()
+
+	audit, err := svc.RepairBalances(context.Background(), "u1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !audit.Repaired {
+		t.Errorf("expected a repaired audit, got %+v", audit)
+	}
+	if got := oRepo.origins["checking"].Total; got != 950 {
+		t.Errorf("expected checking repaired to 950, got %v", got)
+	}
+	if got := oRepo.origins["card"].Total; got != 250 {
+		t.Errorf("expected card repaired to 250 owed, got %v", got)
+	}
+	if opening := oRepo.origins["cash"].OpeningBalance; opening == nil || *opening != 20 || oRepo.origins["cash"].Total != 80 {
+		t.Errorf("cash = %+v", oRepo.origins["cash"])
+	}
+
+	if got := accountBalance(t, ledger, domain.OriginAccount("checking")); got != 950 {
+		t.Errorf("expected checking journaled at 950, got %v", got)
+	}
+
+	audit, _ = svc.AuditBalances(context.Background(), "u1")
+	if audit.Discrepancies != 0 {
+		t.Errorf("expected no discrepancies after repair, got %+v", audit)
+	}
+}
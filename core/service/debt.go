@@ -0,0 +1,322 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"personal-finance/core/domain"
+	"personal-finance/core/port"
+	"time"
+)
+
+type DebtService struct {
+	debtRepo           port.DebtRepository
+	originRepo         port.OriginRepository
+	transactionService port.TransactionService
+	txManager          port.TransactionManager
+}
+
+func NewDebtService(
+	debtRepo port.DebtRepository,
+	originRepo port.OriginRepository,
+	transactionService port.TransactionService,
+	txManager port.TransactionManager) *DebtService {
+
+	return &DebtService{
+		debtRepo,
+		originRepo,
+		transactionService,
+		txManager,
+	}
+}
+
+func (ds *DebtService) GetDebtsByUserId(ctx context.Context, userId string) ([]domain.Debt, error) {
+
+	debts, err := ds.debtRepo.GetDebtsByUserId(ctx, userId)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return debts, nil
+}
+
+func (ds *DebtService) GetDebtById(ctx context.Context, id string) (*domain.Debt, error) {
+
+	debt, err := ds.debtRepo.GetDebtById(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrDataNotFound) {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, domain.ErrInternal
+	}
+
+	return debt, nil
+}
+
+func (ds *DebtService) CreateDebt(ctx context.Context, debt *domain.Debt) (*domain.Debt, error) {
+
+	if err := ds.checkLoanOrigin(ctx, debt); err != nil {
+		return nil, err
+	}
+
+	debt.StartDate = domain.SnapshotDay(debt.StartDate)
+
+	debt, err := ds.debtRepo.CreateDebt(ctx, debt)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return debt, nil
+}
+
+func (ds *DebtService) UpdateDebt(ctx context.Context, id string, debt *domain.Debt) (*domain.Debt, error) {
+
+	if err := ds.checkLoanOrigin(ctx, debt); err != nil {
+		return nil, err
+	}
+
+	debt.StartDate = domain.SnapshotDay(debt.StartDate)
+
+	_, err := ds.debtRepo.UpdateDebt(ctx, id, debt)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	return debt, nil
+}
+
+func (ds *DebtService) DeleteDebt(ctx context.Context, id string) error {
+
+	err := ds.debtRepo.DeleteDebt(ctx, id)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return err
+		}
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+// checkLoanOrigin makes sure the debt is tracked against a loan origin owned
+// by the same user.
+func (ds *DebtService) checkLoanOrigin(ctx context.Context, debt *domain.Debt) error {
+
+	origin, err := ds.originRepo.GetOriginById(ctx, debt.OriginId)
+	if err != nil {
+		return domain.ErrInvalidDebtOrigin
+	}
+
+	if origin.UserId != debt.UserId || origin.OriginKind() != domain.OriginLoan {
+		return domain.ErrInvalidDebtOrigin
+	}
+
+	return nil
+}
+
+// GetDebtStatus totals the payments recorded against the debt and projects
+// when the remaining balance is paid off at the scheduled monthly payment.
+func (ds *DebtService) GetDebtStatus(ctx context.Context, id string) (*domain.DebtStatus, error) {
+
+	debt, err := ds.GetDebtById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	payments, err := ds.debtRepo.GetDebtPayments(ctx, debt)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return debtStatus(*debt, payments), nil
+}
+
+func debtStatus(debt domain.Debt, payments []domain.Transaction) *domain.DebtStatus {
+
+	status := domain.DebtStatus{
+		Debt:           debt,
+		MonthlyPayment: debt.MonthlyPayment(),
+	}
+
+	for _, payment := range payments {
+		status.PrincipalPaid += payment.DebtPayment.Principal
+		status.InterestPaid += payment.DebtPayment.Interest
+		status.PaymentsMade++
+	}
+
+	status.PrincipalPaid = round2(status.PrincipalPaid)
+	status.InterestPaid = round2(status.InterestPaid)
+	status.RemainingBalance = round2(math.Max(0, debt.Principal-status.PrincipalPaid))
+
+	if status.RemainingBalance > 0 {
+		next := debt.PaymentDate(status.PaymentsMade + 1)
+		status.NextPaymentDate = &next
+
+		if schedule := debt.Amortize(status.RemainingBalance, status.PaymentsMade+1, 0); len(schedule) > 0 {
+			status.PayoffDate = &schedule[len(schedule)-1].Date
+		}
+	}
+
+	return &status
+}
+
+// GetSchedule returns the debt's original amortization schedule.
+func (ds *DebtService) GetSchedule(ctx context.Context, id string) ([]domain.AmortizationRow, error) {
+
+	debt, err := ds.GetDebtById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule := debt.Amortize(debt.Principal, 1, 0)
+	if schedule == nil {
+		schedule = []domain.AmortizationRow{}
+	}
+
+	return schedule, nil
+}
+
+// RecordPayment books a payment of amount on date. A month of interest on the
+// remaining balance is charged first and the rest reduces the principal. The
+// principal is booked as an Income on the loan origin, lowering what is owed;
+// when fromOriginId is set the whole payment is also booked as an Output on
+// that origin. Both writes commit or neither does. It returns the loan
+// origin's transaction.
+func (ds *DebtService) RecordPayment(ctx context.Context, id string, amount float64, date time.Time, fromOriginId string) (*domain.Transaction, error) {
+
+	var loanTransaction *domain.Transaction
+
+	err := ds.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+
+		status, err := ds.GetDebtStatus(txCtx, id)
+		if err != nil {
+			return err
+		}
+
+		split, err := splitDebtPayment(status, amount)
+		if err != nil {
+			return err
+		}
+
+		debt := status.Debt
+
+		loanTransaction = debtPaymentTransaction(debt, debt.OriginId, "Income", split.Principal, split, date, status.PaymentsMade+1)
+		if err := ds.transactionService.BookTransaction(txCtx, loanTransaction); err != nil {
+			return err
+		}
+
+		if fromOriginId != "" {
+			paid := round2(split.Principal + split.Interest)
+			if err := ds.transactionService.BookTransaction(txCtx, debtPaymentTransaction(debt, fromOriginId, "Output", paid, split, date, status.PaymentsMade+1)); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return loanTransaction, nil
+}
+
+// splitDebtPayment charges a month of interest on the remaining balance and
+// applies the rest of amount to the principal, never beyond what is left.
+func splitDebtPayment(status *domain.DebtStatus, amount float64) (domain.DebtPayment, error) {
+
+	if status.RemainingBalance <= 0 {
+		return domain.DebtPayment{}, domain.ErrDebtPaidOff
+	}
+
+	interest := round2(status.RemainingBalance * status.Debt.MonthlyRate())
+	if amount <= interest {
+		return domain.DebtPayment{}, domain.ErrPaymentBelowInterest
+	}
+
+	return domain.DebtPayment{
+		DebtId:    status.Debt.ID,
+		Principal: math.Min(status.RemainingBalance, round2(amount-interest)),
+		Interest:  interest,
+	}, nil
+}
+
+func debtPaymentTransaction(debt domain.Debt, originId string, transactionType string, amount float64, split domain.DebtPayment, date time.Time, number int) *domain.Transaction {
+
+	transaction := &domain.Transaction{
+		UserId:           debt.UserId,
+		OriginId:         &originId,
+		Amount:           amount,
+		Type:             transactionType,
+		Subject:          "Payment",
+		PersonOrBusiness: debt.Name,
+		Description:      fmt.Sprintf("%s payment %d", debt.Name, number),
+		DebtPayment:      &split,
+		CreatedAtString:  date.Format("2006-01-02"),
+		CreatedAt:        date,
+		UpdatedAt:        time.Now(),
+	}
+
+	if transactionType == "Output" {
+		transaction.OutputCategory = "Debt"
+	}
+
+	return transaction
+}
+
+// SimulateExtraPayments compares paying the remaining balance on schedule
+// with paying lumpSum now and extraMonthly on top of every payment.
+func (ds *DebtService) SimulateExtraPayments(ctx context.Context, id string, extraMonthly, lumpSum float64) (*domain.DebtSimulation, error) {
+
+	status, err := ds.GetDebtStatus(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return simulateExtraPayments(status, extraMonthly, lumpSum), nil
+}
+
+func simulateExtraPayments(status *domain.DebtStatus, extraMonthly, lumpSum float64) *domain.DebtSimulation {
+
+	simulation := domain.DebtSimulation{
+		ExtraMonthly: extraMonthly,
+		LumpSum:      lumpSum,
+		Schedule:     []domain.AmortizationRow{},
+	}
+
+	debt := status.Debt
+	first := status.PaymentsMade + 1
+
+	baseline := debt.Amortize(status.RemainingBalance, first, 0)
+	if len(baseline) > 0 {
+		simulation.BaselinePayoffDate = &baseline[len(baseline)-1].Date
+	}
+	simulation.BaselineInterest = totalInterest(baseline)
+
+	if schedule := debt.Amortize(math.Max(0, status.RemainingBalance-lumpSum), first, extraMonthly); schedule != nil {
+		simulation.Schedule = schedule
+		simulation.PayoffDate = &schedule[len(schedule)-1].Date
+	}
+	simulation.TotalInterest = totalInterest(simulation.Schedule)
+
+	if len(baseline) > 0 {
+		simulation.InterestSaved = round2(simulation.BaselineInterest - simulation.TotalInterest)
+		simulation.MonthsSaved = len(baseline) - len(simulation.Schedule)
+	}
+
+	return &simulation
+}
+
+func totalInterest(schedule []domain.AmortizationRow) float64 {
+
+	var total float64
+	for _, row := range schedule {
+		total += row.Interest
+	}
+
+	return round2(total)
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"personal-finance/core/domain"
+)
+
+type mockDebtRepo struct {
+	debts    map[string]*domain.Debt
+	payments []domain.Transaction
+}
+
+func (m *mockDebtRepo) GetDebtsByUserId(ctx context.Context, userId string) ([]domain.Debt, error) {
+	var debts []domain.Debt
+	for _, debt := range m.debts {
+		if debt.UserId == userId {
+			debts = append(debts, *debt)
+		}
+	}
+	return debts, nil
+}
+
+func (m *mockDebtRepo) GetDebtById(ctx context.Context, id string) (*domain.Debt, error) {
+	debt, ok := m.debts[id]
+	if !ok {
+		return nil, domain.ErrDataNotFound
+	}
+	return debt, nil
+}
+
+func (m *mockDebtRepo) CreateDebt(ctx context.Context, debt *domain.Debt) (*domain.Debt, error) {
+	return debt, nil
+}
+
+func (m *mockDebtRepo) UpdateDebt(ctx context.Context, id string, debt *domain.Debt) (*domain.Debt, error) {
+	return debt, nil
+}
+
+func (m *mockDebtRepo) DeleteDebt(ctx context.Context, id string) error {
+	return nil
+}
+
+func (m *mockDebtRepo) GetDebtPayments(ctx context.Context, debt *domain.Debt) ([]domain.Transaction, error) {
+	return m.payments, nil
+}
+
+func carLoan() domain.Debt {
+	return domain.Debt{
+		ID:           "d1",
+		UserId:       "u1",
+		OriginId:     "loan",
+		Name:         "Car",
+		Principal:    10000,
+		InterestRate: 12,
+		TermMonths:   12,
+		StartDate:    time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestDebtMonthlyPaymentAndSchedule(t *testing.T) {
+	debt := carLoan()
+
+	if got := debt.MonthlyPayment(); got != 888.49 {
+		t.Fatalf("expected monthly payment 888.49, got %v", got)
+	}
+
+	schedule := debt.Amortize(debt.Principal, 1, 0)
+	if len(schedule) != 12 {
+		t.Fatalf("expected 12 payments, got %d", len(schedule))
+	}
+
+	first, last := schedule[0], schedule[len(schedule)-1]
+	if first.Interest != 100 || first.Principal != 788.49 || first.Date.Format("2006-01-02") != "2024-02-15" {
+		t.Errorf("first payment = %+v", first)
+	}
+	if last.Balance != 0 || last.Date.Format("2006-01-02") != "2025-01-15" {
+		t.Errorf("last payment = %+v", last)
+	}
+
+	var principal float64
+	for _, row := range schedule {
+		principal += row.Principal
+	}
+	if round2(principal) != 10000 {
+		t.Errorf("expected the schedule to repay 10000, got %v", principal)
+	}
+}
+
+func TestRecordPayment_SplitsPrincipalAndInterest(t *testing.T) {
+	debt := carLoan()
+	dRepo := &mockDebtRepo{debts: map[string]*domain.Debt{"d1": &debt}}
+	oRepo := newMockOriginRepo(map[string]*domain.Origin{
+		"loan":     {ID: "loan", UserId: "u1", Kind: domain.OriginLoan, Total: 10000},
+		"checking": {ID: "checking", UserId: "u1", Total: 5000},
+	})
+	tRepo := &mockTransactionRepo{}
+	svc := NewDebtService(dRepo, oRepo, newTransactionService(tRepo, oRepo), noopTxManager{})
+
+	transaction, err := svc.RecordPayment(context.Background(), "d1", 900, time.Date(2024, 2, 15, 0, 0, 0, 0, time.UTC), "checking")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if transaction.DebtPayment.Interest != 100 || transaction.DebtPayment.Principal != 800 || transaction.Amount != 800 {
+		t.Errorf("payment = %+v, split %+v", transaction, transaction.DebtPayment)
+	}
+	if got := oRepo.origins["loan"].Total; got != 9200 {
+		t.Errorf("expected 9200 owed on the loan, got %v", got)
+	}
+	if got := oRepo.origins["checking"].Total; got != 4100 {
+		t.Errorf("expected 4100 left on checking, got %v", got)
+	}
+}
+
+func TestRecordPayment_ReconciledPeriod(t *testing.T) {
+	reconciledThrough := time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)
+	date := time.Date(2024, 2, 15, 0, 0, 0, 0, time.UTC)
+
+	for _, locked := range []string{"loan", "checking"} {
+		debt := carLoan()
+		dRepo := &mockDebtRepo{debts: map[string]*domain.Debt{"d1": &debt}}
+		oRepo := newMockOriginRepo(map[string]*domain.Origin{
+			"loan":     {ID: "loan", UserId: "u1", Kind: domain.OriginLoan, Total: 10000},
+			"checking": {ID: "checking", UserId: "u1", Total: 5000},
+		})
+		oRepo.origins[locked].ReconciledThrough = &reconciledThrough
+		tRepo := &mockTransactionRepo{}
+		svc := NewDebtService(dRepo, oRepo, newTransactionService(tRepo, oRepo), noopTxManager{})
+
+		if _, err := svc.RecordPayment(context.Background(), "d1", 900, date, "checking"); err != domain.ErrReconciledPeriod {
+			t.Errorf("%s locked: expected ErrReconciledPeriod, got %v", locked, err)
+		}
+	}
+}
+
+func TestRecordPayment_BelowInterest(t *testing.T) {
+	debt := carLoan()
+	dRepo := &mockDebtRepo{debts: map[string]*domain.Debt{"d1": &debt}}
+	oRepo := newMockOriginRepo(map[string]*domain.Origin{})
+	tRepo := &mockTransactionRepo{}
+	svc := NewDebtService(dRepo, oRepo, newTransactionService(tRepo, oRepo), noopTxManager{})
+
+	if _, err := svc.RecordPayment(context.Background(), "d1", 50, time.Now(), ""); err != domain.ErrPaymentBelowInterest {
+		t.Errorf("expected ErrPaymentBelowInterest, got %v", err)
+	}
+}
+
+func TestDebtStatusAndSimulation(t *testing.T) {
+	debt := carLoan()
+	payments := []domain.Transaction{
+		{DebtPayment: &domain.DebtPayment{DebtId: "d1", Principal: 788.49, Interest: 100}},
+		{DebtPayment: &domain.DebtPayment{DebtId: "d1", Principal: 796.37, Interest: 92.12}},
+	}
+
+	status := debtStatus(debt, payments)
+
+	if status.RemainingBalance != 8415.14 || status.InterestPaid != 192.12 || status.PaymentsMade != 2 {
+		t.Errorf("status = %+v", status)
+	}
+	if status.NextPaymentDate.Format("2006-01-02") != "2024-04-15" || status.PayoffDate.Format("2006-01-02") != "2025-01-15" {
+		t.Errorf("next %v, payoff %v", status.NextPaymentDate, status.PayoffDate)
+	}
+
+	simulation := simulateExtraPayments(status, 500, 1000)
+
+	if simulation.MonthsSaved <= 0 || simulation.InterestSaved <= 0 {
+		t.Errorf("expected extra payments to save time and interest, got %+v", simulation)
+	}
+	if !simulation.PayoffDate.Before(*simulation.BaselinePayoffDate) {
+		t.Errorf("expected an earlier payoff, got %v vs %v", simulation.PayoffDate, simulation.BaselinePayoffDate)
+	}
+}
@@ -0,0 +1,450 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"personal-finance/core/domain"
+	"personal-finance/core/port"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxForecastMonths bounds the horizon of a forecast request.
+const maxForecastMonths = 24
+
+// forecastLookbackMonths is how much history feeds pattern detection and the
+// average discretionary spending.
+const forecastLookbackMonths = 6
+
+// minPatternOccurrences is how many times a payment must repeat to be taken
+// as recurring.
+const minPatternOccurrences = 3
+
+type ForecastService struct {
+	itemRepo        port.ExpectedItemRepository
+	originRepo      port.OriginRepository
+	transactionRepo port.TransactionRepository
+}
+
+func NewForecastService(
+	itemRepo port.ExpectedItemRepository,
+	originRepo port.OriginRepository,
+	transactionRepo port.TransactionRepository) *ForecastService {
+
+	return &ForecastService{
+		itemRepo,
+		originRepo,
+		transactionRepo,
+	}
+}
+
+func (fs *ForecastService) GetExpectedItemsByUserId(ctx context.Context, userId string) ([]domain.ExpectedItem, error) {
+
+	items, err := fs.itemRepo.GetExpectedItemsByUserId(ctx, userId)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return items, nil
+}
+
+func (fs *ForecastService) GetExpectedItemById(ctx context.Context, id string) (*domain.ExpectedItem, error) {
+
+	item, err := fs.itemRepo.GetExpectedItemById(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrDataNotFound) {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, domain.ErrInternal
+	}
+
+	return item, nil
+}
+
+func (fs *ForecastService) CreateExpectedItem(ctx context.Context, item *domain.ExpectedItem) (*domain.ExpectedItem, error) {
+
+	item.StartDate = domain.SnapshotDay(item.StartDate)
+
+	item, err := fs.itemRepo.CreateExpectedItem(ctx, item)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return item, nil
+}
+
+func (fs *ForecastService) UpdateExpectedItem(ctx context.Context, id string, item *domain.ExpectedItem) (*domain.ExpectedItem, error) {
+
+	item.StartDate = domain.SnapshotDay(item.StartDate)
+
+	_, err := fs.itemRepo.UpdateExpectedItem(ctx, id, item)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	return item, nil
+}
+
+func (fs *ForecastService) DeleteExpectedItem(ctx context.Context, id string) error {
+
+	err := fs.itemRepo.DeleteExpectedItem(ctx, id)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return err
+		}
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+// GetForecast projects the balance of each of the user's origins day by day
+// over the next months. Every day applies the declared expected items, the
+// recurring payments detected in the last months and the origin's average
+// daily discretionary spending.
+func (fs *ForecastService) GetForecast(ctx context.Context, userId string, months int, threshold float64, now time.Time) (*domain.Forecast, error) {
+
+	if months < 1 || months > maxForecastMonths {
+		return nil, domain.ErrInvalidPeriod
+	}
+
+	today := domain.SnapshotDay(now)
+	lookbackStart := today.AddDate(0, -forecastLookbackMonths, 0)
+	end := today.AddDate(0, months, 0)
+
+	origins, err := fs.originRepo.GetOriginsByUserId(ctx, userId, false)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	items, err := fs.itemRepo.GetExpectedItemsByUserId(ctx, userId)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	history, err := fs.getHistory(ctx, userId, lookbackStart, now)
+	if err != nil {
+		return nil, err
+	}
+
+	patterns, patternKeys := detectRecurringPatterns(history, today)
+	patterns = withoutDeclaredPatterns(patterns, items)
+	discretionary := dailyDiscretionary(history, patternKeys, int(today.Sub(lookbackStart).Hours()/24))
+
+	forecast := domain.Forecast{
+		From:      today.Format(snapshotDateFormat),
+		To:        end.Format(snapshotDateFormat),
+		Threshold: threshold,
+		Origins:   []domain.OriginForecast{},
+		Patterns:  patterns,
+	}
+
+	for _, origin := range origins {
+		changes := scheduledChanges(origin.ID, items, patterns, today.AddDate(0, 0, 1), end)
+		forecast.Origins = append(forecast.Origins, projectOrigin(origin, changes, discretionary[origin.ID], threshold, today, end))
+	}
+
+	return &forecast, nil
+}
+
+func (fs *ForecastService) getHistory(ctx context.Context, userId string, from, to time.Time) ([]domain.Transaction, error) {
+
+	var transactionList []domain.Transaction
+	var limit uint64 = 200
+	var cursor string
+
+	filter := domain.TransactionFilter{
+		UserId: userId,
+		From:   &from,
+		To:     &to,
+	}
+
+	for {
+		transactions, _, nextCursor, err := fs.transactionRepo.GetTransactionsByCursor(ctx, filter, cursor, limit)
+		if err != nil {
+			return nil, domain.ErrInternal
+		}
+
+		transactionList = append(transactionList, transactions...)
+
+		if nextCursor == "" {
+			break
+		}
+
+		cursor = nextCursor
+	}
+
+	return transactionList, nil
+}
+
+func patternKey(originId, transactionType, payee string) string {
+	return originId + "\x00" + transactionType + "\x00" + strings.ToLower(strings.TrimSpace(payee))
+}
+
+// detectRecurringPatterns groups the transactions by origin, type and payee
+// and keeps the groups that repeat weekly, biweekly or monthly with a stable
+// amount. Patterns that missed more than one expected occurrence before today
+// are dropped from the result but their keys are still returned, so their
+// past payments are not counted as discretionary spending.
+func detectRecurringPatterns(transactions []domain.Transaction, today time.Time) ([]domain.RecurringPattern, map[string]bool) {
+
+	groups := make(map[string][]domain.Transaction)
+	var order []string
+
+	for _, transaction := range transactions {
+
+		if transaction.OriginId == nil || *transaction.OriginId == "" || strings.TrimSpace(transaction.PersonOrBusiness) == "" {
+			continue
+		}
+
+		key := patternKey(*transaction.OriginId, transaction.Type, transaction.PersonOrBusiness)
+		if _, exists := groups[key]; !exists {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], transaction)
+	}
+
+	patterns := []domain.RecurringPattern{}
+	keys := make(map[string]bool)
+
+	for _, key := range order {
+
+		group := groups[key]
+		if len(group) < minPatternOccurrences {
+			continue
+		}
+
+		sort.Slice(group, func(i, j int) bool {
+			return group[i].CreatedAt.Before(group[j].CreatedAt)
+		})
+
+		frequency, period := classifyIntervals(group)
+		if frequency == "" || !stableAmounts(group) {
+			continue
+		}
+
+		keys[key] = true
+
+		last := group[len(group)-1]
+		lastDay := domain.SnapshotDay(last.CreatedAt)
+		if today.Sub(lastDay).Hours()/24 > float64(2*period) {
+			continue
+		}
+
+		var total float64
+		for _, transaction := range group {
+			total += transaction.Amount
+		}
+
+		patterns = append(patterns, domain.RecurringPattern{
+			OriginId:         *last.OriginId,
+			Type:             last.Type,
+			Subject:          last.Subject,
+			PersonOrBusiness: last.PersonOrBusiness,
+			Amount:           round2(total / float64(len(group))),
+			Frequency:        frequency,
+			Occurrences:      len(group),
+			LastDate:         lastDay,
+		})
+	}
+
+	return patterns, keys
+}
+
+// classifyIntervals maps the median gap between the sorted transactions to a
+// frequency and its nominal length in days. Every gap must stay within half a
+// period of it, otherwise the group is not regular enough to project.
+func classifyIntervals(group []domain.Transaction) (string, int) {
+
+	gaps := make([]float64, 0, len(group)-1)
+	for i := 1; i < len(group); i++ {
+		gaps = append(gaps, domain.SnapshotDay(group[i].CreatedAt).Sub(domain.SnapshotDay(group[i-1].CreatedAt)).Hours()/24)
+	}
+
+	sorted := append([]float64(nil), gaps...)
+	sort.Float64s(sorted)
+	median := sorted[len(sorted)/2]
+
+	var frequency string
+	var period int
+
+	switch {
+	case median >= 6 && median <= 8:
+		frequency, period = domain.FrequencyWeekly, 7
+	case median >= 13 && median <= 16:
+		frequency, period = domain.FrequencyBiweekly, 14
+	case median >= 27 && median <= 33:
+		frequency, period = domain.FrequencyMonthly, 30
+	default:
+		return "", 0
+	}
+
+	for _, gap := range gaps {
+		if math.Abs(gap-float64(period)) > float64(period)/2 {
+			return "", 0
+		}
+	}
+
+	return frequency, period
+}
+
+// stableAmounts reports whether every amount is within 25% of the median one.
+func stableAmounts(group []domain.Transaction) bool {
+
+	amounts := make([]float64, 0, len(group))
+	for _, transaction := range group {
+		amounts = append(amounts, transaction.Amount)
+	}
+	sort.Float64s(amounts)
+	median := amounts[len(amounts)/2]
+
+	for _, amount := range amounts {
+		if math.Abs(amount-median) > median*0.25 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// withoutDeclaredPatterns drops the patterns the user already declared as an
+// expected item for the same origin, type and payee.
+func withoutDeclaredPatterns(patterns []domain.RecurringPattern, items []domain.ExpectedItem) []domain.RecurringPattern {
+
+	declared := make(map[string]bool)
+	for _, item := range items {
+		if item.PersonOrBusiness != "" {
+			declared[patternKey(item.OriginId, item.Type, item.PersonOrBusiness)] = true
+		}
+	}
+
+	filtered := []domain.RecurringPattern{}
+	for _, pattern := range patterns {
+		if !declared[patternKey(pattern.OriginId, pattern.Type, pattern.PersonOrBusiness)] {
+			filtered = append(filtered, pattern)
+		}
+	}
+
+	return filtered
+}
+
+// dailyDiscretionary averages, per origin, the daily outputs that are not
+// part of a recurring pattern.
+func dailyDiscretionary(transactions []domain.Transaction, patternKeys map[string]bool, days int) map[string]float64 {
+
+	averages := make(map[string]float64)
+	if days < 1 {
+		return averages
+	}
+
+	for _, transaction := range transactions {
+
+		if transaction.Type != "Output" || transaction.OriginId == nil || *transaction.OriginId == "" {
+			continue
+		}
+		if patternKeys[patternKey(*transaction.OriginId, transaction.Type, transaction.PersonOrBusiness)] {
+			continue
+		}
+
+		averages[*transaction.OriginId] += transaction.Amount
+	}
+
+	for originId, total := range averages {
+		averages[originId] = round2(total / float64(days))
+	}
+
+	return averages
+}
+
+// scheduledChanges returns the balance change the expected items and the
+// recurring patterns of the origin bring to each day in [from, to].
+func scheduledChanges(originId string, items []domain.ExpectedItem, patterns []domain.RecurringPattern, from, to time.Time) map[time.Time]float64 {
+
+	changes := make(map[time.Time]float64)
+
+	for _, item := range items {
+		if item.OriginId != originId {
+			continue
+		}
+		for _, day := range item.Occurrences(from, to) {
+			changes[day] += signedAmount(item.Type, item.Amount)
+		}
+	}
+
+	for _, pattern := range patterns {
+		if pattern.OriginId != originId {
+			continue
+		}
+		for n := 1; ; n++ {
+			day := domain.NextOccurrence(pattern.LastDate, pattern.Frequency, n)
+			if day.After(to) {
+				break
+			}
+			if !day.Before(from) {
+				changes[day] += signedAmount(pattern.Type, pattern.Amount)
+			}
+		}
+	}
+
+	return changes
+}
+
+func signedAmount(transactionType string, amount float64) float64 {
+
+	if transactionType == "Income" {
+		return amount
+	}
+
+	return -amount
+}
+
+// projectOrigin walks the origin's balance from today through end. Liability
+// balances are projected negative, as what is owed. A day is
+// flagged as an alert when the balance falls below the threshold after being
+// at or above it the day before.
+func projectOrigin(origin domain.Origin, changes map[time.Time]float64, discretionary, threshold float64, today, end time.Time) domain.OriginForecast {
+
+	forecast := domain.OriginForecast{
+		OriginId:           origin.ID,
+		OriginName:         origin.Name,
+		StartBalance:       origin.NetWorth(),
+		LowestBalance:      origin.NetWorth(),
+		LowestDate:         today.Format(snapshotDateFormat),
+		DailyDiscretionary: discretionary,
+		Alerts:             []string{},
+	}
+
+	balance := origin.NetWorth()
+	below := balance < threshold
+
+	for day := today.AddDate(0, 0, 1); !day.After(end); day = day.AddDate(0, 0, 1) {
+
+		balance = round2(balance + changes[day] - discretionary)
+		date := day.Format(snapshotDateFormat)
+
+		if balance < forecast.LowestBalance {
+			forecast.LowestBalance = balance
+			forecast.LowestDate = date
+		}
+
+		if balance < threshold && !below {
+			forecast.Alerts = append(forecast.Alerts, date)
+		}
+		below = balance < threshold
+
+		forecast.Points = append(forecast.Points, domain.ForecastPoint{
+			Date:           date,
+			Balance:        balance,
+			BelowThreshold: below,
+		})
+	}
+
+	forecast.EndBalance = balance
+
+	return forecast
+}
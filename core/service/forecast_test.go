@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"personal-finance/core/domain"
+)
+
+func recurring(originId, payee, transactionType string, amount float64, dates ...time.Time) []domain.Transaction {
+	var transactions []domain.Transaction
+	for _, date := range dates {
+		transactions = append(transactions, domain.Transaction{
+			OriginId:         strPtr(originId),
+			Type:             transactionType,
+			Subject:          "Payment",
+			PersonOrBusiness: payee,
+			Amount:           amount,
+			CreatedAt:        date,
+		})
+	}
+	return transactions
+}
+
+func TestDetectRecurringPatterns(t *testing.T) {
+	today := time.Date(2024, 4, 20, 0, 0, 0, 0, time.UTC)
+
+	var history []domain.Transaction
+	history = append(history, recurring("o1", "Employer", "Income", 2000,
+		time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC),
+		time.Date(2024, 2, 15, 9, 0, 0, 0, time.UTC),
+		time.Date(2024, 3, 15, 9, 0, 0, 0, time.UTC),
+		time.Date(2024, 4, 15, 9, 0, 0, 0, time.UTC))...)
+	// irregular gaps
+	history = append(history, recurring("o1", "Store", "Output", 40,
+		time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 4, 3, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 4, 19, 0, 0, 0, 0, time.UTC))...)
+	// stopped two months ago
+	history = append(history, recurring("o1", "Gym", "Output", 30,
+		time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC))...)
+
+	patterns, keys := detectRecurringPatterns(history, today)
+
+	if len(patterns) != 1 {
+		t.Fatalf("got %d patterns, want 1: %+v", len(patterns), patterns)
+	}
+	if patterns[0].PersonOrBusiness != "Employer" || patterns[0].Frequency != domain.FrequencyMonthly || patterns[0].Amount != 2000 {
+		t.Errorf("pattern = %+v", patterns[0])
+	}
+	if !keys[patternKey("o1", "Output", "gym")] {
+		t.Error("stale pattern key should still be reported")
+	}
+	if keys[patternKey("o1", "Output", "store")] {
+		t.Error("irregular payments should not form a pattern")
+	}
+}
+
+func TestScheduledChangesAndProjection(t *testing.T) {
+	today := time.Date(2024, 4, 20, 0, 0, 0, 0, time.UTC)
+	end := today.AddDate(0, 0, 10)
+
+	items := []domain.ExpectedItem{
+		{OriginId: "o1", Type: "Output", Amount: 500, Frequency: domain.FrequencyOnce, StartDate: today.AddDate(0, 0, 3)},
+		{OriginId: "o2", Type: "Output", Amount: 999, Frequency: domain.FrequencyOnce, StartDate: today.AddDate(0, 0, 3)},
+	}
+	patterns := []domain.RecurringPattern{
+		{OriginId: "o1", Type: "Income", Amount: 1000, Frequency: domain.FrequencyWeekly, LastDate: today.AddDate(0, 0, -1)},
+	}
+
+	changes := scheduledChanges("o1", items, patterns, today.AddDate(0, 0, 1), end)
+	forecast := projectOrigin(domain.Origin{ID: "o1", Total: 300}, changes, 10, 0, today, end)
+
+	if len(forecast.Points) != 10 {
+		t.Fatalf("got %d points, want 10", len(forecast.Points))
+	}
+	// day 3: 300 - 30 - 500 = -230
+	if forecast.Points[2].Balance != -230 || !forecast.Points[2].BelowThreshold {
+		t.Errorf("day 3 = %+v", forecast.Points[2])
+	}
+	// day 6: weekly income lands, balance back above the threshold
+	if forecast.Points[5].Balance != 740 || forecast.Points[5].BelowThreshold {
+		t.Errorf("day 6 = %+v", forecast.Points[5])
+	}
+	if len(forecast.Alerts) != 1 || forecast.Alerts[0] != "2024-04-23" {
+		t.Errorf("alerts = %v", forecast.Alerts)
+	}
+	if forecast.LowestBalance != -250 || forecast.LowestDate != "2024-04-25" {
+		t.Errorf("lowest = %v on %s", forecast.LowestBalance, forecast.LowestDate)
+	}
+}
+
+func TestWithoutDeclaredPatterns(t *testing.T) {
+	patterns := []domain.RecurringPattern{
+		{OriginId: "o1", Type: "Income", PersonOrBusiness: "Employer"},
+		{OriginId: "o1", Type: "Output", PersonOrBusiness: "Landlord"},
+	}
+	items := []domain.ExpectedItem{
+		{OriginId: "o1", Type: "Income", PersonOrBusiness: "employer "},
+	}
+
+	filtered := withoutDeclaredPatterns(patterns, items)
+
+	if len(filtered) != 1 || filtered[0].PersonOrBusiness != "Landlord" {
+		t.Errorf("filtered = %+v", filtered)
+	}
+}
+
+func TestExpectedItemOccurrences_MonthlyClamped(t *testing.T) {
+	item := domain.ExpectedItem{
+		Frequency: domain.FrequencyMonthly,
+		StartDate: time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+	}
+
+	days := item.Occurrences(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 4, 30, 0, 0, 0, 0, time.UTC))
+
+	want := []string{"2024-02-29", "2024-03-31", "2024-04-30"}
+	if len(days) != len(want) {
+		t.Fatalf("got %v", days)
+	}
+	for i, day := range days {
+		if day.Format("2006-01-02") != want[i] {
+			t.Errorf("occurrence %d = %s, want %s", i, day.Format("2006-01-02"), want[i])
+		}
+	}
+}
+
+func TestGetForecast_InvalidMonths(t *testing.T) {
+	svc := NewForecastService(nil, newMockOriginRepo(nil), &mockTransactionRepo{})
+
+	if _, err := svc.GetForecast(context.Background(), "u1", maxForecastMonths+1, 0, time.Now()); err != domain.ErrInvalidPeriod {
+		t.Errorf("err = %v, want ErrInvalidPeriod", err)
+	}
+}
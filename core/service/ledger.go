@@ -0,0 +1,426 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"personal-finance/core/domain"
+	"personal-finance/core/port"
+	"strings"
+	"time"
+)
+
+// migrationBatchSize bounds the entries inserted at once by a migration.
+const migrationBatchSize = 500
+
+type LedgerService struct {
+	repo            port.LedgerRepository
+	originRepo      port.OriginRepository
+	transactionRepo port.TransactionRepository
+	txManager       port.TransactionManager
+}
+
+func NewLedgerService(repo port.LedgerRepository, originRepo port.OriginRepository, transactionRepo port.TransactionRepository, txManager port.TransactionManager) *LedgerService {
+
+	return &LedgerService{
+		repo,
+		originRepo,
+		transactionRepo,
+		txManager,
+	}
+}
+
+// PostTransaction appends the journal entry of a transaction.
+func (ls *LedgerService) PostTransaction(ctx context.Context, transaction *domain.Transaction) error {
+
+	entry, ok := transactionEntry(*transaction, time.Now())
+	if !ok {
+		return nil
+	}
+
+	return ls.append(ctx, entry)
+}
+
+// ReverseTransaction undoes the transaction's standing entry with a reversal.
+// Transactions that were never journaled, or already reversed, are left as
+// they are.
+func (ls *LedgerService) ReverseTransaction(ctx context.Context, transaction *domain.Transaction) error {
+
+	last, err := ls.repo.GetLastTransactionEntry(ctx, transaction.ID)
+	if err != nil {
+		if errors.Is(err, domain.ErrDataNotFound) {
+			return nil
+		}
+		return domain.ErrInternal
+	}
+
+	if last.Kind == domain.EntryReversal {
+		return nil
+	}
+
+	return ls.append(ctx, domain.JournalEntry{
+		UserId:        last.UserId,
+		Kind:          domain.EntryReversal,
+		TransactionId: last.TransactionId,
+		OriginId:      last.OriginId,
+		Date:          last.Date,
+		Memo:          last.Memo,
+		Postings:      last.Reversed(),
+		CreatedAt:     time.Now(),
+	})
+}
+
+// PostOpening books amount into the origin's balance against opening equity,
+// either as its opening balance or as a correction made by hand.
+func (ls *LedgerService) PostOpening(ctx context.Context, origin *domain.Origin, amount float64, kind string) error {
+
+	entry, ok := openingEntry(*origin, amount, kind, time.Now())
+	if !ok {
+		return nil
+	}
+
+	return ls.append(ctx, entry)
+}
+
+func (ls *LedgerService) append(ctx context.Context, entries ...domain.JournalEntry) error {
+
+	for _, entry := range entries {
+		if !entry.Balanced() {
+			return domain.ErrInternal
+		}
+	}
+
+	if err := ls.repo.AppendEntries(ctx, entries); err != nil {
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+func (ls *LedgerService) GetEntries(ctx context.Context, userId string, account string, page, limit uint64) ([]domain.JournalEntry, int64, int, error) {
+
+	entries, totalDocuments, totalPages, err := ls.repo.GetEntries(ctx, userId, account, page, limit)
+	if err != nil {
+		return nil, 0, 0, domain.ErrInternal
+	}
+
+	return entries, totalDocuments, totalPages, nil
+}
+
+// GetTrialBalance derives every account balance of the user from the journal.
+func (ls *LedgerService) GetTrialBalance(ctx context.Context, userId string) (*domain.TrialBalance, error) {
+
+	totals, err := ls.repo.GetAccountTotals(ctx, userId)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	origins, err := ls.originRepo.GetOriginsByUserId(ctx, userId, true)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return trialBalance(totals, origins), nil
+}
+
+// GetOriginBalances returns the journal balance of each of the given origins
+// of the user, keyed by id. Origins without an opening entry have not been
+// journaled in full yet and are left out.
+func (ls *LedgerService) GetOriginBalances(ctx context.Context, userId string, origins []domain.Origin) (map[string]float64, error) {
+
+	journaled, err := ls.repo.GetJournaledOriginIds(ctx, userId)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	if len(journaled) == 0 {
+		return map[string]float64{}, nil
+	}
+
+	totals, err := ls.repo.GetAccountTotals(ctx, userId)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	byAccount := make(map[string]float64, len(totals))
+	for _, account := range trialBalance(totals, origins).Accounts {
+		byAccount[account.Account] = account.Balance
+	}
+
+	isJournaled := toSet(journaled)
+
+	balances := make(map[string]float64, len(origins))
+	for _, origin := range origins {
+		if isJournaled[origin.ID] {
+			balances[origin.ID] = byAccount[domain.OriginAccount(origin.ID)]
+		}
+	}
+
+	return balances, nil
+}
+
+func trialBalance(totals []domain.AccountTotals, origins []domain.Origin) *domain.TrialBalance {
+
+	byAccount := make(map[string]domain.Origin, len(origins))
+	for _, origin := range origins {
+		byAccount[domain.OriginAccount(origin.ID)] = origin
+	}
+
+	balance := domain.TrialBalance{Accounts: []domain.AccountBalance{}}
+
+	for _, total := range totals {
+
+		_, name, _ := strings.Cut(total.Account, ":")
+
+		account := domain.AccountBalance{
+			Account: total.Account,
+			Name:    name,
+			Debit:   round2(total.Debit),
+			Credit:  round2(total.Credit),
+		}
+
+		debitNormal := false
+		switch domain.AccountKind(total.Account) {
+		case domain.AccountOrigin:
+			origin, ok := byAccount[total.Account]
+			if ok {
+				account.Name = origin.Name
+			}
+			account.Liability = origin.IsLiability()
+			debitNormal = !account.Liability
+		case domain.AccountExpense:
+			debitNormal = true
+		}
+
+		if debitNormal {
+			account.Balance = round2(total.Debit - total.Credit)
+		} else {
+			account.Balance = round2(total.Credit - total.Debit)
+		}
+
+		balance.TotalDebit += total.Debit
+		balance.TotalCredit += total.Credit
+		balance.Accounts = append(balance.Accounts, account)
+	}
+
+	balance.TotalDebit = round2(balance.TotalDebit)
+	balance.TotalCredit = round2(balance.TotalCredit)
+	balance.Balanced = balance.TotalDebit == balance.TotalCredit
+
+	return &balance
+}
+
+// MigrateUser journals the user's data stored before the ledger existed: an
+// opening entry for every origin and an entry for every transaction not yet
+// in the journal. Origins without an opening balance get the one that, with
+// their transactions, adds up to their current Total. Corrections already
+// journaled for an origin, such as a total edited by hand before the
+// migration, are left out of its opening entry. The journal is read and
+// appended to in one transaction, so concurrent runs cannot journal the same
+// data twice. It can be run again safely and returns the number of entries
+// appended.
+func (ls *LedgerService) MigrateUser(ctx context.Context, userId string) (int, error) {
+
+	appended := 0
+
+	err := ls.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+
+		journaledTransactions, err := ls.repo.GetJournaledTransactionIds(txCtx, userId)
+		if err != nil {
+			return domain.ErrInternal
+		}
+
+		journaledOrigins, err := ls.repo.GetJournaledOriginIds(txCtx, userId)
+		if err != nil {
+			return domain.ErrInternal
+		}
+
+		totals, err := ls.repo.GetAccountTotals(txCtx, userId)
+		if err != nil {
+			return domain.ErrInternal
+		}
+
+		origins, err := ls.originRepo.GetOriginsByUserId(txCtx, userId, true)
+		if err != nil {
+			return domain.ErrInternal
+		}
+
+		transactions, err := ls.userTransactions(txCtx, userId)
+		if err != nil {
+			return err
+		}
+
+		ledger := make(map[string]float64)
+		for _, account := range trialBalance(totals, origins).Accounts {
+			ledger[account.Account] = account.Balance
+		}
+
+		entries := migrationEntries(origins, transactions, toSet(journaledOrigins), toSet(journaledTransactions), ledger, time.Now())
+
+		for start := 0; start < len(entries); start += migrationBatchSize {
+			end := min(start+migrationBatchSize, len(entries))
+			if err := ls.append(txCtx, entries[start:end]...); err != nil {
+				return err
+			}
+		}
+
+		appended = len(entries)
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return appended, nil
+}
+
+func (ls *LedgerService) userTransactions(ctx context.Context, userId string) ([]domain.Transaction, error) {
+
+	var transactionList []domain.Transaction
+
+	filter := domain.TransactionFilter{UserId: userId, SortAscending: true}
+	cursor := ""
+
+	for {
+		transactions, _, nextCursor, err := ls.transactionRepo.GetTransactionsByCursor(ctx, filter, cursor, 200)
+		if err != nil {
+			return nil, domain.ErrInternal
+		}
+
+		transactionList = append(transactionList, transactions...)
+
+		if nextCursor == "" {
+			break
+		}
+
+		cursor = nextCursor
+	}
+
+	return transactionList, nil
+}
+
+// migrationEntries builds the entries journaling what is missing from the
+// journal. ledger holds the current balance of every origin account; what of
+// it is not explained by journaled transactions are corrections, already part
+// of the origin's Total and opening balance, so they are taken off its
+// opening entry.
+func migrationEntries(origins []domain.Origin, transactions []domain.Transaction, journaledOrigins, journaledTransactions map[string]bool, ledger map[string]float64, now time.Time) []domain.JournalEntry {
+
+	var entries []domain.JournalEntry
+
+	for _, origin := range origins {
+
+		if journaledOrigins[origin.ID] {
+			continue
+		}
+
+		opening := origin
+		if origin.OpeningBalance != nil {
+			opening.Total = *origin.OpeningBalance
+		}
+
+		journaled := origin
+		journaled.Total = 0
+
+		for _, transaction := range transactions {
+			if transaction.OriginId == nil || *transaction.OriginId != origin.ID {
+				continue
+			}
+			if origin.OpeningBalance == nil {
+				opening.ApplyTransaction(reverseType(transaction.Type), transaction.Amount)
+			}
+			if journaledTransactions[transaction.ID] {
+				journaled.ApplyTransaction(transaction.Type, transaction.Amount)
+			}
+		}
+
+		opening.Total -= ledger[domain.OriginAccount(origin.ID)] - journaled.Total
+
+		if entry, ok := openingEntry(origin, round2(opening.Total), domain.EntryOpening, now); ok {
+			entries = append(entries, entry)
+		}
+	}
+
+	for _, transaction := range transactions {
+
+		if journaledTransactions[transaction.ID] {
+			continue
+		}
+
+		if entry, ok := transactionEntry(transaction, now); ok {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries
+}
+
+func transactionEntry(transaction domain.Transaction, now time.Time) (domain.JournalEntry, bool) {
+
+	postings := domain.TransactionPostings(transaction)
+	if postings == nil {
+		return domain.JournalEntry{}, false
+	}
+
+	return domain.JournalEntry{
+		UserId:        transaction.UserId,
+		Kind:          domain.EntryTransaction,
+		TransactionId: transaction.ID,
+		OriginId:      *transaction.OriginId,
+		Date:          transaction.CreatedAt,
+		Memo:          transaction.Description,
+		Postings:      postings,
+		CreatedAt:     now,
+	}, true
+}
+
+// openingEntry journals amount against opening equity. An origin's opening
+// entry is posted even for a zero amount, since it marks the origin as
+// journaled.
+func openingEntry(origin domain.Origin, amount float64, kind string, now time.Time) (domain.JournalEntry, bool) {
+
+	postings := domain.OpeningPostings(origin, amount)
+	if postings == nil {
+		if kind != domain.EntryOpening {
+			return domain.JournalEntry{}, false
+		}
+		postings = []domain.Posting{
+			{Account: domain.OriginAccount(origin.ID)},
+			{Account: domain.OpeningEquityAccount},
+		}
+	}
+
+	date := now
+	if kind == domain.EntryOpening && !origin.CreatedAt.IsZero() {
+		date = origin.CreatedAt
+	}
+
+	return domain.JournalEntry{
+		UserId:    origin.UserId,
+		Kind:      kind,
+		OriginId:  origin.ID,
+		Date:      date,
+		Memo:      origin.Name,
+		Postings:  postings,
+		CreatedAt: now,
+	}, true
+}
+
+func reverseType(transactionType string) string {
+
+	if transactionType == "Output" {
+		return "Income"
+	}
+
+	return "Output"
+}
+
+func toSet(values []string) map[string]bool {
+
+	set := make(map[string]bool, len(values))
+	for _, value := range values {
+		set[value] = true
+	}
+
+	return set
+}
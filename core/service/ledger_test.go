@@ -0,0 +1,320 @@
+package service
+
+import (
+	"context"
+	"mime/multipart"
+	"testing"
+	"time"
+
+	"personal-finance/core/domain"
+)
+
+type mockLedgerRepo struct {
+	entries []domain.JournalEntry
+}
+
+func (m *mockLedgerRepo) AppendEntries(ctx context.Context, entries []domain.JournalEntry) error {
+	m.entries = append(m.entries, entries...)
+	return nil
+}
+
+func (m *mockLedgerRepo) GetEntries(ctx context.Context, userId string, account string, page, limit uint64) ([]domain.JournalEntry, int64, int, error) {
+	return m.entries, int64(len(m.entries)), 1, nil
+}
+
+func (m *mockLedgerRepo) GetLastTransactionEntry(ctx context.Context, transactionId string) (*domain.JournalEntry, error) {
+	for i := len(m.entries) - 1; i >= 0; i-- {
+		if m.entries[i].TransactionId == transactionId {
+			entry := m.entries[i]
+			return &entry, nil
+		}
+	}
+	return nil, domain.ErrDataNotFound
+}
+
+func (m *mockLedgerRepo) GetAccountTotals(ctx context.Context, userId string) ([]domain.AccountTotals, error) {
+	byAccount := map[string]*domain.AccountTotals{}
+	var totals []domain.AccountTotals
+	for _, entry := range m.entries {
+		for _, posting := range entry.Postings {
+			if byAccount[posting.Account] == nil {
+				byAccount[posting.Account] = &domain.AccountTotals{Account: posting.Account}
+			}
+			byAccount[posting.Account].Debit += posting.Debit
+			byAccount[posting.Account].Credit += posting.Credit
+		}
+	}
+	for _, total := range byAccount {
+		totals = append(totals, *total)
+	}
+	return totals, nil
+}
+
+func (m *mockLedgerRepo) GetJournaledTransactionIds(ctx context.Context, userId string) ([]string, error) {
+	var ids []string
+	for _, entry := range m.entries {
+		if entry.TransactionId != "" {
+			ids = append(ids, entry.TransactionId)
+		}
+	}
+	return ids, nil
+}
+
+func (m *mockLedgerRepo) GetJournaledOriginIds(ctx context.Context, userId string) ([]string, error) {
+	var ids []string
+	for _, entry := range m.entries {
+		if entry.Kind == domain.EntryOpening {
+			ids = append(ids, entry.OriginId)
+		}
+	}
+	return ids, nil
+}
+
+type mockAttachmentService struct{}
+
+func (mockAttachmentService) GetAttachmentsByTransactionId(ctx context.Context, transactionId string) ([]domain.Attachment, error) {
+	return nil, nil
+}
+
+func (mockAttachmentService) UploadAttachment(ctx context.Context, transactionId string, file multipart.File, fileName string, contentType string) (*domain.Attachment, error) {
+	return nil, nil
+}
+
+func (mockAttachmentService) DeleteAttachment(ctx context.Context, transactionId string, id string) error {
+	return nil
+}
+
+func (mockAttachmentService) DeleteAttachmentsByTransactionId(ctx context.Context, transactionId string) error {
+	return nil
+}
+
+func accountBalance(t *testing.T, ls *LedgerService, account string) float64 {
+	t.Helper()
+
+	balance, err := ls.GetTrialBalance(context.Background(), "u1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !balance.Balanced {
+		t.Fatalf("trial balance does not balance: %+v", balance)
+	}
+
+	for _, a := range balance.Accounts {
+		if a.Account == account {
+			return a.Balance
+		}
+	}
+	return 0
+}
+
+func TestLedger_TransactionLifecycle(t *testing.T) {
+	oRepo := newMockOriginRepo(map[string]*domain.Origin{
+		"checking": {ID: "checking", UserId: "u1", Name: "Checking", Total: 100},
+	})
+	lRepo := &mockLedgerRepo{}
+	ledger := NewLedgerService(lRepo, oRepo, &mockTransactionRepo{}, noopTxManager{})
+	ctx := context.Background()
+
+	if err := ledger.PostOpening(ctx, oRepo.origins["checking"], 100, domain.EntryOpening); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var stored domain.Transaction
+	tRepo := &mockTransactionRepo{
+		getByIdFunc: func(ctx context.Context, id string) (*domain.Transaction, error) {
+			transaction := stored
+			return &transaction, nil
+		},
+		updateFunc: func(ctx context.Context, id string, tx *domain.Transaction) (*domain.Transaction, error) {
+			return tx, nil
+		},
+	}
+	ts := NewTransactionService(tRepo, oRepo, noopTxManager{}, &mockAttachmentService{}, NewSnapshotService(newMockSnapshotRepo(), oRepo), ledger, NewAuditService(&mockAuditRepo{}))
+
+	stored = domain.Transaction{ID: "t1", UserId: "u1", OriginId: strPtr("checking"), Type: "Income", Subject: "Salary", Amount: 50}
+	if _, err := ts.CreateTransaction(ctx, &stored); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := accountBalance(t, ledger, "origin:checking"); got != 150 {
+		t.Errorf("expected 150 after the income, got %v", got)
+	}
+
+	updated := stored
+	updated.Amount = 80
+	if _, err := ts.UpdateTransaction(ctx, "t1", &updated); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stored = updated
+
+	if got := accountBalance(t, ledger, "origin:checking"); got != 180 {
+		t.Errorf("expected 180 after the update, got %v", got)
+	}
+	if got := accountBalance(t, ledger, "income:Salary"); got != 80 {
+		t.Errorf("expected 80 of salary income, got %v", got)
+	}
+
+	if err := ts.DeleteTransaction(ctx, "t1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := accountBalance(t, ledger, "origin:checking"); got != 100 || got != oRepo.origins["checking"].Total {
+		t.Errorf("expected the ledger and the origin back at 100, got %v and %v", got, oRepo.origins["checking"].Total)
+	}
+
+	kinds := []string{}
+	for _, entry := range lRepo.entries {
+		if !entry.Balanced() {
+			t.Errorf("unbalanced entry %+v", entry)
+		}
+		kinds = append(kinds, entry.Kind)
+	}
+	want := []string{"opening", "transaction", "reversal", "transaction", "reversal"}
+	if len(kinds) != len(want) {
+		t.Fatalf("expected entries %v, got %v", want, kinds)
+	}
+	for i := range want {
+		if kinds[i] != want[i] {
+			t.Errorf("expected entries %v, got %v", want, kinds)
+			break
+		}
+	}
+}
+
+func TestLedger_LiabilityBalance(t *testing.T) {
+	oRepo := newMockOriginRepo(map[string]*domain.Origin{
+		"card": {ID: "card", UserId: "u1", Kind: domain.OriginCreditCard},
+	})
+	lRepo := &mockLedgerRepo{}
+	ledger := NewLedgerService(lRepo, oRepo, &mockTransactionRepo{}, noopTxManager{})
+	ts := NewTransactionService(&mockTransactionRepo{}, oRepo, noopTxManager{}, nil, NewSnapshotService(newMockSnapshotRepo(), oRepo), ledger, NewAuditService(&mockAuditRepo{}))
+	ctx := context.Background()
+
+	charge := &domain.Transaction{ID: "t1", UserId: "u1", OriginId: strPtr("card"), Type: "Output", OutputCategory: "Food", Amount: 200}
+	payment := &domain.Transaction{ID: "t2", UserId: "u1", OriginId: strPtr("card"), Type: "Income", Subject: "Payment", Amount: 50}
+
+	for _, transaction := range []*domain.Transaction{charge, payment} {
+		if _, err := ts.CreateTransaction(ctx, transaction); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if got := accountBalance(t, ledger, "origin:card"); got != 150 || got != oRepo.origins["card"].Total {
+		t.Errorf("expected 150 owed in the ledger and on the origin, got %v and %v", got, oRepo.origins["card"].Total)
+	}
+	if got := accountBalance(t, ledger, "expense:Food"); got != 200 {
+		t.Errorf("expected 200 of food expenses, got %v", got)
+	}
+}
+
+func TestMigrationEntries(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	origins := []domain.Origin{
+		{ID: "legacy", UserId: "u1", Total: 150},
+		{ID: "card", UserId: "u1", Kind: domain.OriginCreditCard, Total: 0, OpeningBalance: floatPtr(0)},
+		{ID: "done", UserId: "u1", Total: 10},
+	}
+	transactions := []domain.Transaction{
+		{ID: "t1", UserId: "u1", OriginId: strPtr("legacy"), Type: "Income", Subject: "Salary", Amount: 100},
+		{ID: "t2", UserId: "u1", OriginId: strPtr("legacy"), Type: "Output", OutputCategory: "Food", Amount: 50},
+		{ID: "t3", UserId: "u1", Type: "Output", Amount: 5},
+	}
+
+	entries := migrationEntries(origins, transactions, map[string]bool{"done": true}, map[string]bool{"t2": true}, map[string]float64{"origin:legacy": -50}, now)
+
+	if len(entries) != 3 {
+		t.Fatalf("expected the legacy and card openings and t1, got %+v", entries)
+	}
+
+	opening := entries[0]
+	if opening.Kind != domain.EntryOpening || opening.OriginId != "legacy" || opening.Postings[0].Debit != 100 {
+		t.Errorf("expected a 100 opening derived from the legacy total, got %+v", opening)
+	}
+	// A zero opening is still journaled, marking the card as migrated.
+	if entries[1].Kind != domain.EntryOpening || entries[1].OriginId != "card" || !entries[1].Balanced() {
+		t.Errorf("expected a zero opening for card, got %+v", entries[1])
+	}
+	if entries[2].TransactionId != "t1" || !entries[2].Balanced() {
+		t.Errorf("expected t1 journaled, got %+v", entries[2])
+	}
+}
+
+// commitFailingTxManager runs the work and then fails to commit it, as when a
+// concurrent migration of the same user conflicts.
+type commitFailingTxManager struct{}
+
+func (commitFailingTxManager) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if err := fn(ctx); err != nil {
+		return err
+	}
+	return domain.ErrInternal
+}
+
+func TestMigrateUser_ReportsNothingWhenTheTransactionFails(t *testing.T) {
+	oRepo := newMockOriginRepo(map[string]*domain.Origin{
+		"legacy": {ID: "legacy", UserId: "u1", Total: 150},
+	})
+	ledger := NewLedgerService(&mockLedgerRepo{}, oRepo, &mockTransactionRepo{}, commitFailingTxManager{})
+
+	appended, err := ledger.MigrateUser(context.Background(), "u1")
+	if err != domain.ErrInternal || appended != 0 {
+		t.Errorf("expected nothing appended and ErrInternal, got %d and %v", appended, err)
+	}
+}
+
+func TestMigrateUser_AfterTotalEditedByHand(t *testing.T) {
+	// legacy predates the ledger: no opening balance, 150 of which 100 came
+	// from a salary.
+	oRepo := newMockOriginRepo(map[string]*domain.Origin{
+		"legacy": {ID: "legacy", UserId: "u1", Total: 150},
+	})
+	tRepo := &mockTransactionRepo{byOrigin: []domain.Transaction{
+		{ID: "t1", UserId: "u1", OriginId: strPtr("legacy"), Type: "Income", Subject: "Salary", Amount: 100},
+	}}
+	lRepo := &mockLedgerRepo{}
+	ledger := NewLedgerService(lRepo, oRepo, tRepo, noopTxManager{})
+	os := NewOriginService(oRepo, tRepo, &mockDebtRepo{}, NewSnapshotService(newMockSnapshotRepo(), oRepo), ledger, NewAuditService(&mockAuditRepo{}), noopTxManager{})
+
+	if _, err := os.UpdateOrigin(context.Background(), "legacy", &domain.Origin{UserId: "u1", Name: "Legacy", Total: 180}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := ledger.MigrateUser(context.Background(), "u1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := accountBalance(t, ledger, domain.OriginAccount("legacy")); got != 180 {
+		t.Errorf("expected the journal to add up to the edited 180, got %v", got)
+	}
+}
+
+func TestGetOriginsByUserId_ReadsJournaledBalances(t *testing.T) {
+	oRepo := newMockOriginRepo(map[string]*domain.Origin{
+		"journaled": {ID: "journaled", UserId: "u1", Total: 999, OpeningBalance: floatPtr(0)},
+		"legacy":    {ID: "legacy", UserId: "u1", Total: 70},
+	})
+	lRepo := &mockLedgerRepo{}
+	ledger := NewLedgerService(lRepo, oRepo, &mockTransactionRepo{}, noopTxManager{})
+	ledger.PostOpening(context.Background(), oRepo.origins["journaled"], 250, domain.EntryOpening)
+	os := NewOriginService(oRepo, &mockTransactionRepo{}, &mockDebtRepo{}, NewSnapshotService(newMockSnapshotRepo(), oRepo), ledger, NewAuditService(&mockAuditRepo{}), noopTxManager{})
+
+	origins, err := os.GetOriginsByUserId(context.Background(), "u1", true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, origin := range origins {
+		switch origin.ID {
+		case "journaled":
+			if origin.Total != 250 {
+				t.Errorf("expected the journal balance 250 over the cached 999, got %v", origin.Total)
+			}
+		case "legacy":
+			if origin.Total != 70 {
+				t.Errorf("expected the stored total of an origin not yet migrated, got %v", origin.Total)
+			}
+		}
+	}
+
+	origin, err := os.GetOriginById(context.Background(), "journaled")
+	if err != nil || origin.Total != 250 {
+		t.Errorf("expected the journal balance by id, got %+v and %v", origin, err)
+	}
+}
@@ -5,26 +5,52 @@ import (
 	"errors"
 	"personal-finance/core/domain"
 	"personal-finance/core/port"
+	"time"
 )
 
 type OriginService struct {
-	repo port.OriginRepository
+	repo            port.OriginRepository
+	transactionRepo port.TransactionRepository
+	debtRepo        port.DebtRepository
+	snapshotService port.SnapshotService
+	ledgerService   port.LedgerService
+	auditService    port.AuditService
+	txManager       port.TransactionManager
 }
 
-func NewOriginService(repo port.OriginRepository) *OriginService {
+func NewOriginService(
+	repo port.OriginRepository,
+	transactionRepo port.TransactionRepository,
+	debtRepo port.DebtRepository,
+	snapshotService port.SnapshotService,
+	ledgerService port.LedgerService,
+	auditService port.AuditService,
+	txManager port.TransactionManager) *OriginService {
 
 	return &OriginService{
 		repo,
+		transactionRepo,
+		debtRepo,
+		snapshotService,
+		ledgerService,
+		auditService,
+		txManager,
 	}
 }
 
-func (os *OriginService) GetOriginsByUserId(ctx context.Context, userId string) ([]domain.Origin, error) {
+// GetOriginsByUserId lists the user's origins, the archived ones only if
+// includeArchived is set.
+func (os *OriginService) GetOriginsByUserId(ctx context.Context, userId string, includeArchived bool) ([]domain.Origin, error) {
 
-	origins, err := os.repo.GetOriginsByUserId(ctx, userId)
+	origins, err := os.repo.GetOriginsByUserId(ctx, userId, includeArchived)
 	if err != nil {
 		return nil, domain.ErrInternal
 	}
 
+	if err := os.withLedgerBalances(ctx, userId, origins); err != nil {
+		return nil, err
+	}
+
 	return origins, nil
 
 }
@@ -42,40 +68,476 @@ func (os *OriginService) GetOriginById(ctx context.Context, id string) (*domain.
 		return nil, domain.ErrInternal
 	}
 
+	origins := []domain.Origin{*origin}
+	if err := os.withLedgerBalances(ctx, origin.UserId, origins); err != nil {
+		return nil, err
+	}
+	origin.Total = origins[0].Total
+
 	return origin, nil
 }
 
+// withLedgerBalances sets the Total of each journaled origin to its balance
+// in the journal, the stored Total being only a cache of it that balance
+// repairs rebuild. Origins the journal does not hold in full yet keep their
+// stored Total until they are migrated.
+func (os *OriginService) withLedgerBalances(ctx context.Context, userId string, origins []domain.Origin) error {
+
+	balances, err := os.ledgerService.GetOriginBalances(ctx, userId, origins)
+	if err != nil {
+		return err
+	}
+
+	for i := range origins {
+		if balance, ok := balances[origins[i].ID]; ok {
+			origins[i].Total = balance
+		}
+	}
+
+	return nil
+}
+
 func (os *OriginService) CreateOrigin(ctx context.Context, origin *domain.Origin) (*domain.Origin, error) {
 
-	origin, err := os.repo.CreateOrigin(ctx, origin)
+	if err := validateOriginKind(origin); err != nil {
+		return nil, err
+	}
 
-	if err != nil {
-		if err == domain.ErrConflictingData {
-			return nil, err
+	if origin.OpeningBalance == nil {
+		opening := origin.Total
+		origin.OpeningBalance = &opening
+	}
+
+	err := os.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+
+		created, err := os.repo.CreateOrigin(txCtx, origin)
+		if err != nil {
+			if err == domain.ErrConflictingData {
+				return err
+			}
+			return domain.ErrInternal
 		}
-		return nil, domain.ErrInternal
+		origin = created
+
+		if err := os.auditService.Record(txCtx, origin.UserId, domain.AuditOrigin, origin.ID, domain.AuditCreate, nil, origin); err != nil {
+			return err
+		}
+
+		if err := os.ledgerService.PostOpening(txCtx, origin, *origin.OpeningBalance, domain.EntryOpening); err != nil {
+			return err
+		}
+
+		return os.snapshotService.RecordBalance(txCtx, origin)
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return origin, nil
 }
 
+// UpdateOrigin replaces the origin, keeping its stored kind when none is
+// given. The kind cannot move between assets and liabilities, since that
+// would flip the sign of the balance and of every transaction already
+// applied to it.
 func (os *OriginService) UpdateOrigin(ctx context.Context, id string, origin *domain.Origin) (*domain.Origin, error) {
 
-	_, err := os.repo.UpdateOrigin(ctx, id, origin)
+	err := os.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+
+		actual, err := os.GetOriginById(txCtx, id)
+		if err != nil {
+			return err
+		}
+
+		if origin.Kind == "" {
+			origin.Kind = actual.Kind
+		}
+
+		if err := validateOriginKind(origin); err != nil {
+			return err
+		}
+
+		if origin.IsLiability() != actual.IsLiability() {
+			return domain.ErrOriginKindChange
+		}
+
+		// A total edited by hand is not backed by any transaction: it moves
+		// the opening balance along and is journaled against opening equity,
+		// so the ledger still adds up.
+		if actual.OpeningBalance != nil {
+			opening := *actual.OpeningBalance + origin.Total - actual.Total
+			origin.OpeningBalance = &opening
+		}
+
+		_, err = os.repo.UpdateOrigin(txCtx, id, origin)
+		if err != nil {
+			if err == domain.ErrConflictingData {
+				return err
+			}
+			if err == domain.ErrDataNotFound {
+				return domain.ErrDataNotFound
+			}
+			return domain.ErrInternal
+		}
+
+		origin.ID = id
+
+		if err := os.auditService.Record(txCtx, actual.UserId, domain.AuditOrigin, id, domain.AuditUpdate, actual, origin); err != nil {
+			return err
+		}
+
+		if err := os.ledgerService.PostOpening(txCtx, origin, origin.Total-actual.Total, domain.EntryAdjustment); err != nil {
+			return err
+		}
+
+		return os.snapshotService.RecordBalance(txCtx, origin)
+	})
 	if err != nil {
-		if err == domain.ErrConflictingData {
-			return nil, err
+		return nil, err
+	}
+
+	return origin, nil
+}
+
+// DeleteOrigin moves the origin to the trash and closes its balance history
+// with a zero snapshot, so the net worth stops counting it from today on.
+// deletion tells what happens to the origin's transactions: by default the
+// deletion is refused while there are any, those in the trash included; they can instead be reassigned to
+// another origin along with their balance, or the origin archived and kept.
+// An origin backing a debt can only be archived, since the debt's payment
+// history is read from it.
+func (os *OriginService) DeleteOrigin(ctx context.Context, id string, deletion domain.OriginDeletion) error {
+
+	return os.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+
+		origin, err := os.GetOriginById(txCtx, id)
+		if err != nil {
+			return err
+		}
+
+		if deletion.Mode != domain.OriginDeleteArchive {
+			if err := os.checkNoDebts(txCtx, origin); err != nil {
+				return err
+			}
+		}
+
+		switch deletion.Mode {
+		case "", domain.OriginDeleteRefuse:
+			count, err := os.transactionRepo.CountTransactionsByOriginId(txCtx, id)
+			if err != nil {
+				return domain.ErrInternal
+			}
+			if count > 0 {
+				return domain.ErrOriginInUse
+			}
+		case domain.OriginDeleteReassign:
+			if err := os.reassignTransactions(txCtx, origin, deletion.TargetId); err != nil {
+				return err
+			}
+		case domain.OriginDeleteArchive:
+			_, err := os.archiveOrigin(txCtx, origin)
+			return err
+		default:
+			return domain.ErrInvalidOriginDeletion
+		}
+
+		if err := os.repo.DeleteOrigin(txCtx, id); err != nil {
+			if err == domain.ErrDataNotFound {
+				return err
+			}
+			return domain.ErrInternal
+		}
+
+		if err := os.auditService.Record(txCtx, origin.UserId, domain.AuditOrigin, id, domain.AuditDelete, origin, nil); err != nil {
+			return err
+		}
+
+		closed := *origin
+		closed.Total = 0
+
+		return os.snapshotService.RecordBalance(txCtx, &closed)
+	})
+}
+
+// checkNoDebts refuses with ErrOriginHasDebt while a debt of the user is
+// booked on origin.
+func (os *OriginService) checkNoDebts(ctx context.Context, origin *domain.Origin) error {
+
+	debts, err := os.debtRepo.GetDebtsByUserId(ctx, origin.UserId)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	for _, debt := range debts {
+		if debt.OriginId == origin.ID {
+			return domain.ErrOriginHasDebt
+		}
+	}
+
+	return nil
+}
+
+// reassignTransactions moves every transaction of origin to the target origin
+// of the same user, shifting their balance and journal entries along. The
+// move is refused if it would touch a reconciled period on either side.
+func (os *OriginService) reassignTransactions(ctx context.Context, origin *domain.Origin, targetId string) error {
+
+	if targetId == "" || targetId == origin.ID {
+		return domain.ErrInvalidOriginDeletion
+	}
+
+	target, err := os.GetOriginById(ctx, targetId)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return domain.ErrInvalidOriginDeletion
+		}
+		return err
+	}
+
+	if target.UserId != origin.UserId {
+		return domain.ErrInvalidOriginDeletion
+	}
+
+	if target.IsArchived() {
+		return domain.ErrArchivedOrigin
+	}
+
+	transactions, err := os.transactionRepo.GetTransactionsByOriginId(ctx, origin.ID)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	for _, transaction := range transactions {
+		if transaction.Reconciled || (transaction.DeletedAt == nil && (origin.IsLocked(transaction.CreatedAt) || target.IsLocked(transaction.CreatedAt))) {
+			return domain.ErrReconciledPeriod
+		}
+	}
+
+	if err := os.transactionRepo.ReassignTransactions(ctx, origin.ID, target.ID); err != nil {
+		return domain.ErrInternal
+	}
+
+	for _, transaction := range transactions {
+
+		moved := transaction
+		moved.OriginId = &target.ID
+
+		if err := os.auditService.Record(ctx, transaction.UserId, domain.AuditTransaction, transaction.ID, domain.AuditUpdate, &transaction, &moved); err != nil {
+			return err
 		}
+
+		// Transactions in the trash no longer count on any balance
+		if transaction.DeletedAt != nil {
+			continue
+		}
+
+		origin.ApplyTransaction(transaction.Type, -transaction.Amount)
+		target.ApplyTransaction(transaction.Type, transaction.Amount)
+
+		if err := os.ledgerService.ReverseTransaction(ctx, &transaction); err != nil {
+			return err
+		}
+
+		if err := os.ledgerService.PostTransaction(ctx, &moved); err != nil {
+			return err
+		}
+	}
+
+	for _, updated := range []*domain.Origin{origin, target} {
+		if _, err := os.repo.UpdateOrigin(ctx, updated.ID, updated); err != nil {
+			return domain.ErrInternal
+		}
+	}
+
+	return os.snapshotService.RecordBalance(ctx, target)
+}
+
+// ArchiveOrigin hides the origin from the listings and reports and closes it
+// to new transactions. Its transactions and balance history are kept.
+func (os *OriginService) ArchiveOrigin(ctx context.Context, id string) (*domain.Origin, error) {
+
+	var archived *domain.Origin
+
+	err := os.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+
+		origin, err := os.GetOriginById(txCtx, id)
+		if err != nil {
+			return err
+		}
+
+		archived, err = os.archiveOrigin(txCtx, origin)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return archived, nil
+}
+
+// UnarchiveOrigin brings an archived origin back to the listings and reports.
+func (os *OriginService) UnarchiveOrigin(ctx context.Context, id string) (*domain.Origin, error) {
+
+	var unarchived *domain.Origin
+
+	err := os.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+
+		origin, err := os.GetOriginById(txCtx, id)
+		if err != nil {
+			return err
+		}
+
+		if !origin.IsArchived() {
+			unarchived = origin
+			return nil
+		}
+
+		if err := os.repo.UnarchiveOrigin(txCtx, id); err != nil {
+			if err == domain.ErrDataNotFound {
+				return err
+			}
+			return domain.ErrInternal
+		}
+
+		unarchived, err = os.GetOriginById(txCtx, id)
+		if err != nil {
+			return err
+		}
+
+		return os.auditService.Record(txCtx, origin.UserId, domain.AuditOrigin, id, domain.AuditUpdate, origin, unarchived)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return unarchived, nil
+}
+
+// archiveOrigin archives the origin, unless it already is, and returns it.
+func (os *OriginService) archiveOrigin(ctx context.Context, origin *domain.Origin) (*domain.Origin, error) {
+
+	if origin.IsArchived() {
+		return origin, nil
+	}
+
+	if err := os.repo.ArchiveOrigin(ctx, origin.ID); err != nil {
 		if err == domain.ErrDataNotFound {
-			return nil, domain.ErrDataNotFound
+			return nil, err
 		}
 		return nil, domain.ErrInternal
 	}
 
-	return origin, nil
+	archived, err := os.GetOriginById(ctx, origin.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.auditService.Record(ctx, origin.UserId, domain.AuditOrigin, origin.ID, domain.AuditUpdate, origin, archived); err != nil {
+		return nil, err
+	}
+
+	return archived, nil
+}
+
+func (os *OriginService) GetDeletedOrigins(ctx context.Context, userId string) ([]domain.Origin, error) {
+
+	origins, err := os.repo.GetDeletedOrigins(ctx, userId)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return origins, nil
+}
+
+// RestoreOrigin takes the origin out of the trash and records its balance
+// again, so the net worth counts it from today on.
+func (os *OriginService) RestoreOrigin(ctx context.Context, id string) (*domain.Origin, error) {
+
+	var restored *domain.Origin
+
+	err := os.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+
+		origin, err := os.repo.GetDeletedOriginById(txCtx, id)
+		if err != nil {
+			if err == domain.ErrDataNotFound {
+				return err
+			}
+			return domain.ErrInternal
+		}
+
+		if err := os.repo.RestoreOrigin(txCtx, id); err != nil {
+			if err == domain.ErrDataNotFound {
+				return err
+			}
+			return domain.ErrInternal
+		}
+
+		restored, err = os.GetOriginById(txCtx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := os.auditService.Record(txCtx, restored.UserId, domain.AuditOrigin, id, domain.AuditRestore, origin, restored); err != nil {
+			return err
+		}
+
+		return os.snapshotService.RecordBalance(txCtx, restored)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return restored, nil
+}
+
+// PurgeDeletedOrigins permanently deletes the origins moved to the trash
+// before the given time and returns how many were deleted.
+func (os *OriginService) PurgeDeletedOrigins(ctx context.Context, before time.Time) (int64, error) {
+
+	purged, err := os.repo.PurgeOrigins(ctx, before)
+	if err != nil {
+		return 0, domain.ErrInternal
+	}
+
+	return purged, nil
 }
 
-func (os *OriginService) DeleteOrigin(ctx context.Context, id string) error {
+// validateOriginKind defaults the kind to checking and checks that the
+// kind-specific fields set belong to it: the credit limit, statement day and
+// payment terms to credit cards, the interest rate to anything but cash and
+// checking.
+func validateOriginKind(origin *domain.Origin) error {
+
+	if origin.Kind == "" {
+		origin.Kind = domain.OriginChecking
+	}
+
+	if !domain.IsOriginKind(origin.Kind) {
+		return domain.ErrInvalidOriginKind
+	}
+
+	isCreditCard := origin.Kind == domain.OriginCreditCard
+
+	if origin.CreditLimit != nil && (!isCreditCard || *origin.CreditLimit < 0) {
+		return domain.ErrInvalidOriginFields
+	}
+
+	if origin.StatementDay != 0 && (!isCreditCard || origin.StatementDay < 1 || origin.StatementDay > 31) {
+		return domain.ErrInvalidOriginFields
+	}
+
+	if origin.PaymentDueDays != 0 && (!isCreditCard || origin.PaymentDueDays < 0) {
+		return domain.ErrInvalidOriginFields
+	}
+
+	if origin.MinimumPaymentRate != nil && (!isCreditCard || *origin.MinimumPaymentRate < 0 || *origin.MinimumPaymentRate > 100) {
+		return domain.ErrInvalidOriginFields
+	}
+
+	if origin.InterestRate != nil && (origin.Kind == domain.OriginCash || origin.Kind == domain.OriginChecking || *origin.InterestRate < 0) {
+		return domain.ErrInvalidOriginFields
+	}
 
-	return os.repo.DeleteOrigin(ctx, id)
+	return nil
 }
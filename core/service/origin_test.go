@@ -0,0 +1,322 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"personal-finance/core/domain"
+)
+
+func floatPtr(f float64) *float64 { return &f }
+
+func TestValidateOriginKind_DefaultsToChecking(t *testing.T) {
+	origin := domain.Origin{Name: "Wallet"}
+
+	if err := validateOriginKind(&origin); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if origin.Kind != domain.OriginChecking {
+		t.Errorf("expected kind checking, got %q", origin.Kind)
+	}
+}
+
+func TestValidateOriginKind_KindSpecificFields(t *testing.T) {
+	cases := []struct {
+		name   string
+		origin domain.Origin
+		err    error
+	}{
+		{"unknown kind", domain.Origin{Kind: "crypto"}, domain.ErrInvalidOriginKind},
+		{"credit card fields", domain.Origin{Kind: domain.OriginCreditCard, CreditLimit: floatPtr(1000), StatementDay: 25, InterestRate: floatPtr(28)}, nil},
+		{"credit limit on savings", domain.Origin{Kind: domain.OriginSavings, CreditLimit: floatPtr(1000)}, domain.ErrInvalidOriginFields},
+		{"statement day on loan", domain.Origin{Kind: domain.OriginLoan, StatementDay: 5}, domain.ErrInvalidOriginFields},
+		{"statement day out of range", domain.Origin{Kind: domain.OriginCreditCard, StatementDay: 32}, domain.ErrInvalidOriginFields},
+		{"interest on loan", domain.Origin{Kind: domain.OriginLoan, InterestRate: floatPtr(12.5)}, nil},
+		{"interest on cash", domain.Origin{Kind: domain.OriginCash, InterestRate: floatPtr(1)}, domain.ErrInvalidOriginFields},
+	}
+
+	for _, c := range cases {
+		origin := c.origin
+		if err := validateOriginKind(&origin); err != c.err {
+			t.Errorf("%s: expected %v, got %v", c.name, c.err, err)
+		}
+	}
+}
+
+func newOriginService(tRepo *mockTransactionRepo, oRepo *mockOriginRepo) *OriginService {
+	return newOriginServiceWithDebts(tRepo, oRepo, &mockDebtRepo{})
+}
+
+func newOriginServiceWithDebts(tRepo *mockTransactionRepo, oRepo *mockOriginRepo, dRepo *mockDebtRepo) *OriginService {
+	return NewOriginService(oRepo, tRepo, dRepo, NewSnapshotService(newMockSnapshotRepo(), oRepo), NewLedgerService(&mockLedgerRepo{}, oRepo, tRepo, noopTxManager{}), NewAuditService(&mockAuditRepo{}), noopTxManager{})
+}
+
+func TestUpdateOrigin_KeepsStoredKindWhenOmitted(t *testing.T) {
+	oRepo := newMockOriginRepo(map[string]*domain.Origin{
+		"o1": {ID: "o1", UserId: "u1", Kind: domain.OriginCreditCard, Total: 200, CreditLimit: floatPtr(1000)},
+	})
+	os := newOriginService(&mockTransactionRepo{}, oRepo)
+
+	if _, err := os.UpdateOrigin(context.Background(), "o1", &domain.Origin{UserId: "u1", Name: "Visa", Total: 200, CreditLimit: floatPtr(1500)}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := oRepo.origins["o1"].Kind; got != domain.OriginCreditCard {
+		t.Errorf("expected kind credit_card kept, got %q", got)
+	}
+}
+
+func TestUpdateOrigin_RefusesAssetLiabilitySwitch(t *testing.T) {
+	oRepo := newMockOriginRepo(map[string]*domain.Origin{
+		"o1": {ID: "o1", UserId: "u1", Kind: domain.OriginSavings, Total: 500},
+		"o2": {ID: "o2", UserId: "u1", Kind: domain.OriginLoan, Total: 900},
+	})
+	os := newOriginService(&mockTransactionRepo{}, oRepo)
+
+	if _, err := os.UpdateOrigin(context.Background(), "o1", &domain.Origin{UserId: "u1", Name: "Savings", Kind: domain.OriginLoan, Total: 500}); err != domain.ErrOriginKindChange {
+		t.Fatalf("expected ErrOriginKindChange, got %v", err)
+	}
+	if _, err := os.UpdateOrigin(context.Background(), "o2", &domain.Origin{UserId: "u1", Name: "Loan", Kind: domain.OriginChecking, Total: 900}); err != domain.ErrOriginKindChange {
+		t.Fatalf("expected ErrOriginKindChange, got %v", err)
+	}
+	if oRepo.origins["o1"].Kind != domain.OriginSavings || oRepo.origins["o2"].Kind != domain.OriginLoan {
+		t.Errorf("expected kinds unchanged, got %q and %q", oRepo.origins["o1"].Kind, oRepo.origins["o2"].Kind)
+	}
+
+	// switching within assets is fine
+	if _, err := os.UpdateOrigin(context.Background(), "o1", &domain.Origin{UserId: "u1", Name: "Savings", Kind: domain.OriginInvestment, Total: 500}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestDeleteOrigin_RefusesWithTransactions(t *testing.T) {
+	oRepo := newMockOriginRepo(map[string]*domain.Origin{
+		"o1": {ID: "o1", UserId: "u1", Total: 100},
+	})
+	tRepo := &mockTransactionRepo{byOrigin: []domain.Transaction{
+		{ID: "t1", OriginId: strPtr("o1"), Type: "Income", Amount: 100},
+	}}
+	os := newOriginService(tRepo, oRepo)
+
+	err := os.DeleteOrigin(context.Background(), "o1", domain.OriginDeletion{Mode: domain.OriginDeleteRefuse})
+	if err != domain.ErrOriginInUse {
+		t.Fatalf("expected ErrOriginInUse, got %v", err)
+	}
+	if len(oRepo.deleted) != 0 {
+		t.Errorf("expected nothing deleted, got %v", oRepo.deleted)
+	}
+}
+
+func TestDeleteOrigin_RefuseCountsTrash(t *testing.T) {
+	deletedAt := time.Now()
+	oRepo := newMockOriginRepo(map[string]*domain.Origin{
+		"o1": {ID: "o1", UserId: "u1", Total: 0},
+	})
+	tRepo := &mockTransactionRepo{byOrigin: []domain.Transaction{
+		{ID: "t1", OriginId: strPtr("o1"), Type: "Income", Amount: 100, DeletedAt: &deletedAt},
+	}}
+	os := newOriginService(tRepo, oRepo)
+
+	// The trashed transaction could still be restored onto o1.
+	if err := os.DeleteOrigin(context.Background(), "o1", domain.OriginDeletion{}); err != domain.ErrOriginInUse {
+		t.Fatalf("expected ErrOriginInUse, got %v", err)
+	}
+	if len(oRepo.deleted) != 0 {
+		t.Errorf("expected o1 kept, got %v", oRepo.deleted)
+	}
+}
+
+func TestDeleteOrigin_ReassignMovesBalance(t *testing.T) {
+	deletedAt := time.Now()
+	oRepo := newMockOriginRepo(map[string]*domain.Origin{
+		"o1": {ID: "o1", UserId: "u1", Total: 300},
+		"o2": {ID: "o2", UserId: "u1", Total: 500},
+	})
+	tRepo := &mockTransactionRepo{byOrigin: []domain.Transaction{
+		{ID: "t1", UserId: "u1", OriginId: strPtr("o1"), Type: "Income", Amount: 150},
+		{ID: "t2", UserId: "u1", OriginId: strPtr("o1"), Type: "Output", Amount: 50},
+		{ID: "t3", UserId: "u1", OriginId: strPtr("o1"), Type: "Output", Amount: 30, DeletedAt: &deletedAt},
+	}}
+	os := newOriginService(tRepo, oRepo)
+
+	deletion := domain.OriginDeletion{Mode: domain.OriginDeleteReassign, TargetId: "o2"}
+	if err := os.DeleteOrigin(context.Background(), "o1", deletion); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(tRepo.reassigned) != 1 || tRepo.reassigned[0] != "o1->o2" {
+		t.Errorf("expected the transactions moved from o1 to o2, got %v", tRepo.reassigned)
+	}
+	// The trashed output does not count: o2 gains 150 - 50 = 100
+	if got := oRepo.origins["o2"].Total; got != 600 {
+		t.Errorf("expected o2 total 600, got %v", got)
+	}
+	if got := oRepo.origins["o1"].Total; got != 200 {
+		t.Errorf("expected o1 total 200, got %v", got)
+	}
+	if len(oRepo.deleted) != 1 || oRepo.deleted[0] != "o1" {
+		t.Errorf("expected o1 deleted, got %v", oRepo.deleted)
+	}
+}
+
+func TestDeleteOrigin_RefusesWhileBackingDebt(t *testing.T) {
+	for _, mode := range []string{domain.OriginDeleteRefuse, domain.OriginDeleteReassign} {
+		oRepo := newMockOriginRepo(map[string]*domain.Origin{
+			"loan":  {ID: "loan", UserId: "u1", Kind: domain.OriginLoan, Total: 9000},
+			"other": {ID: "other", UserId: "u1", Kind: domain.OriginLoan, Total: 0},
+		})
+		tRepo := &mockTransactionRepo{byOrigin: []domain.Transaction{
+			{ID: "t1", UserId: "u1", OriginId: strPtr("loan"), Type: "Income", Amount: 1000, DebtPayment: &domain.DebtPayment{DebtId: "d1"}},
+		}}
+		dRepo := &mockDebtRepo{debts: map[string]*domain.Debt{
+			"d1": {ID: "d1", UserId: "u1", OriginId: "loan"},
+		}}
+		os := newOriginServiceWithDebts(tRepo, oRepo, dRepo)
+
+		deletion := domain.OriginDeletion{Mode: mode, TargetId: "other"}
+		if err := os.DeleteOrigin(context.Background(), "loan", deletion); err != domain.ErrOriginHasDebt {
+			t.Errorf("%s: expected ErrOriginHasDebt, got %v", mode, err)
+		}
+		if len(tRepo.reassigned) != 0 || len(oRepo.deleted) != 0 {
+			t.Errorf("%s: expected nothing moved or deleted, got %v and %v", mode, tRepo.reassigned, oRepo.deleted)
+		}
+	}
+}
+
+func TestDeleteOrigin_ReassignInvalidTarget(t *testing.T) {
+	cases := []struct {
+		name     string
+		targetId string
+	}{
+		{"no target", ""},
+		{"same origin", "o1"},
+		{"missing target", "o9"},
+		{"other user", "o3"},
+	}
+
+	for _, c := range cases {
+		oRepo := newMockOriginRepo(map[string]*domain.Origin{
+			"o1": {ID: "o1", UserId: "u1", Total: 100},
+			"o3": {ID: "o3", UserId: "u2", Total: 0},
+		})
+		tRepo := &mockTransactionRepo{}
+		os := newOriginService(tRepo, oRepo)
+
+		deletion := domain.OriginDeletion{Mode: domain.OriginDeleteReassign, TargetId: c.targetId}
+		if err := os.DeleteOrigin(context.Background(), "o1", deletion); err != domain.ErrInvalidOriginDeletion {
+			t.Errorf("%s: expected ErrInvalidOriginDeletion, got %v", c.name, err)
+		}
+		if len(tRepo.reassigned) != 0 || len(oRepo.deleted) != 0 {
+			t.Errorf("%s: expected nothing changed", c.name)
+		}
+	}
+}
+
+func TestDeleteOrigin_ReassignIntoReconciledPeriod(t *testing.T) {
+	through := time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)
+	oRepo := newMockOriginRepo(map[string]*domain.Origin{
+		"o1": {ID: "o1", UserId: "u1", Total: 100},
+		"o2": {ID: "o2", UserId: "u1", Total: 0, ReconciledThrough: &through},
+	})
+	tRepo := &mockTransactionRepo{byOrigin: []domain.Transaction{
+		{ID: "t1", OriginId: strPtr("o1"), Type: "Income", Amount: 100, CreatedAt: through.AddDate(0, 0, -1)},
+	}}
+	os := newOriginService(tRepo, oRepo)
+
+	deletion := domain.OriginDeletion{Mode: domain.OriginDeleteReassign, TargetId: "o2"}
+	if err := os.DeleteOrigin(context.Background(), "o1", deletion); err != domain.ErrReconciledPeriod {
+		t.Fatalf("expected ErrReconciledPeriod, got %v", err)
+	}
+	if len(tRepo.reassigned) != 0 {
+		t.Errorf("expected nothing reassigned, got %v", tRepo.reassigned)
+	}
+}
+
+func TestDeleteOrigin_ReassignOutOfReconciledPeriod(t *testing.T) {
+	through := time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)
+	oRepo := newMockOriginRepo(map[string]*domain.Origin{
+		"o1": {ID: "o1", UserId: "u1", Total: 100, ReconciledThrough: &through},
+		"o2": {ID: "o2", UserId: "u1", Total: 0},
+	})
+	// t1 falls in o1's reconciled period without having been cleared.
+	tRepo := &mockTransactionRepo{byOrigin: []domain.Transaction{
+		{ID: "t1", OriginId: strPtr("o1"), Type: "Income", Amount: 100, CreatedAt: through.AddDate(0, 0, -1)},
+	}}
+	os := newOriginService(tRepo, oRepo)
+
+	deletion := domain.OriginDeletion{Mode: domain.OriginDeleteReassign, TargetId: "o2"}
+	if err := os.DeleteOrigin(context.Background(), "o1", deletion); err != domain.ErrReconciledPeriod {
+		t.Fatalf("expected ErrReconciledPeriod, got %v", err)
+	}
+	if len(tRepo.reassigned) != 0 {
+		t.Errorf("expected nothing reassigned, got %v", tRepo.reassigned)
+	}
+}
+
+func TestDeleteOrigin_Archive(t *testing.T) {
+	oRepo := newMockOriginRepo(map[string]*domain.Origin{
+		"o1": {ID: "o1", UserId: "u1", Total: 100},
+	})
+	tRepo := &mockTransactionRepo{byOrigin: []domain.Transaction{
+		{ID: "t1", OriginId: strPtr("o1"), Type: "Income", Amount: 100},
+	}}
+	os := newOriginService(tRepo, oRepo)
+
+	if err := os.DeleteOrigin(context.Background(), "o1", domain.OriginDeletion{Mode: domain.OriginDeleteArchive}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if oRepo.origins["o1"].ArchivedAt == nil {
+		t.Errorf("expected o1 archived")
+	}
+	if len(oRepo.deleted) != 0 {
+		t.Errorf("expected nothing deleted, got %v", oRepo.deleted)
+	}
+}
+
+func TestArchiveOrigin_HiddenFromListing(t *testing.T) {
+	oRepo := newMockOriginRepo(map[string]*domain.Origin{
+		"o1": {ID: "o1", UserId: "u1", Total: 0},
+		"o2": {ID: "o2", UserId: "u1", Total: 100},
+	})
+	os := newOriginService(&mockTransactionRepo{}, oRepo)
+	ctx := context.Background()
+
+	archived, err := os.ArchiveOrigin(ctx, "o1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !archived.IsArchived() {
+		t.Errorf("expected o1 archived")
+	}
+
+	if origins, _ := os.GetOriginsByUserId(ctx, "u1", false); len(origins) != 1 || origins[0].ID != "o2" {
+		t.Errorf("expected only o2 listed, got %+v", origins)
+	}
+	if origins, _ := os.GetOriginsByUserId(ctx, "u1", true); len(origins) != 2 {
+		t.Errorf("expected both origins with include archived, got %d", len(origins))
+	}
+
+	unarchived, err := os.UnarchiveOrigin(ctx, "o1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if unarchived.IsArchived() {
+		t.Errorf("expected o1 unarchived")
+	}
+}
+
+func TestDeleteOrigin_ReassignToArchivedTarget(t *testing.T) {
+	archivedAt := time.Now()
+	oRepo := newMockOriginRepo(map[string]*domain.Origin{
+		"o1": {ID: "o1", UserId: "u1", Total: 100},
+		"o2": {ID: "o2", UserId: "u1", Total: 0, ArchivedAt: &archivedAt},
+	})
+	tRepo := &mockTransactionRepo{}
+	os := newOriginService(tRepo, oRepo)
+
+	deletion := domain.OriginDeletion{Mode: domain.OriginDeleteReassign, TargetId: "o2"}
+	if err := os.DeleteOrigin(context.Background(), "o1", deletion); err != domain.ErrArchivedOrigin {
+		t.Fatalf("expected ErrArchivedOrigin, got %v", err)
+	}
+}
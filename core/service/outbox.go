@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"personal-finance/core/domain"
+	"personal-finance/core/port"
+	"time"
+)
+
+const (
+	// outboxLease is how long a claimed message stays hidden from other
+	// workers while it is being sent.
+	outboxLease = 5 * time.Minute
+
+	maxOutboxBackoff = 6 * time.Hour
+)
+
+type OutboxService struct {
+	outboxRepo  port.OutboxRepository
+	sender      port.MailSender
+	maxAttempts int
+	baseBackoff time.Duration
+}
+
+func NewOutboxService(
+	outboxRepo port.OutboxRepository,
+	sender port.MailSender,
+	maxAttempts int,
+	baseBackoff time.Duration,
+) *OutboxService {
+
+	return &OutboxService{
+		outboxRepo,
+		sender,
+		maxAttempts,
+		baseBackoff,
+	}
+}
+
+func (obs *OutboxService) GetMessages(ctx context.Context, status string, page, limit uint64) ([]domain.OutboxMessage, int64, int, error) {
+
+	messages, totalDocuments, totalPages, err := obs.outboxRepo.GetMessages(ctx, status, page, limit)
+	if err != nil {
+		return nil, 0, 0, domain.ErrInternal
+	}
+
+	return messages, totalDocuments, totalPages, nil
+}
+
+func (obs *OutboxService) GetMessageById(ctx context.Context, id string) (*domain.OutboxMessage, error) {
+
+	message, err := obs.outboxRepo.GetMessageById(ctx, id)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	return message, nil
+}
+
+// EnqueueMessage queues the message for immediate delivery. A message already
+// queued under the same idempotency key is returned instead.
+func (obs *OutboxService) EnqueueMessage(ctx context.Context, message *domain.OutboxMessage) (*domain.OutboxMessage, error) {
+
+	now := time.Now()
+
+	message.Status = domain.OutboxPending
+	message.Attempts = 0
+	message.NextAttemptAt = now
+	message.CreatedAt = now
+	message.UpdatedAt = now
+
+	queued, err := obs.outboxRepo.EnqueueMessage(ctx, message)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return queued, nil
+}
+
+// ProcessDueMessages sends every message due at now and returns how many were
+// sent. Failed messages are rescheduled with exponential backoff, or marked
+// dead once they used all their attempts.
+func (obs *OutboxService) ProcessDueMessages(ctx context.Context, now time.Time) (int, error) {
+
+	sent := 0
+
+	for {
+		message, err := obs.outboxRepo.ClaimDueMessage(ctx, now, now.Add(outboxLease))
+		if err != nil {
+			if err == domain.ErrDataNotFound {
+				return sent, nil
+			}
+			return sent, domain.ErrInternal
+		}
+
+		sendErr := obs.sender.SendMessage(ctx, message)
+
+		message.UpdatedAt = time.Now()
+
+		if sendErr == nil {
+			message.Status = domain.OutboxSent
+			message.LastError = ""
+			message.SentAt = &message.UpdatedAt
+			sent++
+		} else {
+			message.LastError = sendErr.Error()
+			if message.Attempts >= obs.maxAttempts {
+				message.Status = domain.OutboxDead
+				slog.Error("Mail dead-lettered", "id", message.ID, "attempts", message.Attempts, "error", sendErr)
+			} else {
+				message.NextAttemptAt = now.Add(obs.backoff(message.Attempts))
+			}
+		}
+
+		if err := obs.outboxRepo.UpdateMessageStatus(ctx, message); err != nil {
+			return sent, domain.ErrInternal
+		}
+	}
+}
+
+// backoff returns the delay before the retry following the given attempt:
+// baseBackoff, doubled on every attempt and capped at maxOutboxBackoff.
+func (obs *OutboxService) backoff(attempts int) time.Duration {
+
+	delay := obs.baseBackoff
+	for i := 1; i < attempts && delay < maxOutboxBackoff; i++ {
+		delay *= 2
+	}
+
+	if delay > maxOutboxBackoff {
+		return maxOutboxBackoff
+	}
+
+	return delay
+}
+
+// ResendMessage queues again a message that has not been sent, resetting its attempts.
+func (obs *OutboxService) ResendMessage(ctx context.Context, id string) (*domain.OutboxMessage, error) {
+
+	message, err := obs.GetMessageById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if message.Status == domain.OutboxSent {
+		return nil, domain.ErrMessageAlreadySent
+	}
+
+	message, err = obs.outboxRepo.RequeueMessage(ctx, id, time.Now())
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, domain.ErrMessageAlreadySent
+		}
+		return nil, domain.ErrInternal
+	}
+
+	return message, nil
+}
@@ -0,0 +1,311 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"personal-finance/core/domain"
+	"personal-finance/core/port"
+	"time"
+)
+
+type ReconciliationService struct {
+	repo               port.ReconciliationRepository
+	originRepo         port.OriginRepository
+	transactionService port.TransactionService
+	txManager          port.TransactionManager
+}
+
+func NewReconciliationService(
+	repo port.ReconciliationRepository,
+	originRepo port.OriginRepository,
+	transactionService port.TransactionService,
+	txManager port.TransactionManager) *ReconciliationService {
+
+	return &ReconciliationService{
+		repo,
+		originRepo,
+		transactionService,
+		txManager,
+	}
+}
+
+// StartReconciliation opens a reconciliation of the origin against a
+// statement ending at statementDate with statementBalance. The date must
+// come after the origin's last reconciliation and only one reconciliation
+// per origin can be open at a time.
+func (rs *ReconciliationService) StartReconciliation(ctx context.Context, originId string, statementDate time.Time, statementBalance float64) (*domain.ReconciliationState, error) {
+
+	origin, err := rs.originRepo.GetOriginById(ctx, originId)
+	if err != nil {
+		if errors.Is(err, domain.ErrDataNotFound) {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, domain.ErrInternal
+	}
+
+	statementDate = domain.SnapshotDay(statementDate)
+	if origin.IsLocked(statementDate) {
+		return nil, domain.ErrReconciledPeriod
+	}
+
+	if _, err := rs.repo.GetOpenReconciliation(ctx, originId); err == nil {
+		return nil, domain.ErrConflictingData
+	} else if !errors.Is(err, domain.ErrDataNotFound) {
+		return nil, domain.ErrInternal
+	}
+
+	reconciliation := &domain.Reconciliation{
+		UserId:           origin.UserId,
+		OriginId:         originId,
+		StatementDate:    statementDate,
+		StatementBalance: statementBalance,
+		Status:           domain.ReconciliationOpen,
+		CreatedAt:        time.Now(),
+	}
+
+	reconciliation, err = rs.repo.CreateReconciliation(ctx, reconciliation)
+	if err != nil {
+		if err == domain.ErrConflictingData {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	return rs.reconciliationState(ctx, reconciliation)
+}
+
+func (rs *ReconciliationService) GetReconciliation(ctx context.Context, id string) (*domain.ReconciliationState, error) {
+
+	reconciliation, err := rs.getReconciliation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return rs.reconciliationState(ctx, reconciliation)
+}
+
+// SetCleared marks the given transactions of the reconciled origin as cleared
+// by the bank, or back as uncleared.
+func (rs *ReconciliationService) SetCleared(ctx context.Context, id string, transactionIds []string, cleared bool) (*domain.ReconciliationState, error) {
+
+	reconciliation, err := rs.getOpenReconciliation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := rs.repo.SetCleared(ctx, reconciliation.OriginId, transactionIds, cleared); err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return rs.reconciliationState(ctx, reconciliation)
+}
+
+// CompleteReconciliation books whatever still separates the cleared balance
+// from the statement balance as an adjustment transaction, then locks the
+// origin's cleared transactions through the statement date. All writes
+// commit or none do.
+func (rs *ReconciliationService) CompleteReconciliation(ctx context.Context, id string) (*domain.Reconciliation, error) {
+
+	var reconciliation *domain.Reconciliation
+
+	err := rs.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+
+		var err error
+		reconciliation, err = rs.getOpenReconciliation(txCtx, id)
+		if err != nil {
+			return err
+		}
+
+		origin, state, err := rs.loadState(txCtx, reconciliation)
+		if err != nil {
+			return err
+		}
+
+		if state.Difference != 0 {
+			adjustment := adjustmentTransaction(reconciliation, state.Difference, origin.IsLiability())
+
+			if err := rs.transactionService.BookTransaction(txCtx, adjustment); err != nil {
+				return err
+			}
+
+			reconciliation.Adjustment = state.Difference
+			reconciliation.AdjustmentId = adjustment.ID
+		}
+
+		if err := rs.repo.MarkReconciled(txCtx, reconciliation.OriginId, reconciliation.StatementDate); err != nil {
+			return domain.ErrInternal
+		}
+
+		if err := rs.repo.SetOriginReconciled(txCtx, reconciliation.OriginId, reconciliation.StatementDate, reconciliation.StatementBalance); err != nil {
+			return domain.ErrInternal
+		}
+
+		completedAt := time.Now()
+		reconciliation.Status = domain.ReconciliationCompleted
+		reconciliation.CompletedAt = &completedAt
+
+		if err := rs.repo.UpdateReconciliation(txCtx, reconciliation); err != nil {
+			return domain.ErrInternal
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return reconciliation, nil
+}
+
+// CancelReconciliation discards an open reconciliation. Cleared marks are
+// kept for the next one.
+func (rs *ReconciliationService) CancelReconciliation(ctx context.Context, id string) error {
+
+	if _, err := rs.getOpenReconciliation(ctx, id); err != nil {
+		return err
+	}
+
+	if err := rs.repo.DeleteReconciliation(ctx, id); err != nil {
+		if err == domain.ErrDataNotFound {
+			return err
+		}
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+func (rs *ReconciliationService) getReconciliation(ctx context.Context, id string) (*domain.Reconciliation, error) {
+
+	reconciliation, err := rs.repo.GetReconciliationById(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrDataNotFound) {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, domain.ErrInternal
+	}
+
+	return reconciliation, nil
+}
+
+func (rs *ReconciliationService) getOpenReconciliation(ctx context.Context, id string) (*domain.Reconciliation, error) {
+
+	reconciliation, err := rs.getReconciliation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if reconciliation.Status != domain.ReconciliationOpen {
+		return nil, domain.ErrReconciliationClosed
+	}
+
+	return reconciliation, nil
+}
+
+func (rs *ReconciliationService) reconciliationState(ctx context.Context, reconciliation *domain.Reconciliation) (*domain.ReconciliationState, error) {
+
+	_, state, err := rs.loadState(ctx, reconciliation)
+	if err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
+
+func (rs *ReconciliationService) loadState(ctx context.Context, reconciliation *domain.Reconciliation) (*domain.Origin, *domain.ReconciliationState, error) {
+
+	origin, err := rs.originRepo.GetOriginById(ctx, reconciliation.OriginId)
+	if err != nil {
+		if errors.Is(err, domain.ErrDataNotFound) {
+			return nil, nil, domain.ErrDataNotFound
+		}
+		return nil, nil, domain.ErrInternal
+	}
+
+	transactions, err := rs.repo.GetUnreconciledTransactions(ctx, reconciliation.OriginId)
+	if err != nil {
+		return nil, nil, domain.ErrInternal
+	}
+
+	state := clearedState(*origin, *reconciliation, transactions)
+
+	return origin, &state, nil
+}
+
+// clearedState works back from the origin's current balance to the balance
+// the bank should show on the statement date: transactions dated after it or
+// not yet cleared are unwound. Uncleared transactions up to the statement
+// date are listed for the user to review.
+func clearedState(origin domain.Origin, reconciliation domain.Reconciliation, transactions []domain.Transaction) domain.ReconciliationState {
+
+	end := domain.SnapshotDay(reconciliation.StatementDate).AddDate(0, 0, 1)
+
+	state := domain.ReconciliationState{
+		Reconciliation: reconciliation,
+		Uncleared:      []domain.Transaction{},
+	}
+
+	for _, transaction := range transactions {
+
+		if transaction.Cleared && transaction.CreatedAt.Before(end) {
+			continue
+		}
+
+		if transaction.CreatedAt.Before(end) {
+			state.Uncleared = append(state.Uncleared, transaction)
+		}
+
+		revertType := "Output"
+		if transaction.Type == "Output" {
+			revertType = "Income"
+		}
+		origin.ApplyTransaction(revertType, transaction.Amount)
+	}
+
+	state.ClearedBalance = round2(origin.Total)
+	state.Difference = round2(reconciliation.StatementBalance - state.ClearedBalance)
+
+	return state
+}
+
+// adjustmentTransaction books difference on the origin so its cleared balance
+// matches the statement. It is dated on the statement date and comes already
+// reconciled.
+func adjustmentTransaction(reconciliation *domain.Reconciliation, difference float64, liability bool) *domain.Transaction {
+
+	transactionType := adjustmentType(difference, liability)
+
+	originId := reconciliation.OriginId
+
+	transaction := &domain.Transaction{
+		UserId:          reconciliation.UserId,
+		OriginId:        &originId,
+		Amount:          math.Abs(difference),
+		Type:            transactionType,
+		Subject:         "Adjustment",
+		Description:     "Reconciliation adjustment",
+		Cleared:         true,
+		Reconciled:      true,
+		CreatedAtString: reconciliation.StatementDate.Format("2006-01-02"),
+		CreatedAt:       reconciliation.StatementDate,
+		UpdatedAt:       time.Now(),
+	}
+
+	if transactionType == "Output" {
+		transaction.OutputCategory = "Adjustment"
+	}
+
+	return transaction
+}
+
+// adjustmentType is the transaction type that moves an origin's Total by
+// difference: an Income raises an asset but lowers what a liability owes.
+func adjustmentType(difference float64, liability bool) string {
+
+	if (difference > 0) == liability {
+		return "Output"
+	}
+
+	return "Income"
+}
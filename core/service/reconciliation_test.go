@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"personal-finance/core/domain"
+)
+
+type mockReconciliationRepo struct {
+	reconciliations map[string]*domain.Reconciliation
+	transactions    []domain.Transaction
+	reconciledAt    *time.Time
+	origins         *mockOriginRepo
+}
+
+func (m *mockReconciliationRepo) GetReconciliationById(ctx context.Context, id string) (*domain.Reconciliation, error) {
+	reconciliation, ok := m.reconciliations[id]
+	if !ok {
+		return nil, domain.ErrDataNotFound
+	}
+	return reconciliation, nil
+}
+
+func (m *mockReconciliationRepo) GetOpenReconciliation(ctx context.Context, originId string) (*domain.Reconciliation, error) {
+	for _, reconciliation := range m.reconciliations {
+		if reconciliation.OriginId == originId && reconciliation.Status == domain.ReconciliationOpen {
+			return reconciliation, nil
+		}
+	}
+	return nil, domain.ErrDataNotFound
+}
+
+func (m *mockReconciliationRepo) CreateReconciliation(ctx context.Context, reconciliation *domain.Reconciliation) (*domain.Reconciliation, error) {
+	reconciliation.ID = "r1"
+	m.reconciliations[reconciliation.ID] = reconciliation
+	return reconciliation, nil
+}
+
+func (m *mockReconciliationRepo) UpdateReconciliation(ctx context.Context, reconciliation *domain.Reconciliation) error {
+	m.reconciliations[reconciliation.ID] = reconciliation
+	return nil
+}
+
+func (m *mockReconciliationRepo) DeleteReconciliation(ctx context.Context, id string) error {
+	delete(m.reconciliations, id)
+	return nil
+}
+
+func (m *mockReconciliationRepo) GetUnreconciledTransactions(ctx context.Context, originId string) ([]domain.Transaction, error) {
+	var transactions []domain.Transaction
+	for _, transaction := range m.transactions {
+		if !transaction.Reconciled {
+			transactions = append(transactions, transaction)
+		}
+	}
+	return transactions, nil
+}
+
+func (m *mockReconciliationRepo) SetCleared(ctx context.Context, originId string, ids []string, cleared bool) error {
+	for i := range m.transactions {
+		for _, id := range ids {
+			if m.transactions[i].ID == id && !m.transactions[i].Reconciled {
+				m.transactions[i].Cleared = cleared
+			}
+		}
+	}
+	return nil
+}
+
+func (m *mockReconciliationRepo) MarkReconciled(ctx context.Context, originId string, through time.Time) error {
+	for i := range m.transactions {
+		if m.transactions[i].Cleared && !m.transactions[i].CreatedAt.After(through) {
+			m.transactions[i].Reconciled = true
+		}
+	}
+	return nil
+}
+
+func (m *mockReconciliationRepo) SetOriginReconciled(ctx context.Context, originId string, through time.Time, balance float64) error {
+	m.reconciledAt = &through
+	m.origins.origins[originId].ReconciledThrough = &through
+	m.origins.origins[originId].ReconciledBalance = balance
+	return nil
+}
+
+func day(d int) time.Time {
+	return time.Date(2024, 3, d, 0, 0, 0, 0, time.UTC)
+}
+
+// checking holds 1000: 1200 brought forward, -150 and +50 in March up to the
+// statement on the 20th and -100 on the 25th.
+func newReconciliationFixture() (*ReconciliationService, *mockReconciliationRepo, *mockOriginRepo) {
+
+	oRepo := newMockOriginRepo(map[string]*domain.Origin{
+		"checking": {ID: "checking", UserId: "u1", Total: 1000},
+	})
+	rRepo := &mockReconciliationRepo{
+		reconciliations: map[string]*domain.Reconciliation{},
+		origins:         oRepo,
+		transactions: []domain.Transaction{
+			{ID: "t1", Type: "Output", Amount: 150, CreatedAt: day(5)},
+			{ID: "t2", Type: "Income", Amount: 50, CreatedAt: day(18)},
+			{ID: "t3", Type: "Output", Amount: 100, CreatedAt: day(25)},
+		},
+	}
+	tRepo := &mockTransactionRepo{}
+	svc := NewReconciliationService(rRepo, oRepo, newTransactionService(tRepo, oRepo), noopTxManager{})
+
+	return svc, rRepo, oRepo
+}
+
+func TestReconciliation_ClearedBalance(t *testing.T) {
+	svc, _, _ := newReconciliationFixture()
+	ctx := context.Background()
+
+	state, err := svc.StartReconciliation(ctx, "checking", day(20), 1050)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if state.ClearedBalance != 1200 || state.Difference != -150 || len(state.Uncleared) != 2 {
+		t.Fatalf("state = %+v", state)
+	}
+
+	state, err = svc.SetCleared(ctx, "r1", []string{"t1", "t2", "t3"}, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// t3 is cleared but dated after the statement, so it is still unwound.
+	if state.ClearedBalance != 1100 || state.Difference != -50 || len(state.Uncleared) != 0 {
+		t.Errorf("state = %+v", state)
+	}
+
+	if _, err := svc.StartReconciliation(ctx, "checking", day(21), 1000); err != domain.ErrConflictingData {
+		t.Errorf("expected ErrConflictingData for a second open reconciliation, got %v", err)
+	}
+}
+
+func TestReconciliation_CompleteBooksAdjustmentAndLocks(t *testing.T) {
+	svc, rRepo, oRepo := newReconciliationFixture()
+	ctx := context.Background()
+
+	if _, err := svc.StartReconciliation(ctx, "checking", day(20), 1090); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := svc.SetCleared(ctx, "r1", []string{"t1", "t2"}, true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	reconciliation, err := svc.CompleteReconciliation(ctx, "r1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if reconciliation.Status != domain.ReconciliationCompleted || reconciliation.Adjustment != -10 {
+		t.Errorf("reconciliation = %+v", reconciliation)
+	}
+	if got := oRepo.origins["checking"].Total; got != 990 {
+		t.Errorf("expected the -10 adjustment to leave 990, got %v", got)
+	}
+	if !rRepo.transactions[0].Reconciled || !rRepo.transactions[1].Reconciled || rRepo.transactions[2].Reconciled {
+		t.Errorf("transactions = %+v", rRepo.transactions)
+	}
+	if rRepo.reconciledAt == nil || !rRepo.reconciledAt.Equal(day(20)) {
+		t.Errorf("expected the origin reconciled through the 20th, got %v", rRepo.reconciledAt)
+	}
+
+	if _, err := svc.CompleteReconciliation(ctx, "r1"); err != domain.ErrReconciliationClosed {
+		t.Errorf("expected ErrReconciliationClosed, got %v", err)
+	}
+	if _, err := svc.StartReconciliation(ctx, "checking", day(20), 990); err != domain.ErrReconciledPeriod {
+		t.Errorf("expected ErrReconciledPeriod, got %v", err)
+	}
+}
+
+func TestReconciliation_LiabilityAdjustment(t *testing.T) {
+	reconciliation := &domain.Reconciliation{OriginId: "card", StatementDate: day(20)}
+
+	// The bank says more is owed than recorded: the adjustment must raise the debt.
+	adjustment := adjustmentTransaction(reconciliation, 25, true)
+	if adjustment.Type != "Output" || adjustment.Amount != 25 || !adjustment.Reconciled {
+		t.Errorf("adjustment = %+v", adjustment)
+	}
+
+	adjustment = adjustmentTransaction(reconciliation, 25, false)
+	if adjustment.Type != "Income" {
+		t.Errorf("expected an Income adjustment on an asset, got %+v", adjustment)
+	}
+}
+
+func TestTransactionService_ReconciledPeriodLocked(t *testing.T) {
+	through := day(20)
+	oRepo := newMockOriginRepo(map[string]*domain.Origin{
+		"checking": {ID: "checking", Total: 1000, ReconciledThrough: &through},
+	})
+	reconciled := &domain.Transaction{OriginId: strPtr("checking"), Type: "Output", Amount: 10, CreatedAt: day(5), Reconciled: true}
+	tRepo := &mockTransactionRepo{
+		getByIdFunc: func(ctx context.Context, id string) (*domain.Transaction, error) { return reconciled, nil },
+	}
+	ts := newTransactionService(tRepo, oRepo)
+	ctx := context.Background()
+
+	backdated := &domain.Transaction{OriginId: strPtr("checking"), Type: "Output", Amount: 10, CreatedAt: day(20).Add(15 * time.Hour)}
+	if _, err := ts.CreateTransaction(ctx, backdated); err != domain.ErrReconciledPeriod {
+		t.Errorf("expected ErrReconciledPeriod creating on the statement day, got %v", err)
+	}
+
+	current := &domain.Transaction{OriginId: strPtr("checking"), Type: "Output", Amount: 10, CreatedAt: day(21)}
+	if _, err := ts.CreateTransaction(ctx, current); err != nil {
+		t.Errorf("unexpected error after the reconciled period: %v", err)
+	}
+
+	if _, err := ts.UpdateTransaction(ctx, "t1", &domain.Transaction{OriginId: strPtr("checking"), Amount: 20}); err != domain.ErrReconciledPeriod {
+		t.Errorf("expected ErrReconciledPeriod updating, got %v", err)
+	}
+	if err := ts.DeleteTransaction(ctx, "t1"); err != domain.ErrReconciledPeriod {
+		t.Errorf("expected ErrReconciledPeriod deleting, got %v", err)
+	}
+}
@@ -2,6 +2,8 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"personal-finance/core/domain"
 	"personal-finance/core/port"
 	"time"
@@ -12,80 +14,164 @@ type ReportService struct {
 	transactionService port.TransactionService
 	originService      port.OriginService
 	mailAdapter        port.MailReportAdapter
+	renderer           port.ReportRenderer
+	rules              domain.ReportRules
 }
 
 func NewReportService(
 	authService port.AuthService,
 	transactionService port.TransactionService,
 	originService port.OriginService,
-	mailAdapter port.MailReportAdapter) *ReportService {
+	mailAdapter port.MailReportAdapter,
+	renderer port.ReportRenderer,
+	rules domain.ReportRules) *ReportService {
 
 	return &ReportService{
 		authService,
 		transactionService,
 		originService,
 		mailAdapter,
+		renderer,
+		rules,
 	}
 }
 
+// GenerateMonthlyReport mails the user the report of the previous calendar
+// month on demand. Every request queues a new email, unlike SendReport.
 func (rs *ReportService) GenerateMonthlyReport(ctx context.Context, userId string) error {
 
-	var report domain.Report
-	var transactionList []domain.Transaction
-	var page uint64 = 1
-	var limit uint64 = 200
+	period := domain.PreviousMonthPeriod(time.Now())
+
+	report, err := rs.GetReport(ctx, userId, period, false)
+	if err != nil {
+		return err
+	}
+
+	requestId := make([]byte, 8)
+	if _, err := rand.Read(requestId); err != nil {
+		return domain.ErrInternal
+	}
+
+	_, err = rs.mailAdapter.SendMail(ctx, *report, reportMailKey(userId, period)+":"+hex.EncodeToString(requestId))
+
+	return err
+}
+
+// SendReport queues the scheduled report of period for the user. It is queued
+// at most once per user and period; repeated calls return the queued message.
+func (rs *ReportService) SendReport(ctx context.Context, userId string, period domain.ReportPeriod) (*domain.OutboxMessage, error) {
+
+	report, err := rs.GetReport(ctx, userId, period, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return rs.mailAdapter.SendMail(ctx, *report, reportMailKey(userId, period))
+}
+
+func reportMailKey(userId string, period domain.ReportPeriod) string {
+	return "report:" + userId + ":" + period.Start.Format("2006-01-02") + ":" + period.End.Format("2006-01-02")
+}
+
+// GetReportPDF renders the user's report over period as a PDF document.
+func (rs *ReportService) GetReportPDF(ctx context.Context, userId string, period domain.ReportPeriod, includeArchived bool) ([]byte, error) {
+
+	report, err := rs.GetReport(ctx, userId, period, includeArchived)
+	if err != nil {
+		return nil, err
+	}
+
+	document, err := rs.renderer.RenderPDF(*report)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return document, nil
+}
 
-	var now = time.Now()
-	var lastMonth = now.AddDate(0, -1, 0).Month()
+// GetReport computes the user's report over period without sending it.
+// Archived origins are left out of the totals and the origin summary unless
+// includeArchived is set.
+func (rs *ReportService) GetReport(ctx context.Context, userId string, period domain.ReportPeriod, includeArchived bool) (*domain.Report, error) {
+
+	var report domain.Report
 
 	user, err := rs.authService.GetUserById(ctx, userId)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	report.UserId = user.ID
 	report.Username = user.Username
 	report.UserEmail = user.Email
-	report.Month = lastMonth
-	report.Year = now.Year()
+	report.Locale = user.Locale
+	report.Currency = user.Currency
+	report.Month = period.Start.Month()
+	report.Year = period.Start.Year()
+	report.Period = period
 
-	origins, err := rs.originService.GetOriginsByUserId(ctx, user.ID)
+	origins, err := rs.originService.GetOriginsByUserId(ctx, user.ID, true)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
+	origins = reportOrigins(origins, includeArchived)
+
+	report.TotalAssets, report.TotalLiabilities = calculateAssetsAndLiabilities(origins)
 	report.NetBalance = calculateUserTotalNetwork(origins)
 
+	transactionList, err := rs.getTransactionsInPeriod(ctx, user.ID, period)
+	if err != nil {
+		return nil, err
+	}
+
+	filteredTransactions := filterTransactionsByRules(transactionList, rs.rules)
+
+	report.TotalIncome, report.TotalExpenses = calculateIncomeAndExpenses(filteredTransactions)
+	report.OriginSummary = calculateOriginSummary(filteredTransactions, origins)
+	report.CategorySummary = calculateCategorySummary(filteredTransactions)
+	report.IncomeSummary = calculateIncomeSummary(filteredTransactions)
+	report.TagSummary = calculateTagSummary(filteredTransactions)
+
+	return &report, nil
+}
+
+func (rs *ReportService) getTransactionsInPeriod(ctx context.Context, userId string, period domain.ReportPeriod) ([]domain.Transaction, error) {
+
+	var transactionList []domain.Transaction
+	var limit uint64 = 200
+	var cursor string
+
+	filter := domain.TransactionFilter{
+		UserId: userId,
+		From:   &period.Start,
+		To:     &period.End,
+	}
+
 	for {
-		transactions, _, totalPages, err := rs.transactionService.GetTransactionsByDate(ctx, user.ID, page, limit, now.Year(), int(lastMonth))
+		transactions, _, nextCursor, err := rs.transactionService.GetTransactionsByCursor(ctx, filter, cursor, limit)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		transactionList = append(transactionList, transactions...)
 
-		page += 1
-
-		if int(page) > totalPages {
+		if nextCursor == "" {
 			break
 		}
-	}
 
-	filteredTransactions := filterTransactionsByType(transactionList)
-
-	report.TotalIncome, report.TotalExpenses = calculateIncomeAndExpenses(filteredTransactions)
-	report.OriginSummary = calculateOriginSummary(filteredTransactions, origins)
-	report.CategorySummary = calculateCategorySummary(filteredTransactions)
+		cursor = nextCursor
+	}
 
-	return rs.mailAdapter.SendMail(report)
+	return transactionList, nil
 }
 
-func filterTransactionsByType(transactionList []domain.Transaction) []domain.Transaction {
+func filterTransactionsByRules(transactionList []domain.Transaction, rules domain.ReportRules) []domain.Transaction {
 
 	var filteredTransactionList []domain.Transaction
 
 	for _, transaction := range transactionList {
-		if transaction.Subject == "Payment" || transaction.Subject == "Expense" {
+		if rules.Counts(transaction) {
 			filteredTransactionList = append(filteredTransactionList, transaction)
 		}
 	}
@@ -93,17 +179,35 @@ func filterTransactionsByType(transactionList []domain.Transaction) []domain.Tra
 	return filteredTransactionList
 }
 
+// calculateUserTotalNetwork is the user's net worth: assets minus liabilities.
 func calculateUserTotalNetwork(origins []domain.Origin) float64 {
 
 	var totalNetwork float64 = 0
 
 	for _, origin := range origins {
-		totalNetwork += origin.Total
+		totalNetwork += origin.NetWorth()
 	}
 
 	return totalNetwork
 }
 
+func calculateAssetsAndLiabilities(origins []domain.Origin) (float64, float64) {
+
+	var totalAssets float64 = 0
+	var totalLiabilities float64 = 0
+
+	for _, origin := range origins {
+
+		if origin.IsLiability() {
+			totalLiabilities += origin.Total
+		} else {
+			totalAssets += origin.Total
+		}
+	}
+
+	return totalAssets, totalLiabilities
+}
+
 func calculateIncomeAndExpenses(transactions []domain.Transaction) (float64, float64) {
 
 	var totalIncome float64 = 0
@@ -121,6 +225,24 @@ func calculateIncomeAndExpenses(transactions []domain.Transaction) (float64, flo
 	return totalIncome, totalExpenses
 }
 
+// reportOrigins returns the origins a report covers, the archived ones only
+// if includeArchived is set.
+func reportOrigins(origins []domain.Origin, includeArchived bool) []domain.Origin {
+
+	if includeArchived {
+		return origins
+	}
+
+	var listed []domain.Origin
+	for _, origin := range origins {
+		if !origin.IsArchived() {
+			listed = append(listed, origin)
+		}
+	}
+
+	return listed
+}
+
 func calculateOriginSummary(transactions []domain.Transaction, origins []domain.Origin) []domain.OriginSummary {
 
 	incomeMap := make(map[string]float64)
@@ -150,6 +272,8 @@ func calculateOriginSummary(transactions []domain.Transaction, origins []domain.
 	for _, origin := range origins {
 		var originSummary domain.OriginSummary
 		originSummary.OriginName = origin.Name
+		originSummary.Kind = origin.OriginKind()
+		originSummary.Liability = origin.IsLiability()
 		originSummary.OriginBalance = origin.Total
 		originSummary.TotalIncome = incomeMap[origin.ID]
 		originSummary.TotalExpenses = outputMap[origin.ID]
@@ -192,3 +316,81 @@ func calculateCategorySummary(transactions []domain.Transaction) []domain.Catego
 
 	return categorySummaryList
 }
+
+// calculateIncomeSummary groups the income by source, falling back to the
+// subject for transactions recorded without one.
+func calculateIncomeSummary(transactions []domain.Transaction) []domain.IncomeSummary {
+
+	totalMap := make(map[string]float64)
+	countMap := make(map[string]int)
+	var sourceOrder []string
+	var incomeSummaryList []domain.IncomeSummary
+
+	for _, transaction := range transactions {
+
+		if transaction.Type != "Income" {
+			continue
+		}
+
+		source := transaction.IncomeSource
+		if source == "" {
+			source = transaction.Subject
+		}
+
+		if _, exists := totalMap[source]; !exists {
+			sourceOrder = append(sourceOrder, source)
+		}
+
+		totalMap[source] += transaction.Amount
+		countMap[source] += 1
+	}
+
+	for _, source := range sourceOrder {
+		var incomeSummary domain.IncomeSummary
+		incomeSummary.Source = source
+		incomeSummary.TotalIncome = totalMap[source]
+		incomeSummary.Count = countMap[source]
+
+		incomeSummaryList = append(incomeSummaryList, incomeSummary)
+	}
+
+	return incomeSummaryList
+}
+
+func calculateTagSummary(transactions []domain.Transaction) []domain.TagSummary {
+
+	incomeMap := make(map[string]float64)
+	outputMap := make(map[string]float64)
+	countMap := make(map[string]int)
+	var tagOrder []string
+	var tagSummaryList []domain.TagSummary
+
+	for _, transaction := range transactions {
+
+		for _, tag := range transaction.Tags {
+
+			if _, exists := countMap[tag]; !exists {
+				tagOrder = append(tagOrder, tag)
+			}
+
+			if transaction.Type == "Income" {
+				incomeMap[tag] += transaction.Amount
+			} else {
+				outputMap[tag] += transaction.Amount
+			}
+			countMap[tag] += 1
+		}
+	}
+
+	for _, tag := range tagOrder {
+		var tagSummary domain.TagSummary
+		tagSummary.Tag = tag
+		tagSummary.TotalIncome = incomeMap[tag]
+		tagSummary.TotalExpenses = outputMap[tag]
+		tagSummary.Count = countMap[tag]
+
+		tagSummaryList = append(tagSummaryList, tagSummary)
+	}
+
+	return tagSummaryList
+}
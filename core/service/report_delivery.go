@@ -0,0 +1,320 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"personal-finance/core/domain"
+	"personal-finance/core/port"
+	"sync"
+	"time"
+)
+
+const (
+	subscriberBatchSize = 100
+
+	// retryStatus marks a failed delivery that a later run will try again.
+	retryStatus = "retry"
+)
+
+type ReportDeliveryService struct {
+	authRepo      port.AuthRepository
+	deliveryRepo  port.ReportDeliveryRepository
+	reportService port.ReportService
+	outboxService port.OutboxService
+	concurrency   int
+	reportDay     int
+}
+
+func NewReportDeliveryService(
+	authRepo port.AuthRepository,
+	deliveryRepo port.ReportDeliveryRepository,
+	reportService port.ReportService,
+	outboxService port.OutboxService,
+	concurrency int,
+	reportDay int,
+) *ReportDeliveryService {
+
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	if reportDay < 1 {
+		reportDay = 1
+	}
+
+	return &ReportDeliveryService{
+		authRepo,
+		deliveryRepo,
+		reportService,
+		outboxService,
+		concurrency,
+		reportDay,
+	}
+}
+
+// DeliverMonthlyReports mails the previous month's report to every subscribed
+// user, at most concurrency at a time. A month's run only starts on the
+// report day; it returns nil on any other day while none was started. An
+// interrupted run resumes scanning subscribers after the last batch it
+// finished, and a partial one only retries the failed deliveries it recorded,
+// so the run can be repeated safely after a restart or to retry failures. It
+// is a no-op once the month's run completed.
+func (rds *ReportDeliveryService) DeliverMonthlyReports(ctx context.Context, now time.Time) (*domain.ReportRun, error) {
+
+	period := domain.PreviousMonthPeriod(now)
+	key := domain.PeriodKey(period)
+
+	run, err := rds.deliveryRepo.GetRun(ctx, key)
+	if err != nil && err != domain.ErrDataNotFound {
+		return nil, domain.ErrInternal
+	}
+	if run != nil && run.Status == domain.ReportRunCompleted {
+		return run, nil
+	}
+
+	if run == nil {
+		if !isReportDay(now, rds.reportDay) {
+			return nil, nil
+		}
+		run = &domain.ReportRun{
+			Period:    key,
+			Status:    domain.ReportRunRunning,
+			StartedAt: now,
+		}
+		if err := rds.deliveryRepo.SaveRun(ctx, run); err != nil {
+			return nil, domain.ErrInternal
+		}
+	}
+
+	var retryable int
+	if run.Status == domain.ReportRunPartial {
+		retryable, err = rds.retryFailed(ctx, run, period, key)
+	} else {
+		retryable, err = rds.scanSubscribers(ctx, run, period, key)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	finishedAt := time.Now()
+	run.FinishedAt = &finishedAt
+	run.Status = domain.ReportRunCompleted
+	if retryable > 0 {
+		run.Status = domain.ReportRunPartial
+	}
+
+	if err := rds.deliveryRepo.SaveRun(ctx, run); err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return run, nil
+}
+
+// isReportDay reports whether now falls on the month's report day, the last
+// day of the month standing in for days it does not have.
+func isReportDay(now time.Time, reportDay int) bool {
+
+	lastDay := time.Date(now.Year(), now.Month()+1, 0, 0, 0, 0, 0, now.Location()).Day()
+
+	return now.Day() == min(reportDay, lastDay)
+}
+
+// scanSubscribers delivers the report to the subscribers after the run's last
+// finished batch, saving the run after each batch so an interrupted scan
+// resumes there. It returns how many deliveries failed but may be retried.
+func (rds *ReportDeliveryService) scanSubscribers(ctx context.Context, run *domain.ReportRun, period domain.ReportPeriod, key string) (int, error) {
+
+	retryable := 0
+
+	for {
+		users, err := rds.authRepo.GetReportSubscribers(ctx, run.LastUserId, subscriberBatchSize)
+		if err != nil {
+			return 0, domain.ErrInternal
+		}
+
+		userIds := make([]string, 0, len(users))
+		for _, user := range users {
+			userIds = append(userIds, user.ID)
+		}
+
+		retryable += rds.deliverAll(ctx, run, userIds, period, key)
+
+		if len(users) < subscriberBatchSize {
+			return retryable, nil
+		}
+
+		run.LastUserId = users[len(users)-1].ID
+		if err := rds.deliveryRepo.SaveRun(ctx, run); err != nil {
+			return 0, domain.ErrInternal
+		}
+	}
+}
+
+// retryFailed delivers again the reports of the period that failed with
+// attempts left. It returns how many still failed but may be retried.
+func (rds *ReportDeliveryService) retryFailed(ctx context.Context, run *domain.ReportRun, period domain.ReportPeriod, key string) (int, error) {
+
+	deliveries, err := rds.deliveryRepo.GetDeliveriesByPeriod(ctx, key)
+	if err != nil {
+		return 0, domain.ErrInternal
+	}
+
+	var userIds []string
+	for _, delivery := range deliveries {
+		if delivery.MessageId == "" && delivery.Status == domain.DeliveryStatusFailed && delivery.Attempts < domain.MaxReportDeliveryAttempts {
+			userIds = append(userIds, delivery.UserId)
+		}
+	}
+
+	// the retried deliveries were counted as failed by an earlier pass
+	run.Failed -= len(userIds)
+
+	return rds.deliverAll(ctx, run, userIds, period, key), nil
+}
+
+// deliverAll delivers the report to the users, at most concurrency at a time,
+// and counts the outcomes in run. It returns how many failed but may be
+// retried.
+func (rds *ReportDeliveryService) deliverAll(ctx context.Context, run *domain.ReportRun, userIds []string, period domain.ReportPeriod, key string) int {
+
+	var (
+		mu        sync.Mutex
+		wg        sync.WaitGroup
+		retryable int
+	)
+	semaphore := make(chan struct{}, rds.concurrency)
+
+	for _, userId := range userIds {
+		semaphore <- struct{}{}
+		wg.Add(1)
+
+		go func(userId string) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			status := rds.deliver(ctx, userId, period, key)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			switch status {
+			case domain.DeliveryStatusSent:
+				run.Sent++
+			case domain.DeliveryStatusFailed:
+				run.Failed++
+			case retryStatus:
+				run.Failed++
+				retryable++
+			default:
+				run.Skipped++
+			}
+		}(userId)
+	}
+
+	wg.Wait()
+
+	return retryable
+}
+
+// deliver queues one user's report unless it is already in the outbox, and
+// records the outcome. It returns "" when the user was skipped.
+func (rds *ReportDeliveryService) deliver(ctx context.Context, userId string, period domain.ReportPeriod, key string) string {
+
+	delivery, err := rds.deliveryRepo.GetDelivery(ctx, userId, key)
+	if err != nil {
+		if err != domain.ErrDataNotFound {
+			slog.Error("Error reading report delivery", "user_id", userId, "period", key, "error", err)
+			return retryStatus
+		}
+		delivery = &domain.ReportDelivery{UserId: userId, Period: key}
+	}
+
+	// once queued, retries are up to the outbox
+	if delivery.MessageId != "" || delivery.Status == domain.DeliveryStatusSent || delivery.Attempts >= domain.MaxReportDeliveryAttempts {
+		return ""
+	}
+
+	delivery.Attempts++
+	delivery.UpdatedAt = time.Now()
+
+	message, err := rds.reportService.SendReport(ctx, userId, period)
+	if err != nil {
+		delivery.Status = domain.DeliveryStatusFailed
+		delivery.Error = err.Error()
+	} else {
+		followMessage(delivery, message)
+	}
+
+	if err := rds.deliveryRepo.SaveDelivery(ctx, delivery); err != nil {
+		slog.Error("Error saving report delivery", "user_id", userId, "period", key, "error", err)
+	}
+
+	if delivery.MessageId != "" {
+		return domain.DeliveryStatusSent
+	}
+	if delivery.Attempts < domain.MaxReportDeliveryAttempts {
+		return retryStatus
+	}
+	return domain.DeliveryStatusFailed
+}
+
+// GetDeliveryStatus returns the run summary and per-user deliveries of period
+// ("2006-01"). Deliveries still pending in the outbox are refreshed from it.
+func (rds *ReportDeliveryService) GetDeliveryStatus(ctx context.Context, period string) (*domain.ReportDeliveryStatus, error) {
+
+	if _, err := time.Parse("2006-01", period); err != nil {
+		return nil, domain.ErrInvalidPeriod
+	}
+
+	run, err := rds.deliveryRepo.GetRun(ctx, period)
+	if err != nil && err != domain.ErrDataNotFound {
+		return nil, domain.ErrInternal
+	}
+
+	deliveries, err := rds.deliveryRepo.GetDeliveriesByPeriod(ctx, period)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	for i := range deliveries {
+		rds.refreshDelivery(ctx, &deliveries[i])
+	}
+
+	return &domain.ReportDeliveryStatus{
+		Run:        run,
+		Deliveries: deliveries,
+	}, nil
+}
+
+// refreshDelivery brings a pending delivery up to date with its outbox message
+// and saves it when the message moved on.
+func (rds *ReportDeliveryService) refreshDelivery(ctx context.Context, delivery *domain.ReportDelivery) {
+
+	if delivery.MessageId == "" || delivery.Status != domain.OutboxPending {
+		return
+	}
+
+	message, err := rds.outboxService.GetMessageById(ctx, delivery.MessageId)
+	if err != nil {
+		slog.Error("Error reading report outbox message", "user_id", delivery.UserId, "period", delivery.Period, "error", err)
+		return
+	}
+
+	if message.Status == delivery.Status {
+		return
+	}
+
+	delivery.UpdatedAt = time.Now()
+	followMessage(delivery, message)
+
+	if err := rds.deliveryRepo.SaveDelivery(ctx, delivery); err != nil {
+		slog.Error("Error saving report delivery", "user_id", delivery.UserId, "period", delivery.Period, "error", err)
+	}
+}
+
+func followMessage(delivery *domain.ReportDelivery, message *domain.OutboxMessage) {
+	delivery.MessageId = message.ID
+	delivery.Status = message.Status
+	delivery.Error = message.LastError
+	delivery.SentAt = message.SentAt
+}
@@ -0,0 +1,359 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"personal-finance/core/domain"
+)
+
+// --- mocks ---
+
+type mockSubscriberRepo struct {
+	users   []domain.User
+	scanned []string
+}
+
+func (m *mockSubscriberRepo) GetUserById(ctx context.Context, id string) (*domain.User, error) {
+	return nil, domain.ErrDataNotFound
+}
+
+func (m *mockSubscriberRepo) GetUserByEmail(ctx context.Context, email string) (*domain.User, error) {
+	return nil, domain.ErrDataNotFound
+}
+
+func (m *mockSubscriberRepo) GetReportSubscribers(ctx context.Context, afterId string, limit int64) ([]domain.User, error) {
+	m.scanned = append(m.scanned, afterId)
+	var users []domain.User
+	for _, user := range m.users {
+		if user.ID > afterId && int64(len(users)) < limit {
+			users = append(users, user)
+		}
+	}
+	return users, nil
+}
+
+func (m *mockSubscriberRepo) CreateUser(ctx context.Context, user *domain.User) (*domain.User, error) {
+	return user, nil
+}
+
+func (m *mockSubscriberRepo) UpdateUser(ctx context.Context, id string, user *domain.User) (*domain.User, error) {
+	return user, nil
+}
+
+func (m *mockSubscriberRepo) UpdatePreferences(ctx context.Context, id string, preferences domain.UserPreferences) error {
+	return nil
+}
+
+func (m *mockSubscriberRepo) DeleteUser(ctx context.Context, id string) error {
+	return nil
+}
+
+type mockDeliveryRepo struct {
+	mu         sync.Mutex
+	deliveries map[string]domain.ReportDelivery
+	runs       map[string]domain.ReportRun
+}
+
+func newMockDeliveryRepo() *mockDeliveryRepo {
+	return &mockDeliveryRepo{
+		deliveries: map[string]domain.ReportDelivery{},
+		runs:       map[string]domain.ReportRun{},
+	}
+}
+
+func (m *mockDeliveryRepo) GetDelivery(ctx context.Context, userId string, period string) (*domain.ReportDelivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delivery, ok := m.deliveries[userId+period]
+	if !ok {
+		return nil, domain.ErrDataNotFound
+	}
+	return &delivery, nil
+}
+
+func (m *mockDeliveryRepo) GetDeliveriesByPeriod(ctx context.Context, period string) ([]domain.ReportDelivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var deliveries []domain.ReportDelivery
+	for _, delivery := range m.deliveries {
+		if delivery.Period == period {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	return deliveries, nil
+}
+
+func (m *mockDeliveryRepo) SaveDelivery(ctx context.Context, delivery *domain.ReportDelivery) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.deliveries[delivery.UserId+delivery.Period] = *delivery
+	return nil
+}
+
+func (m *mockDeliveryRepo) GetRun(ctx context.Context, period string) (*domain.ReportRun, error) {
+	run, ok := m.runs[period]
+	if !ok {
+		return nil, domain.ErrDataNotFound
+	}
+	return &run, nil
+}
+
+func (m *mockDeliveryRepo) SaveRun(ctx context.Context, run *domain.ReportRun) error {
+	m.runs[run.Period] = *run
+	return nil
+}
+
+type mockReportSender struct {
+	mu      sync.Mutex
+	sent    map[string]int
+	failFor map[string]bool
+}
+
+func (m *mockReportSender) GetReport(ctx context.Context, userId string, period domain.ReportPeriod, includeArchived bool) (*domain.Report, error) {
+	return &domain.Report{UserId: userId}, nil
+}
+
+func (m *mockReportSender) GetReportPDF(ctx context.Context, userId string, period domain.ReportPeriod, includeArchived bool) ([]byte, error) {
+	return nil, nil
+}
+
+func (m *mockReportSender) GenerateMonthlyReport(ctx context.Context, userId string) error {
+	return nil
+}
+
+func (m *mockReportSender) SendReport(ctx context.Context, userId string, period domain.ReportPeriod) (*domain.OutboxMessage, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.failFor[userId] {
+		return nil, errors.New("smtp unavailable")
+	}
+	m.sent[userId]++
+	return &domain.OutboxMessage{ID: "m-" + userId, Status: domain.OutboxPending}, nil
+}
+
+type mockOutboxService struct {
+	messages map[string]*domain.OutboxMessage
+}
+
+func (m *mockOutboxService) GetMessages(ctx context.Context, status string, page, limit uint64) ([]domain.OutboxMessage, int64, int, error) {
+	return nil, 0, 0, nil
+}
+
+func (m *mockOutboxService) GetMessageById(ctx context.Context, id string) (*domain.OutboxMessage, error) {
+	message, ok := m.messages[id]
+	if !ok {
+		return nil, domain.ErrDataNotFound
+	}
+	return message, nil
+}
+
+func (m *mockOutboxService) EnqueueMessage(ctx context.Context, message *domain.OutboxMessage) (*domain.OutboxMessage, error) {
+	return message, nil
+}
+
+func (m *mockOutboxService) ProcessDueMessages(ctx context.Context, now time.Time) (int, error) {
+	return 0, nil
+}
+
+func (m *mockOutboxService) ResendMessage(ctx context.Context, id string) (*domain.OutboxMessage, error) {
+	return nil, domain.ErrDataNotFound
+}
+
+// --- DeliverMonthlyReports ---
+
+var deliveryNow = time.Date(2026, time.October, 1, 8, 0, 0, 0, time.UTC)
+
+func TestDeliverMonthlyReports_RestartDoesNotSendTwice(t *testing.T) {
+	users := &mockSubscriberRepo{users: []domain.User{{ID: "a"}, {ID: "b"}, {ID: "c"}}}
+	deliveries := newMockDeliveryRepo()
+	sender := &mockReportSender{sent: map[string]int{}}
+
+	// a previous process sent "a" and died before finishing the run
+	deliveries.deliveries["a2026-09"] = domain.ReportDelivery{UserId: "a", Period: "2026-09", Status: domain.DeliveryStatusSent, Attempts: 1}
+	deliveries.runs["2026-09"] = domain.ReportRun{Period: "2026-09", Status: domain.ReportRunRunning}
+
+	service := NewReportDeliveryService(users, deliveries, sender, &mockOutboxService{}, 2, 1)
+
+	run, err := service.DeliverMonthlyReports(context.Background(), deliveryNow)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if run.Status != domain.ReportRunCompleted || run.Sent != 2 || run.Skipped != 1 {
+		t.Errorf("unexpected run %+v", run)
+	}
+	if sender.sent["a"] != 0 || sender.sent["b"] != 1 || sender.sent["c"] != 1 {
+		t.Errorf("unexpected sends %v", sender.sent)
+	}
+
+	// completed runs are not repeated
+	if _, err := service.DeliverMonthlyReports(context.Background(), deliveryNow.Add(time.Hour)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sender.sent["b"] != 1 {
+		t.Errorf("expected completed run to be skipped, got %v", sender.sent)
+	}
+}
+
+func TestDeliverMonthlyReports_RetriesFailuresUpToMaxAttempts(t *testing.T) {
+	users := &mockSubscriberRepo{users: []domain.User{{ID: "a"}}}
+	deliveries := newMockDeliveryRepo()
+	sender := &mockReportSender{sent: map[string]int{}, failFor: map[string]bool{"a": true}}
+
+	service := NewReportDeliveryService(users, deliveries, sender, &mockOutboxService{}, 1, 1)
+
+	for i := 1; i <= domain.MaxReportDeliveryAttempts; i++ {
+		run, err := service.DeliverMonthlyReports(context.Background(), deliveryNow)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		expected := domain.ReportRunPartial
+		if i == domain.MaxReportDeliveryAttempts {
+			expected = domain.ReportRunCompleted
+		}
+		if run.Status != expected {
+			t.Errorf("attempt %d: expected status %s, got %s", i, expected, run.Status)
+		}
+	}
+
+	delivery := deliveries.deliveries["a2026-09"]
+	if delivery.Status != domain.DeliveryStatusFailed || delivery.Attempts != domain.MaxReportDeliveryAttempts {
+		t.Errorf("unexpected delivery %+v", delivery)
+	}
+}
+
+func TestDeliverMonthlyReports_RecordsOutboxStatus(t *testing.T) {
+	users := &mockSubscriberRepo{users: []domain.User{{ID: "a"}}}
+	deliveries := newMockDeliveryRepo()
+	sender := &mockReportSender{sent: map[string]int{}}
+	outbox := &mockOutboxService{messages: map[string]*domain.OutboxMessage{}}
+
+	service := NewReportDeliveryService(users, deliveries, sender, outbox, 1, 1)
+
+	if _, err := service.DeliverMonthlyReports(context.Background(), deliveryNow); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// queued is not sent yet
+	delivery := deliveries.deliveries["a2026-09"]
+	if delivery.Status != domain.OutboxPending || delivery.MessageId != "m-a" || delivery.SentAt != nil {
+		t.Fatalf("expected a pending delivery, got %+v", delivery)
+	}
+
+	sentAt := deliveryNow.Add(time.Minute)
+	outbox.messages["m-a"] = &domain.OutboxMessage{ID: "m-a", Status: domain.OutboxSent, SentAt: &sentAt}
+
+	status, err := service.GetDeliveryStatus(context.Background(), "2026-09")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(status.Deliveries) != 1 || status.Deliveries[0].Status != domain.OutboxSent {
+		t.Errorf("expected the delivery to follow the outbox, got %+v", status.Deliveries)
+	}
+	if saved := deliveries.deliveries["a2026-09"]; saved.Status != domain.OutboxSent || saved.SentAt == nil {
+		t.Errorf("expected the refreshed delivery saved, got %+v", saved)
+	}
+}
+
+func TestDeliverMonthlyReports_LeavesQueuedReportsToTheOutbox(t *testing.T) {
+	users := &mockSubscriberRepo{users: []domain.User{{ID: "a"}}}
+	deliveries := newMockDeliveryRepo()
+	sender := &mockReportSender{sent: map[string]int{}}
+
+	// the outbox gave up on the message; resending it is the outbox's job
+	deliveries.deliveries["a2026-09"] = domain.ReportDelivery{UserId: "a", Period: "2026-09", Status: domain.OutboxDead, MessageId: "m-a", Attempts: 1}
+
+	service := NewReportDeliveryService(users, deliveries, sender, &mockOutboxService{}, 1, 1)
+
+	run, err := service.DeliverMonthlyReports(context.Background(), deliveryNow)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if run.Skipped != 1 || sender.sent["a"] != 0 {
+		t.Errorf("expected the queued report skipped, got run %+v and sends %v", run, sender.sent)
+	}
+}
+
+func TestDeliverMonthlyReports_WaitsForTheReportDay(t *testing.T) {
+	users := &mockSubscriberRepo{users: []domain.User{{ID: "a"}}}
+	deliveries := newMockDeliveryRepo()
+	sender := &mockReportSender{sent: map[string]int{}}
+
+	service := NewReportDeliveryService(users, deliveries, sender, &mockOutboxService{}, 1, 1)
+
+	// deployed mid-month: the previous month's run is not started
+	run, err := service.DeliverMonthlyReports(context.Background(), time.Date(2026, time.October, 20, 8, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if run != nil || len(deliveries.runs) != 0 || sender.sent["a"] != 0 {
+		t.Errorf("expected nothing delivered before the report day, got run %+v and sends %v", run, sender.sent)
+	}
+}
+
+func TestIsReportDay_ClampsToTheLastDay(t *testing.T) {
+	if !isReportDay(time.Date(2026, time.February, 28, 0, 0, 0, 0, time.UTC), 31) {
+		t.Error("expected the last day of February to stand in for the 31st")
+	}
+	if isReportDay(time.Date(2026, time.March, 28, 0, 0, 0, 0, time.UTC), 31) {
+		t.Error("expected March to wait for the 31st")
+	}
+}
+
+func TestDeliverMonthlyReports_PartialRunRetriesRecordedFailures(t *testing.T) {
+	users := &mockSubscriberRepo{users: []domain.User{{ID: "a"}, {ID: "b"}}}
+	deliveries := newMockDeliveryRepo()
+	sender := &mockReportSender{sent: map[string]int{}}
+
+	deliveries.deliveries["a2026-09"] = domain.ReportDelivery{UserId: "a", Period: "2026-09", Status: domain.OutboxPending, MessageId: "m-a", Attempts: 1}
+	deliveries.deliveries["b2026-09"] = domain.ReportDelivery{UserId: "b", Period: "2026-09", Status: domain.DeliveryStatusFailed, Attempts: 1}
+	deliveries.runs["2026-09"] = domain.ReportRun{Period: "2026-09", Status: domain.ReportRunPartial, Sent: 1, Failed: 1}
+
+	service := NewReportDeliveryService(users, deliveries, sender, &mockOutboxService{}, 1, 1)
+
+	// retries go on past the report day
+	run, err := service.DeliverMonthlyReports(context.Background(), deliveryNow.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(users.scanned) != 0 {
+		t.Errorf("expected no subscriber scan, got %v", users.scanned)
+	}
+	if run.Status != domain.ReportRunCompleted || run.Sent != 2 || run.Failed != 0 {
+		t.Errorf("unexpected run %+v", run)
+	}
+	if sender.sent["a"] != 0 || sender.sent["b"] != 1 {
+		t.Errorf("expected only b retried, got %v", sender.sent)
+	}
+}
+
+func TestDeliverMonthlyReports_ResumesScanAfterLastBatch(t *testing.T) {
+	users := &mockSubscriberRepo{users: []domain.User{{ID: "a"}, {ID: "b"}, {ID: "c"}}}
+	deliveries := newMockDeliveryRepo()
+	sender := &mockReportSender{sent: map[string]int{}}
+
+	deliveries.runs["2026-09"] = domain.ReportRun{Period: "2026-09", Status: domain.ReportRunRunning, Sent: 2, LastUserId: "b"}
+
+	service := NewReportDeliveryService(users, deliveries, sender, &mockOutboxService{}, 1, 1)
+
+	run, err := service.DeliverMonthlyReports(context.Background(), deliveryNow.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(users.scanned) != 1 || users.scanned[0] != "b" {
+		t.Errorf("expected the scan to resume after b, got %v", users.scanned)
+	}
+	if run.Status != domain.ReportRunCompleted || run.Sent != 3 || sender.sent["c"] != 1 || sender.sent["a"] != 0 {
+		t.Errorf("unexpected run %+v and sends %v", run, sender.sent)
+	}
+}
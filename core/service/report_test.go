@@ -0,0 +1,169 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"personal-finance/core/domain"
+)
+
+func TestPreviousMonthPeriod_JanuaryRollsBackTheYear(t *testing.T) {
+	period := domain.PreviousMonthPeriod(time.Date(2026, time.January, 1, 6, 0, 0, 0, time.UTC))
+
+	if period.Start != time.Date(2025, time.December, 1, 0, 0, 0, 0, time.UTC) {
+		t.Errorf("expected start 2025-12-01, got %v", period.Start)
+	}
+	if period.End != time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC) {
+		t.Errorf("expected end 2026-01-01, got %v", period.End)
+	}
+}
+
+func TestPreviousMonthPeriod_EndOfMonthDoesNotSkipFebruary(t *testing.T) {
+	// AddDate(0, -1, 0) on March 31st normalizes to March 3rd
+	period := domain.PreviousMonthPeriod(time.Date(2026, time.March, 31, 12, 0, 0, 0, time.UTC))
+
+	if period.Start.Month() != time.February || period.Start.Year() != 2026 {
+		t.Errorf("expected February 2026, got %v", period.Start)
+	}
+}
+
+func TestQuarterPeriod(t *testing.T) {
+	period, err := domain.QuarterPeriod(2026, 4)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if period.Start != time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC) ||
+		period.End != time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC) {
+		t.Errorf("unexpected Q4 bounds %v - %v", period.Start, period.End)
+	}
+
+	if _, err := domain.QuarterPeriod(2026, 5); err != domain.ErrInvalidPeriod {
+		t.Errorf("expected ErrInvalidPeriod, got %v", err)
+	}
+}
+
+func TestCustomPeriod_IncludesLastDay(t *testing.T) {
+	from := time.Date(2026, time.March, 10, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, time.March, 20, 0, 0, 0, 0, time.UTC)
+
+	period, err := domain.CustomPeriod(from, to)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if period.End != time.Date(2026, time.March, 21, 0, 0, 0, 0, time.UTC) {
+		t.Errorf("expected exclusive end 2026-03-21, got %v", period.End)
+	}
+
+	if _, err := domain.CustomPeriod(to, from); err != domain.ErrInvalidPeriod {
+		t.Errorf("expected ErrInvalidPeriod for reversed range, got %v", err)
+	}
+}
+
+func TestFilterTransactionsByRules_ConfiguredSubjects(t *testing.T) {
+	transactions := []domain.Transaction{
+		{Type: "Income", Subject: "Salary", Amount: 100},
+		{Type: "Income", Subject: "Transfer", Amount: 50},
+		{Type: "Output", Subject: "expense", Amount: 30},
+		{Type: "Output", Subject: "Transfer", Amount: 20},
+	}
+
+	rules := domain.ReportRules{
+		IncomeSubjects:  []string{"Salary"},
+		ExpenseSubjects: []string{"Expense"},
+	}
+
+	filtered := filterTransactionsByRules(transactions, rules)
+	if len(filtered) != 2 || filtered[0].Subject != "Salary" || filtered[1].Subject != "expense" {
+		t.Errorf("unexpected transactions %+v", filtered)
+	}
+
+	rules.IncomeSubjects = []string{domain.AnySubject}
+	if filtered := filterTransactionsByRules(transactions, rules); len(filtered) != 3 {
+		t.Errorf("expected every income to count, got %d transactions", len(filtered))
+	}
+}
+
+func TestDefaultReportRules_EveryIncomeAndExpenseOutputs(t *testing.T) {
+	rules := domain.DefaultReportRules()
+
+	if !rules.Counts(domain.Transaction{Type: "Income", Subject: "Payment"}) ||
+		!rules.Counts(domain.Transaction{Type: "Income", Subject: "Salary"}) {
+		t.Error("expected every income to count by default")
+	}
+	if !rules.Counts(domain.Transaction{Type: "Output", Subject: "Expense"}) {
+		t.Error("expected Expense outputs to count by default")
+	}
+	if rules.Counts(domain.Transaction{Type: "Output", Subject: "Transfer"}) {
+		t.Error("expected other output subjects to be left out by default")
+	}
+}
+
+func TestCalculateIncomeSummary_GroupsBySource(t *testing.T) {
+	summary := calculateIncomeSummary([]domain.Transaction{
+		{Type: "Income", Subject: "Payment", IncomeSource: "Salary", Amount: 1000},
+		{Type: "Income", Subject: "Payment", IncomeSource: "Freelance", Amount: 300},
+		{Type: "Income", Subject: "Payment", IncomeSource: "Salary", Amount: 500},
+		{Type: "Income", Subject: "Payment", Amount: 20},
+		{Type: "Output", Subject: "Expense", OutputCategory: "Food", Amount: 80},
+	})
+
+	expected := []domain.IncomeSummary{
+		{Source: "Salary", TotalIncome: 1500, Count: 2},
+		{Source: "Freelance", TotalIncome: 300, Count: 1},
+		{Source: "Payment", TotalIncome: 20, Count: 1},
+	}
+
+	if len(summary) != len(expected) {
+		t.Fatalf("expected %d sources, got %+v", len(expected), summary)
+	}
+	for i := range expected {
+		if summary[i] != expected[i] {
+			t.Errorf("source %d: expected %+v, got %+v", i, expected[i], summary[i])
+		}
+	}
+}
+
+func TestCalculateUserTotalNetwork_SubtractsLiabilities(t *testing.T) {
+	origins := []domain.Origin{
+		{Name: "Checking", Total: 1000},
+		{Name: "Savings", Kind: domain.OriginSavings, Total: 500},
+		{Name: "Card", Kind: domain.OriginCreditCard, Total: 300},
+		{Name: "Car", Kind: domain.OriginLoan, Total: 700},
+	}
+
+	if got := calculateUserTotalNetwork(origins); got != 500 {
+		t.Errorf("expected net worth 500, got %v", got)
+	}
+
+	assets, liabilities := calculateAssetsAndLiabilities(origins)
+	if assets != 1500 || liabilities != 1000 {
+		t.Errorf("expected assets 1500 and liabilities 1000, got %v and %v", assets, liabilities)
+	}
+}
+
+func TestReportOrigins_HidesArchived(t *testing.T) {
+	archivedAt := time.Now()
+	origins := []domain.Origin{
+		{ID: "o1", Name: "Checking", Total: 1000},
+		{ID: "o2", Name: "Old bank", Total: 250, ArchivedAt: &archivedAt},
+	}
+
+	listed := reportOrigins(origins, false)
+
+	summary := calculateOriginSummary(nil, listed)
+	if len(summary) != 1 || summary[0].OriginName != "Checking" {
+		t.Errorf("expected only Checking in the summary, got %+v", summary)
+	}
+	if assets, _ := calculateAssetsAndLiabilities(listed); assets != 1000 {
+		t.Errorf("expected the archived balance left out of the assets, got %v", assets)
+	}
+	if net := calculateUserTotalNetwork(listed); net != 1000 {
+		t.Errorf("expected the archived balance left out of the net balance, got %v", net)
+	}
+
+	if got := reportOrigins(origins, true); len(got) != 2 {
+		t.Errorf("expected both origins with include archived, got %d", len(got))
+	}
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"personal-finance/core/domain"
+	"personal-finance/core/port"
+	"sort"
+	"time"
+)
+
+// maxNetWorthDays bounds the range of a net worth history request.
+const maxNetWorthDays = 731
+
+const snapshotDateFormat = "2006-01-02"
+
+type SnapshotService struct {
+	repo       port.SnapshotRepository
+	originRepo port.OriginRepository
+}
+
+func NewSnapshotService(repo port.SnapshotRepository, originRepo port.OriginRepository) *SnapshotService {
+
+	return &SnapshotService{
+		repo,
+		originRepo,
+	}
+}
+
+// RecordBalance stores the origin's current balance as its snapshot of today.
+// Snapshots hold the origin's net worth, so liabilities are recorded negative.
+func (ss *SnapshotService) RecordBalance(ctx context.Context, origin *domain.Origin) error {
+
+	now := time.Now()
+
+	err := ss.repo.SaveSnapshot(ctx, &domain.BalanceSnapshot{
+		UserId:    origin.UserId,
+		OriginId:  origin.ID,
+		Date:      domain.SnapshotDay(now),
+		Balance:   origin.NetWorth(),
+		UpdatedAt: now,
+	})
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+// SnapshotAllOrigins records the balance every origin has at now.
+func (ss *SnapshotService) SnapshotAllOrigins(ctx context.Context, now time.Time) error {
+
+	if err := ss.repo.SnapshotAllOrigins(ctx, domain.SnapshotDay(now)); err != nil {
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+// BackfillSnapshots rebuilds the missing daily snapshots of the user's origins
+// by walking back from their current balance through the transactions of each
+// day. Snapshots already recorded are kept. It returns how many were added.
+func (ss *SnapshotService) BackfillSnapshots(ctx context.Context, userId string) (int, error) {
+
+	origins, err := ss.originRepo.GetOriginsByUserId(ctx, userId, true)
+	if err != nil {
+		return 0, domain.ErrInternal
+	}
+
+	nets, err := ss.repo.GetDailyOriginNet(ctx, userId)
+	if err != nil {
+		return 0, domain.ErrInternal
+	}
+
+	netsByOrigin := make(map[string][]domain.DailyOriginNet)
+	for _, net := range nets {
+		netsByOrigin[net.OriginId] = append(netsByOrigin[net.OriginId], net)
+	}
+
+	now := time.Now()
+	today := domain.SnapshotDay(now)
+	inserted := 0
+
+	for _, origin := range origins {
+
+		snapshots := backfillOrigin(origin, netsByOrigin[origin.ID], today, now)
+
+		count, err := ss.repo.InsertMissingSnapshots(ctx, snapshots)
+		if err != nil {
+			return inserted, domain.ErrInternal
+		}
+		inserted += count
+	}
+
+	return inserted, nil
+}
+
+// backfillOrigin returns one snapshot per day from the origin's first
+// movement (or creation) through today.
+func backfillOrigin(origin domain.Origin, nets []domain.DailyOriginNet, today, now time.Time) []domain.BalanceSnapshot {
+
+	netByDay := make(map[time.Time]float64, len(nets))
+	first := today
+
+	if !origin.CreatedAt.IsZero() && domain.SnapshotDay(origin.CreatedAt).Before(first) {
+		first = domain.SnapshotDay(origin.CreatedAt)
+	}
+
+	for _, net := range nets {
+		day := domain.SnapshotDay(net.Date)
+		netByDay[day] += net.Net
+		if day.Before(first) {
+			first = day
+		}
+	}
+
+	days := int(today.Sub(first).Hours()/24) + 1
+	snapshots := make([]domain.BalanceSnapshot, days)
+	balance := origin.NetWorth()
+
+	for i := days - 1; i >= 0; i-- {
+		day := first.AddDate(0, 0, i)
+
+		snapshots[i] = domain.BalanceSnapshot{
+			UserId:    origin.UserId,
+			OriginId:  origin.ID,
+			Date:      day,
+			Balance:   balance,
+			UpdatedAt: now,
+		}
+
+		balance -= netByDay[day]
+	}
+
+	return snapshots
+}
+
+// GetNetWorthHistory returns the user's net worth at the end of every day from
+// from through to. Days without a snapshot carry the previous balance forward.
+func (ss *SnapshotService) GetNetWorthHistory(ctx context.Context, userId string, from, to time.Time) ([]domain.NetWorthPoint, error) {
+
+	from = domain.SnapshotDay(from)
+	to = domain.SnapshotDay(to)
+
+	if to.Before(from) || to.Sub(from).Hours()/24 >= maxNetWorthDays {
+		return nil, domain.ErrInvalidPeriod
+	}
+
+	previous, err := ss.repo.GetLatestSnapshotsBefore(ctx, userId, from)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	snapshots, err := ss.repo.GetSnapshots(ctx, userId, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return netWorthSeries(previous, snapshots, from, to), nil
+}
+
+func netWorthSeries(previous, snapshots []domain.BalanceSnapshot, from, to time.Time) []domain.NetWorthPoint {
+
+	balances := make(map[string]float64)
+	for _, snapshot := range previous {
+		balances[snapshot.OriginId] = snapshot.Balance
+	}
+
+	var series []domain.NetWorthPoint
+	next := 0
+
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+
+		for next < len(snapshots) && !domain.SnapshotDay(snapshots[next].Date).After(day) {
+			balances[snapshots[next].OriginId] = snapshots[next].Balance
+			next++
+		}
+
+		point := domain.NetWorthPoint{
+			Date:    day.Format(snapshotDateFormat),
+			Origins: make([]domain.OriginBalance, 0, len(balances)),
+		}
+
+		for originId, balance := range balances {
+			point.NetWorth += balance
+			point.Origins = append(point.Origins, domain.OriginBalance{OriginId: originId, Balance: balance})
+		}
+
+		sort.Slice(point.Origins, func(i, j int) bool {
+			return point.Origins[i].OriginId < point.Origins[j].OriginId
+		})
+
+		series = append(series, point)
+	}
+
+	return series
+}
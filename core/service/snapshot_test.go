@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"personal-finance/core/domain"
+	"testing"
+	"time"
+)
+
+type snapshotKey struct {
+	originId string
+	date     time.Time
+}
+
+type mockSnapshotRepo struct {
+	snapshots map[snapshotKey]domain.BalanceSnapshot
+	nets      []domain.DailyOriginNet
+}
+
+func newMockSnapshotRepo() *mockSnapshotRepo {
+	return &mockSnapshotRepo{snapshots: make(map[snapshotKey]domain.BalanceSnapshot)}
+}
+
+func (m *mockSnapshotRepo) SaveSnapshot(ctx context.Context, snapshot *domain.BalanceSnapshot) error {
+	m.snapshots[snapshotKey{snapshot.OriginId, snapshot.Date}] = *snapshot
+	return nil
+}
+
+func (m *mockSnapshotRepo) InsertMissingSnapshots(ctx context.Context, snapshots []domain.BalanceSnapshot) (int, error) {
+	inserted := 0
+	for _, snapshot := range snapshots {
+		key := snapshotKey{snapshot.OriginId, snapshot.Date}
+		if _, exists := m.snapshots[key]; !exists {
+			m.snapshots[key] = snapshot
+			inserted++
+		}
+	}
+	return inserted, nil
+}
+
+func (m *mockSnapshotRepo) SnapshotAllOrigins(ctx context.Context, day time.Time) error {
+	return nil
+}
+
+func (m *mockSnapshotRepo) GetSnapshots(ctx context.Context, userId string, from, to time.Time) ([]domain.BalanceSnapshot, error) {
+	return nil, nil
+}
+
+func (m *mockSnapshotRepo) GetLatestSnapshotsBefore(ctx context.Context, userId string, day time.Time) ([]domain.BalanceSnapshot, error) {
+	return nil, nil
+}
+
+func (m *mockSnapshotRepo) GetDailyOriginNet(ctx context.Context, userId string) ([]domain.DailyOriginNet, error) {
+	return m.nets, nil
+}
+
+func TestUpdateTotalOrigin_RecordsSnapshot(t *testing.T) {
+	oRepo := newMockOriginRepo(map[string]*domain.Origin{
+		"o1": {ID: "o1", UserId: "u1", Total: 100},
+	})
+	sRepo := newMockSnapshotRepo()
+	svc := NewTransactionService(&mockTransactionRepo{}, oRepo, noopTxManager{}, nil, NewSnapshotService(sRepo, oRepo), NewLedgerService(&mockLedgerRepo{}, oRepo, &mockTransactionRepo{}, noopTxManager{}), NewAuditService(&mockAuditRepo{}))
+
+	if err := svc.UpdateTotalOrigin(context.Background(), "o1", "Output", 30); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	snapshot, ok := sRepo.snapshots[snapshotKey{"o1", domain.SnapshotDay(time.Now())}]
+	if !ok {
+		t.Fatal("expected a snapshot of today")
+	}
+	if snapshot.Balance != 70 || snapshot.UserId != "u1" {
+		t.Errorf("snapshot = %+v, want balance 70 of u1", snapshot)
+	}
+}
+
+func TestBackfillOrigin(t *testing.T) {
+	today := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+	origin := domain.Origin{ID: "o1", UserId: "u1", Total: 150, CreatedAt: today.AddDate(0, 0, -3)}
+	nets := []domain.DailyOriginNet{
+		{OriginId: "o1", Date: today.AddDate(0, 0, -2), Net: 100},
+		{OriginId: "o1", Date: today, Net: -50},
+	}
+
+	snapshots := backfillOrigin(origin, nets, today, today)
+
+	want := []float64{100, 200, 200, 150}
+	if len(snapshots) != len(want) {
+		t.Fatalf("got %d snapshots, want %d", len(snapshots), len(want))
+	}
+	for i, balance := range want {
+		if snapshots[i].Balance != balance {
+			t.Errorf("day %d balance = %v, want %v", i, snapshots[i].Balance, balance)
+		}
+		if !snapshots[i].Date.Equal(origin.CreatedAt.AddDate(0, 0, i)) {
+			t.Errorf("day %d date = %v", i, snapshots[i].Date)
+		}
+	}
+}
+
+func TestBackfillSnapshots_KeepsRecorded(t *testing.T) {
+	today := domain.SnapshotDay(time.Now())
+	oRepo := newMockOriginRepo(map[string]*domain.Origin{
+		"o1": {ID: "o1", UserId: "u1", Total: 80, CreatedAt: today.AddDate(0, 0, -1)},
+	})
+	sRepo := newMockSnapshotRepo()
+	sRepo.snapshots[snapshotKey{"o1", today}] = domain.BalanceSnapshot{OriginId: "o1", Date: today, Balance: 999}
+	svc := NewSnapshotService(sRepo, oRepo)
+
+	inserted, err := svc.BackfillSnapshots(context.Background(), "u1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if inserted != 1 {
+		t.Errorf("inserted = %d, want 1", inserted)
+	}
+	if sRepo.snapshots[snapshotKey{"o1", today}].Balance != 999 {
+		t.Error("recorded snapshot was overwritten")
+	}
+}
+
+func TestNetWorthSeries_CarriesForward(t *testing.T) {
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	previous := []domain.BalanceSnapshot{
+		{OriginId: "o1", Date: from.AddDate(0, 0, -5), Balance: 100},
+	}
+	snapshots := []domain.BalanceSnapshot{
+		{OriginId: "o2", Date: from.AddDate(0, 0, 1), Balance: 50},
+		{OriginId: "o1", Date: from.AddDate(0, 0, 2), Balance: 40},
+	}
+
+	series := netWorthSeries(previous, snapshots, from, from.AddDate(0, 0, 3))
+
+	want := []float64{100, 150, 90, 90}
+	if len(series) != len(want) {
+		t.Fatalf("got %d points, want %d", len(series), len(want))
+	}
+	for i, netWorth := range want {
+		if series[i].NetWorth != netWorth {
+			t.Errorf("%s net worth = %v, want %v", series[i].Date, series[i].NetWorth, netWorth)
+		}
+	}
+	if series[0].Date != "2024-03-01" {
+		t.Errorf("first date = %s", series[0].Date)
+	}
+}
+
+func TestGetNetWorthHistory_InvalidRange(t *testing.T) {
+	svc := NewSnapshotService(newMockSnapshotRepo(), newMockOriginRepo(nil))
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	if _, err := svc.GetNetWorthHistory(context.Background(), "u1", from, from.AddDate(0, 0, -1)); err != domain.ErrInvalidPeriod {
+		t.Errorf("err = %v, want ErrInvalidPeriod", err)
+	}
+	if _, err := svc.GetNetWorthHistory(context.Background(), "u1", from, from.AddDate(0, 0, maxNetWorthDays)); err != domain.ErrInvalidPeriod {
+		t.Errorf("err = %v, want ErrInvalidPeriod", err)
+	}
+}
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"math"
+	"personal-finance/core/domain"
+	"personal-finance/core/port"
+	"sort"
+	"time"
+)
+
+// maxStatementCycles bounds how many closed cycles a request can list.
+const maxStatementCycles = 24
+
+type StatementService struct {
+	originRepo      port.OriginRepository
+	transactionRepo port.TransactionRepository
+}
+
+func NewStatementService(originRepo port.OriginRepository, transactionRepo port.TransactionRepository) *StatementService {
+
+	return &StatementService{
+		originRepo,
+		transactionRepo,
+	}
+}
+
+// GetStatements returns the open cycle of a credit card followed by its last
+// closed cycles, newest first. Cycle balances are rebuilt backwards from the
+// card's current balance through the transactions booked after each closing.
+func (ss *StatementService) GetStatements(ctx context.Context, originId string, cycles int, now time.Time) ([]domain.Statement, error) {
+
+	if cycles < 1 || cycles > maxStatementCycles {
+		return nil, domain.ErrInvalidPeriod
+	}
+
+	origin, err := ss.originRepo.GetOriginById(ctx, originId)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	if origin.OriginKind() != domain.OriginCreditCard || origin.StatementDay == 0 {
+		return nil, domain.ErrNoStatementCycle
+	}
+
+	today := domain.SnapshotDay(now)
+	lastClosing := domain.LastClosingDate(today, origin.StatementDay)
+
+	// closings[0] is the upcoming closing of the open cycle; closings[cycles+1]
+	// the closing before the oldest cycle listed.
+	closings := make([]time.Time, cycles+2)
+	for i := range closings {
+		closings[i] = domain.ClosingDate(lastClosing.Year(), lastClosing.Month()+1-time.Month(i), origin.StatementDay)
+	}
+
+	from := closings[cycles+1].AddDate(0, 0, 1)
+	transactions, err := ss.getCardTransactions(ctx, origin, from)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildStatements(*origin, transactions, closings, today), nil
+}
+
+func (ss *StatementService) getCardTransactions(ctx context.Context, origin *domain.Origin, from time.Time) ([]domain.Transaction, error) {
+
+	var transactionList []domain.Transaction
+	var limit uint64 = 200
+	var cursor string
+
+	filter := domain.TransactionFilter{
+		UserId:    origin.UserId,
+		OriginIds: []string{origin.ID},
+		From:      &from,
+	}
+
+	for {
+		transactions, _, nextCursor, err := ss.transactionRepo.GetTransactionsByCursor(ctx, filter, cursor, limit)
+		if err != nil {
+			return nil, domain.ErrInternal
+		}
+
+		transactionList = append(transactionList, transactions...)
+
+		if nextCursor == "" {
+			break
+		}
+
+		cursor = nextCursor
+	}
+
+	return transactionList, nil
+}
+
+// buildStatements splits the card's transactions into the cycles ending on
+// closings (newest first) and computes each cycle's balances and payment
+// status as of today.
+func buildStatements(origin domain.Origin, transactions []domain.Transaction, closings []time.Time, today time.Time) []domain.Statement {
+
+	sort.Slice(transactions, func(i, j int) bool {
+		return transactions[i].CreatedAt.Before(transactions[j].CreatedAt)
+	})
+
+	dueDays := origin.PaymentDueDays
+	if dueDays == 0 {
+		dueDays = domain.DefaultPaymentDueDays
+	}
+
+	rate := domain.DefaultMinimumPaymentRate
+	if origin.MinimumPaymentRate != nil {
+		rate = *origin.MinimumPaymentRate
+	}
+
+	statements := make([]domain.Statement, len(closings)-1)
+	closingBalance := origin.Total
+	next := len(transactions) - 1
+
+	for i := range statements {
+
+		closing := closings[i]
+		start := closings[i+1].AddDate(0, 0, 1)
+
+		statement := domain.Statement{
+			OriginId:       origin.ID,
+			PeriodStart:    start,
+			ClosingDate:    closing,
+			ClosingBalance: round2(closingBalance),
+			Transactions:   []domain.Transaction{},
+		}
+
+		// transactions after the cycle were already unwound from
+		// closingBalance by the newer cycles
+		for next >= 0 && !domain.SnapshotDay(transactions[next].CreatedAt).Before(start) {
+
+			transaction := transactions[next]
+			statement.Transactions = append([]domain.Transaction{transaction}, statement.Transactions...)
+
+			if transaction.Type == "Income" {
+				statement.Payments += transaction.Amount
+				closingBalance += transaction.Amount
+			} else {
+				statement.Purchases += transaction.Amount
+				closingBalance -= transaction.Amount
+			}
+			next--
+		}
+
+		statement.OpeningBalance = round2(closingBalance)
+		statement.Purchases = round2(statement.Purchases)
+		statement.Payments = round2(statement.Payments)
+
+		if i == 0 {
+			statement.Status = domain.StatementOpen
+		} else {
+			settleStatement(&statement, transactions, dueDays, rate, today)
+		}
+
+		statements[i] = statement
+	}
+
+	return statements
+}
+
+// settleStatement sets the due date and minimum payment of a closed cycle
+// and its status from the payments made between its closing and due date.
+func settleStatement(statement *domain.Statement, transactions []domain.Transaction, dueDays int, rate float64, today time.Time) {
+
+	dueDate := statement.ClosingDate.AddDate(0, 0, dueDays)
+	statement.DueDate = &dueDate
+
+	if statement.ClosingBalance > 0 {
+		statement.MinimumPayment = math.Min(statement.ClosingBalance, round2(statement.ClosingBalance*rate/100))
+	}
+
+	for _, transaction := range transactions {
+		day := domain.SnapshotDay(transaction.CreatedAt)
+		if transaction.Type == "Income" && day.After(statement.ClosingDate) && !day.After(dueDate) {
+			statement.PaidSinceClosing += transaction.Amount
+		}
+	}
+	statement.PaidSinceClosing = round2(statement.PaidSinceClosing)
+
+	switch {
+	case statement.PaidSinceClosing >= statement.ClosingBalance:
+		statement.Status = domain.StatementPaid
+	case statement.PaidSinceClosing >= statement.MinimumPayment:
+		statement.Status = domain.StatementMinimumPaid
+	case today.After(dueDate):
+		statement.Status = domain.StatementOverdue
+	default:
+		statement.Status = domain.StatementDue
+	}
+}
+
+func round2(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"personal-finance/core/domain"
+)
+
+func cardTransaction(transactionType string, amount float64, year int, month time.Month, day int) domain.Transaction {
+	return domain.Transaction{
+		OriginId:  strPtr("card"),
+		Type:      transactionType,
+		Amount:    amount,
+		CreatedAt: time.Date(year, month, day, 15, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestLastClosingDate_ClampsToMonthEnd(t *testing.T) {
+	cases := []struct {
+		day  time.Time
+		want string
+	}{
+		{time.Date(2024, 4, 10, 0, 0, 0, 0, time.UTC), "2024-03-31"},
+		{time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC), "2024-03-31"},
+		{time.Date(2024, 3, 30, 0, 0, 0, 0, time.UTC), "2024-02-29"},
+	}
+
+	for _, c := range cases {
+		if got := domain.LastClosingDate(c.day, 31).Format("2006-01-02"); got != c.want {
+			t.Errorf("LastClosingDate(%s) = %s, want %s", c.day.Format("2006-01-02"), got, c.want)
+		}
+	}
+}
+
+func TestBuildStatements(t *testing.T) {
+	origin := domain.Origin{ID: "card", Kind: domain.OriginCreditCard, StatementDay: 25, Total: 450}
+	transactions := []domain.Transaction{
+		cardTransaction("Output", 100, 2024, 2, 1),
+		cardTransaction("Output", 200, 2024, 2, 20),
+		cardTransaction("Income", 300, 2024, 3, 5),
+		cardTransaction("Output", 400, 2024, 3, 10),
+		cardTransaction("Output", 50, 2024, 4, 2),
+	}
+	closings := []time.Time{
+		time.Date(2024, 4, 25, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 3, 25, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 2, 25, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 25, 0, 0, 0, 0, time.UTC),
+	}
+
+	statements := buildStatements(origin, transactions, closings, time.Date(2024, 4, 10, 0, 0, 0, 0, time.UTC))
+
+	if len(statements) != 3 {
+		t.Fatalf("expected 3 statements, got %d", len(statements))
+	}
+
+	open, march, february := statements[0], statements[1], statements[2]
+
+	if open.Status != domain.StatementOpen || open.OpeningBalance != 400 || open.ClosingBalance != 450 || len(open.Transactions) != 1 {
+		t.Errorf("open cycle = %+v", open)
+	}
+
+	if march.OpeningBalance != 300 || march.ClosingBalance != 400 || march.Purchases != 400 || march.Payments != 300 {
+		t.Errorf("march balances = %+v", march)
+	}
+	if march.MinimumPayment != 20 || march.Status != domain.StatementDue || march.DueDate.Format("2006-01-02") != "2024-04-14" {
+		t.Errorf("march payment = min %v, status %s, due %v", march.MinimumPayment, march.Status, march.DueDate)
+	}
+
+	if february.OpeningBalance != 0 || february.ClosingBalance != 300 || february.PaidSinceClosing != 300 || february.Status != domain.StatementPaid {
+		t.Errorf("february = %+v", february)
+	}
+	if !february.PeriodStart.Equal(time.Date(2024, 1, 26, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("february starts on %v", february.PeriodStart)
+	}
+}
+
+func TestBuildStatements_Overdue(t *testing.T) {
+	origin := domain.Origin{ID: "card", Kind: domain.OriginCreditCard, StatementDay: 25, Total: 400}
+	transactions := []domain.Transaction{
+		cardTransaction("Output", 400, 2024, 3, 10),
+	}
+	closings := []time.Time{
+		time.Date(2024, 4, 25, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 3, 25, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 2, 25, 0, 0, 0, 0, time.UTC),
+	}
+
+	statements := buildStatements(origin, transactions, closings, time.Date(2024, 4, 20, 0, 0, 0, 0, time.UTC))
+
+	if statements[1].Status != domain.StatementOverdue {
+		t.Errorf("expected overdue, got %s", statements[1].Status)
+	}
+}
+
+func TestGetStatements_RequiresCreditCard(t *testing.T) {
+	oRepo := newMockOriginRepo(map[string]*domain.Origin{
+		"o1": {ID: "o1", Kind: domain.OriginSavings},
+	})
+	svc := NewStatementService(oRepo, &mockTransactionRepo{})
+
+	if _, err := svc.GetStatements(context.Background(), "o1", 3, time.Now()); err != domain.ErrNoStatementCycle {
+		t.Errorf("expected ErrNoStatementCycle, got %v", err)
+	}
+}
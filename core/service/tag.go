@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"personal-finance/core/domain"
+	"personal-finance/core/port"
+	"strings"
+)
+
+type TagService struct {
+	tagRepo         port.TagRepository
+	transactionRepo port.TransactionRepository
+	txManager       port.TransactionManager
+}
+
+func NewTagService(tagRepo port.TagRepository, transactionRepo port.TransactionRepository, txManager port.TransactionManager) *TagService {
+
+	return &TagService{
+		tagRepo,
+		transactionRepo,
+		txManager,
+	}
+}
+
+func (ts *TagService) GetTagsByUserId(ctx context.Context, userId string) ([]domain.Tag, error) {
+
+	tags, err := ts.tagRepo.GetTagsByUserId(ctx, userId)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return tags, nil
+}
+
+func (ts *TagService) GetTagById(ctx context.Context, id string) (*domain.Tag, error) {
+
+	tag, err := ts.tagRepo.GetTagById(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrDataNotFound) {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, domain.ErrInternal
+	}
+
+	return tag, nil
+}
+
+func (ts *TagService) CreateTag(ctx context.Context, tag *domain.Tag) (*domain.Tag, error) {
+
+	tag.Name = strings.TrimSpace(tag.Name)
+
+	if err := ts.ensureUniqueName(ctx, tag.UserId, tag.Name, ""); err != nil {
+		return nil, err
+	}
+
+	tag, err := ts.tagRepo.CreateTag(ctx, tag)
+	if err != nil {
+		if err == domain.ErrConflictingData {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	return tag, nil
+}
+
+// UpdateTag saves the tag and, when its name changes, relabels every
+// transaction carrying the old name in the same atomic unit of work.
+func (ts *TagService) UpdateTag(ctx context.Context, id string, tag *domain.Tag) (*domain.Tag, error) {
+
+	tag.Name = strings.TrimSpace(tag.Name)
+
+	err := ts.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+
+		actualTag, err := ts.GetTagById(txCtx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := ts.ensureUniqueName(txCtx, actualTag.UserId, tag.Name, id); err != nil {
+			return err
+		}
+
+		tag.UserId = actualTag.UserId
+		tag.CreatedAt = actualTag.CreatedAt
+
+		if _, err := ts.tagRepo.UpdateTag(txCtx, id, tag); err != nil {
+			if err == domain.ErrConflictingData || err == domain.ErrDataNotFound {
+				return err
+			}
+			return domain.ErrInternal
+		}
+
+		if actualTag.Name != tag.Name {
+			if err := ts.transactionRepo.RenameTag(txCtx, actualTag.UserId, actualTag.Name, tag.Name); err != nil {
+				return domain.ErrInternal
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tag, nil
+}
+
+// DeleteTag removes the tag and strips it from every transaction of its owner.
+func (ts *TagService) DeleteTag(ctx context.Context, id string) error {
+
+	return ts.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+
+		tag, err := ts.GetTagById(txCtx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := ts.transactionRepo.RemoveTag(txCtx, tag.UserId, tag.Name); err != nil {
+			return domain.ErrInternal
+		}
+
+		return ts.tagRepo.DeleteTag(txCtx, id)
+	})
+}
+
+func (ts *TagService) ensureUniqueName(ctx context.Context, userId string, name string, id string) error {
+
+	existing, err := ts.tagRepo.GetTagByName(ctx, userId, name)
+	if err != nil {
+		if errors.Is(err, domain.ErrDataNotFound) {
+			return nil
+		}
+		return domain.ErrInternal
+	}
+
+	if existing.ID != id {
+		return domain.ErrConflictingData
+	}
+
+	return nil
+}
+
+// normalizeTags trims the labels and drops empty and repeated ones,
+// keeping the order in which they were first given.
+func normalizeTags(tags []string) []string {
+
+	var normalized []string
+	seen := make(map[string]bool)
+
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+
+	return normalized
+}
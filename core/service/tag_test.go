@@ -0,0 +1,208 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"personal-finance/core/domain"
+)
+
+// --- mocks ---
+
+type mockTagRepo struct {
+	tags map[string]*domain.Tag
+}
+
+func newMockTagRepo(tags map[string]*domain.Tag) *mockTagRepo {
+	return &mockTagRepo{tags: tags}
+}
+
+func (m *mockTagRepo) GetTagsByUserId(ctx context.Context, userId string) ([]domain.Tag, error) {
+	var tags []domain.Tag
+	for _, tag := range m.tags {
+		if tag.UserId == userId {
+			tags = append(tags, *tag)
+		}
+	}
+	return tags, nil
+}
+
+func (m *mockTagRepo) GetTagById(ctx context.Context, id string) (*domain.Tag, error) {
+	tag, ok := m.tags[id]
+	if !ok {
+		return nil, domain.ErrDataNotFound
+	}
+	copy := *tag
+	return &copy, nil
+}
+
+func (m *mockTagRepo) GetTagByName(ctx context.Context, userId string, name string) (*domain.Tag, error) {
+	for _, tag := range m.tags {
+		if tag.UserId == userId && tag.Name == name {
+			copy := *tag
+			return &copy, nil
+		}
+	}
+	return nil, domain.ErrDataNotFound
+}
+
+func (m *mockTagRepo) CreateTag(ctx context.Context, tag *domain.Tag) (*domain.Tag, error) {
+	tag.ID = "new"
+	m.tags[tag.ID] = tag
+	return tag, nil
+}
+
+func (m *mockTagRepo) UpdateTag(ctx context.Context, id string, tag *domain.Tag) (*domain.Tag, error) {
+	tag.ID = id
+	m.tags[id] = tag
+	return tag, nil
+}
+
+func (m *mockTagRepo) DeleteTag(ctx context.Context, id string) error {
+	delete(m.tags, id)
+	return nil
+}
+
+func TestCreateTag_TrimsAndRejectsDuplicates(t *testing.T) {
+	tagRepo := newMockTagRepo(map[string]*domain.Tag{
+		"t1": {ID: "t1", UserId: "u1", Name: "travel"},
+	})
+	ts := NewTagService(tagRepo, &mockTransactionRepo{}, noopTxManager{})
+
+	if _, err := ts.CreateTag(context.Background(), &domain.Tag{UserId: "u1", Name: "  travel "}); err != domain.ErrConflictingData {
+		t.Fatalf("expected ErrConflictingData, got %v", err)
+	}
+
+	created, err := ts.CreateTag(context.Background(), &domain.Tag{UserId: "u2", Name: " travel "})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created.Name != "travel" {
+		t.Errorf("expected trimmed name %q, got %q", "travel", created.Name)
+	}
+}
+
+func TestUpdateTag_RenamePropagatesToTransactions(t *testing.T) {
+	tagRepo := newMockTagRepo(map[string]*domain.Tag{
+		"t1": {ID: "t1", UserId: "u1", Name: "travel"},
+	})
+	tRepo := &mockTransactionRepo{}
+	ts := NewTagService(tagRepo, tRepo, noopTxManager{})
+
+	if _, err := ts.UpdateTag(context.Background(), "t1", &domain.Tag{Name: "trips"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(tRepo.renamedTags) != 1 || tRepo.renamedTags[0] != "travel->trips" {
+		t.Errorf("expected travel renamed to trips, got %v", tRepo.renamedTags)
+	}
+	if got := tagRepo.tags["t1"].UserId; got != "u1" {
+		t.Errorf("expected the owner kept, got %q", got)
+	}
+}
+
+func TestUpdateTag_SameNameSkipsRename(t *testing.T) {
+	tagRepo := newMockTagRepo(map[string]*domain.Tag{
+		"t1": {ID: "t1", UserId: "u1", Name: "travel"},
+	})
+	tRepo := &mockTransactionRepo{}
+	ts := NewTagService(tagRepo, tRepo, noopTxManager{})
+
+	if _, err := ts.UpdateTag(context.Background(), "t1", &domain.Tag{Name: "travel", Color: "#00f"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tRepo.renamedTags) != 0 {
+		t.Errorf("expected no rename, got %v", tRepo.renamedTags)
+	}
+}
+
+func TestUpdateTag_RenameToExistingName(t *testing.T) {
+	tagRepo := newMockTagRepo(map[string]*domain.Tag{
+		"t1": {ID: "t1", UserId: "u1", Name: "travel"},
+		"t2": {ID: "t2", UserId: "u1", Name: "food"},
+	})
+	tRepo := &mockTransactionRepo{}
+	ts := NewTagService(tagRepo, tRepo, noopTxManager{})
+
+	if _, err := ts.UpdateTag(context.Background(), "t1", &domain.Tag{Name: "food"}); err != domain.ErrConflictingData {
+		t.Fatalf("expected ErrConflictingData, got %v", err)
+	}
+	if len(tRepo.renamedTags) != 0 {
+		t.Errorf("expected no rename, got %v", tRepo.renamedTags)
+	}
+}
+
+func TestDeleteTag_RemovesFromTransactions(t *testing.T) {
+	tagRepo := newMockTagRepo(map[string]*domain.Tag{
+		"t1": {ID: "t1", UserId: "u1", Name: "travel"},
+	})
+	tRepo := &mockTransactionRepo{}
+	ts := NewTagService(tagRepo, tRepo, noopTxManager{})
+
+	if err := ts.DeleteTag(context.Background(), "t1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(tRepo.removedTags) != 1 || tRepo.removedTags[0] != "travel" {
+		t.Errorf("expected travel removed from transactions, got %v", tRepo.removedTags)
+	}
+	if _, ok := tagRepo.tags["t1"]; ok {
+		t.Errorf("expected the tag deleted")
+	}
+}
+
+func TestNormalizeTags_TrimsAndDedupes(t *testing.T) {
+	got := normalizeTags([]string{" travel", "food ", "", "travel", "  ", "food", "rent"})
+	want := []string{"travel", "food", "rent"}
+
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("expected %v, got %v", want, got)
+			break
+		}
+	}
+
+	if got := normalizeTags([]string{" ", ""}); got != nil {
+		t.Errorf("expected nil for blank tags, got %v", got)
+	}
+}
+
+func TestGetTransactionsByTags_NormalizesFilter(t *testing.T) {
+	tRepo := &mockTransactionRepo{}
+	ts := newTransactionService(tRepo, newMockOriginRepo(map[string]*domain.Origin{}))
+
+	if _, _, _, err := ts.GetTransactionsByTags(context.Background(), "u1", 1, 10, []string{" travel ", "travel", "food"}, true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(tRepo.tagFilter) != 2 || tRepo.tagFilter[0] != "travel" || tRepo.tagFilter[1] != "food" {
+		t.Errorf("expected [travel food], got %v", tRepo.tagFilter)
+	}
+}
+
+func TestCalculateTagSummary(t *testing.T) {
+	transactions := []domain.Transaction{
+		{Type: "Output", Amount: 120, Tags: []string{"travel", "food"}},
+		{Type: "Output", Amount: 30, Tags: []string{"food"}},
+		{Type: "Income", Amount: 50, Tags: []string{"travel"}},
+		{Type: "Output", Amount: 10},
+	}
+
+	summary := calculateTagSummary(transactions)
+
+	want := []domain.TagSummary{
+		{Tag: "travel", TotalIncome: 50, TotalExpenses: 120, Count: 2},
+		{Tag: "food", TotalIncome: 0, TotalExpenses: 150, Count: 2},
+	}
+	if len(summary) != len(want) {
+		t.Fatalf("expected %+v, got %+v", want, summary)
+	}
+	for i := range want {
+		if summary[i] != want[i] {
+			t.Errorf("expected %+v, got %+v", want[i], summary[i])
+		}
+	}
+}
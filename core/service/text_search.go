@@ -0,0 +1,168 @@
+package service
+
+import (
+	"personal-finance/core/domain"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+const snippetRadius = 40
+
+// highlightTransaction locates the query terms in the searchable fields of
+// transaction, comparing without case and diacritics like the text index does.
+func highlightTransaction(transaction domain.Transaction, terms []string) []domain.TextHighlight {
+
+	var highlights []domain.TextHighlight
+
+	fields := []struct {
+		name  string
+		value string
+	}{
+		{"description", transaction.Description},
+		{"person_business", transaction.PersonOrBusiness},
+		{"subject", transaction.Subject},
+		{"output_category", transaction.OutputCategory},
+	}
+
+	for _, field := range fields {
+		if highlight, ok := highlightField(field.name, field.value, terms); ok {
+			highlights = append(highlights, highlight)
+		}
+	}
+
+	return highlights
+}
+
+func highlightField(name string, value string, terms []string) (domain.TextHighlight, bool) {
+
+	original := []rune(value)
+	folded, origin := foldWithOrigin(original)
+
+	var ranges [][2]int
+
+	for _, term := range terms {
+		termRunes := []rune(term)
+		for offset := 0; offset+len(termRunes) <= len(folded); {
+			index := indexRunes(folded[offset:], termRunes)
+			if index < 0 {
+				break
+			}
+			start := origin[offset+index]
+			end := origin[offset+index+len(termRunes)-1] + 1
+			ranges = append(ranges, [2]int{start, end})
+			offset += index + len(termRunes)
+		}
+	}
+
+	if len(ranges) == 0 {
+		return domain.TextHighlight{}, false
+	}
+
+	ranges = mergeRanges(ranges)
+
+	snippetStart := 0
+	snippetEnd := len(original)
+	if ranges[0][0] > snippetRadius {
+		snippetStart = ranges[0][0] - snippetRadius
+	}
+	if ranges[0][1]+snippetRadius*2 < snippetEnd {
+		snippetEnd = ranges[0][1] + snippetRadius*2
+	}
+
+	var snippetRanges [][2]int
+	for _, r := range ranges {
+		if r[0] >= snippetStart && r[1] <= snippetEnd {
+			snippetRanges = append(snippetRanges, [2]int{r[0] - snippetStart, r[1] - snippetStart})
+		}
+	}
+
+	return domain.TextHighlight{
+		Field:   name,
+		Snippet: string(original[snippetStart:snippetEnd]),
+		Ranges:  snippetRanges,
+	}, true
+}
+
+// searchTerms splits a $text query into the folded words worth highlighting,
+// leaving out negated terms ("-word").
+func searchTerms(query string) []string {
+
+	var terms []string
+
+	for _, word := range strings.Fields(strings.ReplaceAll(query, "\"", " ")) {
+		if strings.HasPrefix(word, "-") {
+			continue
+		}
+		for _, part := range strings.FieldsFunc(word, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }) {
+			folded, _ := foldWithOrigin([]rune(part))
+			if len(folded) > 0 {
+				terms = append(terms, string(folded))
+			}
+		}
+	}
+
+	return terms
+}
+
+// foldWithOrigin lowercases the text and strips its combining marks rune by
+// rune, returning for every folded rune the index of the rune it came from.
+func foldWithOrigin(text []rune) ([]rune, []int) {
+
+	var folded []rune
+	var origin []int
+
+	for index, r := range text {
+		for _, decomposed := range norm.NFD.String(string(r)) {
+			if unicode.Is(unicode.Mn, decomposed) {
+				continue
+			}
+			folded = append(folded, unicode.ToLower(decomposed))
+			origin = append(origin, index)
+		}
+	}
+
+	return folded, origin
+}
+
+func indexRunes(text []rune, term []rune) int {
+
+	for i := 0; i+len(term) <= len(text); i++ {
+		match := true
+		for j := range term {
+			if text[i+j] != term[j] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func mergeRanges(ranges [][2]int) [][2]int {
+
+	for i := 1; i < len(ranges); i++ {
+		for j := i; j > 0 && ranges[j][0] < ranges[j-1][0]; j-- {
+			ranges[j], ranges[j-1] = ranges[j-1], ranges[j]
+		}
+	}
+
+	merged := [][2]int{ranges[0]}
+	for _, r := range ranges[1:] {
+		last := &merged[len(merged)-1]
+		if r[0] <= last[1] {
+			if r[1] > last[1] {
+				last[1] = r[1]
+			}
+			continue
+		}
+		merged = append(merged, r)
+	}
+
+	return merged
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"personal-finance/core/domain"
+)
+
+func TestSearchTerms_FoldsAccentsAndSkipsNegations(t *testing.T) {
+	got := searchTerms(`Farmácia "Cruz Verde" -hospital`)
+	want := []string{"farmacia", "cruz", "verde"}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestHighlightTransaction_MatchesWithoutAccents(t *testing.T) {
+	transaction := domain.Transaction{
+		Description:      "Compra en la farmácia del centro",
+		PersonOrBusiness: "Farmacia Cruz Verde",
+		Subject:          "Expense",
+	}
+
+	highlights := highlightTransaction(transaction, searchTerms("farmacia"))
+
+	if len(highlights) != 2 {
+		t.Fatalf("expected 2 highlighted fields, got %d: %+v", len(highlights), highlights)
+	}
+
+	description := highlights[0]
+	if description.Field != "description" {
+		t.Fatalf("expected description first, got %q", description.Field)
+	}
+
+	snippet := []rune(description.Snippet)
+	r := description.Ranges[0]
+	if got := string(snippet[r[0]:r[1]]); got != "farmácia" {
+		t.Errorf("expected range to cover %q, got %q", "farmácia", got)
+	}
+
+	payee := highlights[1]
+	if payee.Field != "person_business" || payee.Ranges[0] != [2]int{0, 8} {
+		t.Errorf("unexpected payee highlight %+v", payee)
+	}
+}
+
+func TestHighlightTransaction_TrimsLongFieldsAroundFirstMatch(t *testing.T) {
+	long := "Lorem ipsum dolor sit amet, consectetur adipiscing elit, sed do eiusmod tempor pharmacy incididunt ut labore et dolore magna aliqua, ut enim ad minim veniam, quis nostrud exercitation"
+	transaction := domain.Transaction{Description: long}
+
+	highlights := highlightTransaction(transaction, searchTerms("pharmacy"))
+
+	if len(highlights) != 1 {
+		t.Fatalf("expected 1 highlight, got %d", len(highlights))
+	}
+
+	snippet := []rune(highlights[0].Snippet)
+	if len(snippet) >= len([]rune(long)) {
+		t.Errorf("expected snippet to be trimmed, got %q", highlights[0].Snippet)
+	}
+
+	r := highlights[0].Ranges[0]
+	if got := string(snippet[r[0]:r[1]]); got != "pharmacy" {
+		t.Errorf("expected range to cover %q, got %q", "pharmacy", got)
+	}
+}
@@ -5,20 +5,39 @@ import (
 	"errors"
 	"personal-finance/core/domain"
 	"personal-finance/core/port"
+	"slices"
+	"strings"
+	"time"
 )
 
 type TransactionService struct {
-	transactionRepo port.TransactionRepository
-	originRepo      port.OriginRepository
-	txManager       port.TransactionManager
+	transactionRepo   port.TransactionRepository
+	originRepo        port.OriginRepository
+	txManager         port.TransactionManager
+	attachmentService port.AttachmentService
+	snapshotService   port.SnapshotService
+	ledgerService     port.LedgerService
+	auditService      port.AuditService
 }
 
-func NewTransactionService(transactionRepo port.TransactionRepository, originRepo port.OriginRepository, txManager port.TransactionManager) *TransactionService {
+func NewTransactionService(
+	transactionRepo port.TransactionRepository,
+	originRepo port.OriginRepository,
+	txManager port.TransactionManager,
+	attachmentService port.AttachmentService,
+	snapshotService port.SnapshotService,
+	ledgerService port.LedgerService,
+	auditService port.AuditService,
+) *TransactionService {
 
 	return &TransactionService{
 		transactionRepo,
 		originRepo,
 		txManager,
+		attachmentService,
+		snapshotService,
+		ledgerService,
+		auditService,
 	}
 }
 
@@ -68,6 +87,89 @@ func (ts *TransactionService) GetTransactionsByType(
 	return transactions, totalDocuments, totalPages, nil
 }
 
+func (ts *TransactionService) GetTransactionsByTags(
+	ctx context.Context,
+	userId string,
+	page, limit uint64,
+	tags []string,
+	matchAll bool,
+) ([]domain.Transaction, int64, int, error) {
+
+	transactions, totalDocuments, totalPages, err := ts.transactionRepo.GetTransactionsByTags(ctx, userId, page, limit, normalizeTags(tags), matchAll)
+	if err != nil {
+		return nil, 0, 0, domain.ErrInternal
+	}
+
+	return transactions, totalDocuments, totalPages, nil
+}
+
+// SearchTransactions returns one page of the user's transactions matching
+// every criterion set in filter.
+func (ts *TransactionService) SearchTransactions(
+	ctx context.Context,
+	filter domain.TransactionFilter,
+	page, limit uint64,
+) ([]domain.Transaction, int64, int, error) {
+
+	filter.Tags = normalizeTags(filter.Tags)
+
+	transactions, totalDocuments, totalPages, err := ts.transactionRepo.SearchTransactions(ctx, filter, page, limit)
+	if err != nil {
+		return nil, 0, 0, domain.ErrInternal
+	}
+
+	return transactions, totalDocuments, totalPages, nil
+}
+
+// GetTransactionsByCursor returns the page of transactions following cursor
+// in creation date order, and the cursor of the next page ("" on the last one).
+func (ts *TransactionService) GetTransactionsByCursor(
+	ctx context.Context,
+	filter domain.TransactionFilter,
+	cursor string,
+	limit uint64,
+) ([]domain.Transaction, int64, string, error) {
+
+	filter.Tags = normalizeTags(filter.Tags)
+
+	transactions, totalDocuments, nextCursor, err := ts.transactionRepo.GetTransactionsByCursor(ctx, filter, cursor, limit)
+	if err != nil {
+		if err == domain.ErrInvalidCursor || err == domain.ErrCursorSort {
+			return nil, 0, "", err
+		}
+		return nil, 0, "", domain.ErrInternal
+	}
+
+	return transactions, totalDocuments, nextCursor, nil
+}
+
+// TextSearchTransactions ranks the user's transactions by relevance to query
+// and highlights where each one matched.
+func (ts *TransactionService) TextSearchTransactions(
+	ctx context.Context,
+	userId string,
+	query string,
+	page, limit uint64,
+) ([]domain.TextSearchResult, int64, int, error) {
+
+	query = strings.TrimSpace(query)
+	terms := searchTerms(query)
+	if len(terms) == 0 {
+		return nil, 0, 0, domain.ErrEmptySearch
+	}
+
+	results, totalDocuments, totalPages, err := ts.transactionRepo.TextSearchTransactions(ctx, userId, query, page, limit)
+	if err != nil {
+		return nil, 0, 0, domain.ErrInternal
+	}
+
+	for i := range results {
+		results[i].Highlights = highlightTransaction(results[i].Transaction, terms)
+	}
+
+	return results, totalDocuments, totalPages, nil
+}
+
 func (ts *TransactionService) GetTransactionById(ctx context.Context, id string) (*domain.Transaction, error) {
 
 	transaction, err := ts.transactionRepo.GetTransactionById(ctx, id)
@@ -89,22 +191,10 @@ func (ts *TransactionService) GetTransactionById(ctx context.Context, id string)
 // commit or neither does.
 func (ts *TransactionService) CreateTransaction(ctx context.Context, transaction *domain.Transaction) (*domain.Transaction, error) {
 
-	err := ts.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
-
-		created, err := ts.transactionRepo.CreateTransaction(txCtx, transaction)
-		if err != nil {
-			if err == domain.ErrConflictingData {
-				return err
-			}
-			return domain.ErrInternal
-		}
-		*transaction = *created
-
-		if transaction.OriginId != nil && *transaction.OriginId != "" {
-			return ts.UpdateTotalOrigin(txCtx, *transaction.OriginId, transaction.Type, transaction.Amount)
-		}
+	transaction.Tags = normalizeTags(transaction.Tags)
 
-		return nil
+	err := ts.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		return ts.BookTransaction(txCtx, transaction)
 	})
 	if err != nil {
 		return nil, err
@@ -113,8 +203,51 @@ func (ts *TransactionService) CreateTransaction(ctx context.Context, transaction
 	return transaction, nil
 }
 
+// BookTransaction inserts the transaction, audits and journals it and applies
+// it to its origin's balance. Callers run it inside their own transaction.
+// Archived origins take no new transactions, and none may be dated inside
+// its origin's reconciled period.
+func (ts *TransactionService) BookTransaction(ctx context.Context, transaction *domain.Transaction) error {
+
+	if err := ts.checkNotArchived(ctx, transaction.OriginId); err != nil {
+		return err
+	}
+
+	if err := ts.checkUnlocked(ctx, transaction.OriginId, transaction.CreatedAt); err != nil {
+		return err
+	}
+
+	created, err := ts.transactionRepo.CreateTransaction(ctx, transaction)
+	if err != nil {
+		if err == domain.ErrConflictingData {
+			return err
+		}
+		return domain.ErrInternal
+	}
+	*transaction = *created
+
+	if err := ts.auditService.Record(ctx, transaction.UserId, domain.AuditTransaction, transaction.ID, domain.AuditCreate, nil, transaction); err != nil {
+		return err
+	}
+
+	if err := ts.ledgerService.PostTransaction(ctx, transaction); err != nil {
+		return err
+	}
+
+	if transaction.OriginId != nil && *transaction.OriginId != "" {
+		return ts.UpdateTotalOrigin(ctx, *transaction.OriginId, transaction.Type, transaction.Amount)
+	}
+
+	return nil
+}
+
+// UpdateTransaction replaces the transaction and moves the balance and journal
+// along. Debt payments only take edits that leave their journal entry alone,
+// since their principal/interest split was computed from the amount and date.
 func (ts *TransactionService) UpdateTransaction(ctx context.Context, id string, transaction *domain.Transaction) (*domain.Transaction, error) {
 
+	transaction.Tags = normalizeTags(transaction.Tags)
+
 	err := ts.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
 
 		actualTransaction, err := ts.GetTransactionById(txCtx, id)
@@ -122,6 +255,36 @@ func (ts *TransactionService) UpdateTransaction(ctx context.Context, id string,
 			return err
 		}
 
+		if actualTransaction.Reconciled {
+			return domain.ErrReconciledPeriod
+		}
+
+		if actualTransaction.DebtPayment != nil {
+			if rebooks(actualTransaction, transaction) {
+				return domain.ErrDebtPaymentEdit
+			}
+			transaction.DebtPayment = actualTransaction.DebtPayment
+		}
+
+		// the edit takes the transaction out of the period it was booked in and
+		// into the one it lands in, so neither may be locked
+		if movesTransaction(actualTransaction, transaction) || changesBalance(actualTransaction, transaction) {
+			if err := ts.checkUnlocked(txCtx, actualTransaction.OriginId, actualTransaction.CreatedAt); err != nil {
+				return err
+			}
+			if err := ts.checkUnlocked(txCtx, transaction.OriginId, transaction.CreatedAt); err != nil {
+				return err
+			}
+		}
+
+		if changesOrigin(actualTransaction, transaction) {
+			if err := ts.checkNotArchived(txCtx, transaction.OriginId); err != nil {
+				return err
+			}
+		}
+
+		transaction.Cleared = actualTransaction.Cleared
+
 		if err := ts.reconcileOriginBalance(txCtx, actualTransaction, transaction); err != nil {
 			return err
 		}
@@ -137,7 +300,21 @@ func (ts *TransactionService) UpdateTransaction(ctx context.Context, id string,
 			return domain.ErrInternal
 		}
 
-		return nil
+		if err := ts.auditService.Record(txCtx, actualTransaction.UserId, domain.AuditTransaction, id, domain.AuditUpdate, actualTransaction, transaction); err != nil {
+			return err
+		}
+
+		if !rebooks(actualTransaction, transaction) {
+			return nil
+		}
+
+		if err := ts.ledgerService.ReverseTransaction(txCtx, actualTransaction); err != nil {
+			return err
+		}
+
+		transaction.ID = id
+
+		return ts.ledgerService.PostTransaction(txCtx, transaction)
 	})
 	if err != nil {
 		return nil, err
@@ -155,17 +332,9 @@ func (ts *TransactionService) reconcileOriginBalance(ctx context.Context, actual
 	updateOrigin := false
 	amount := float64(0)
 
-	if *actualTransaction.OriginId != *updatedTransaction.OriginId {
-
-		if actualTransaction.OriginId == nil {
-			updateOrigin = true
-			originId = *updatedTransaction.OriginId
-
-			transactionType = updatedTransaction.Type
-			amount = updatedTransaction.Amount
-		} else {
+	if changesOrigin(actualTransaction, updatedTransaction) {
 
-			updateOrigin = true
+		if hasOrigin(actualTransaction) {
 
 			originId = *actualTransaction.OriginId
 
@@ -180,6 +349,11 @@ func (ts *TransactionService) reconcileOriginBalance(ctx context.Context, actual
 			if err := ts.UpdateTotalOrigin(ctx, originId, transactionType, amount); err != nil {
 				return err
 			}
+		}
+
+		if hasOrigin(updatedTransaction) {
+
+			updateOrigin = true
 
 			originId = *updatedTransaction.OriginId
 
@@ -187,7 +361,7 @@ func (ts *TransactionService) reconcileOriginBalance(ctx context.Context, actual
 			amount = updatedTransaction.Amount
 		}
 
-	} else if *actualTransaction.OriginId == *updatedTransaction.OriginId {
+	} else if hasOrigin(updatedTransaction) {
 
 		originId = *updatedTransaction.OriginId
 		transactionType = updatedTransaction.Type
@@ -224,6 +398,91 @@ func (ts *TransactionService) reconcileOriginBalance(ctx context.Context, actual
 	return nil
 }
 
+// rebooks reports whether an edit changes the transaction's journal entry.
+func rebooks(actual *domain.Transaction, updated *domain.Transaction) bool {
+
+	return !actual.CreatedAt.Equal(updated.CreatedAt) ||
+		!slices.Equal(domain.TransactionPostings(*actual), domain.TransactionPostings(*updated))
+}
+
+// movesTransaction reports whether an edit moves the transaction to another
+// origin or date, which may fall inside a reconciled period.
+func movesTransaction(actual *domain.Transaction, updated *domain.Transaction) bool {
+
+	return changesOrigin(actual, updated) || !actual.CreatedAt.Equal(updated.CreatedAt)
+}
+
+// changesBalance reports whether an edit changes what the transaction adds to
+// or takes from its origin.
+func changesBalance(actual *domain.Transaction, updated *domain.Transaction) bool {
+
+	return changesOrigin(actual, updated) || actual.Type != updated.Type || actual.Amount != updated.Amount
+}
+
+// hasOrigin reports whether the transaction is booked on an origin.
+func hasOrigin(transaction *domain.Transaction) bool {
+
+	return transaction.OriginId != nil && *transaction.OriginId != ""
+}
+
+// changesOrigin reports whether an edit moves the transaction to another origin.
+func changesOrigin(actual *domain.Transaction, updated *domain.Transaction) bool {
+
+	var actualOrigin, updatedOrigin string
+	if actual.OriginId != nil {
+		actualOrigin = *actual.OriginId
+	}
+	if updated.OriginId != nil {
+		updatedOrigin = *updated.OriginId
+	}
+
+	return actualOrigin != updatedOrigin
+}
+
+// checkUnlocked refuses writes dated inside the origin's reconciled period.
+func (ts *TransactionService) checkUnlocked(ctx context.Context, originId *string, date time.Time) error {
+
+	if originId == nil || *originId == "" {
+		return nil
+	}
+
+	origin, err := ts.originRepo.GetOriginById(ctx, *originId)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return err
+		}
+		return domain.ErrInternal
+	}
+
+	if origin.IsLocked(date) {
+		return domain.ErrReconciledPeriod
+	}
+
+	return nil
+}
+
+// checkNotArchived refuses adding transactions to an archived origin.
+func (ts *TransactionService) checkNotArchived(ctx context.Context, originId *string) error {
+
+	if originId == nil || *originId == "" {
+		return nil
+	}
+
+	origin, err := ts.originRepo.GetOriginById(ctx, *originId)
+	if err != nil {
+		if err == domain.ErrDataNotFound {
+			return err
+		}
+		return domain.ErrInternal
+	}
+
+	if origin.IsArchived() {
+		return domain.ErrArchivedOrigin
+	}
+
+	return nil
+}
+
 func (ts *TransactionService) UpdateTotalOrigin(ctx context.Context, originId string, transactionType string, amount float64) error {
 
 	origin, err := ts.originRepo.GetOriginById(ctx, originId)
@@ -237,22 +496,19 @@ func (ts *TransactionService) UpdateTotalOrigin(ctx context.Context, originId st
 		return domain.ErrInternal
 	}
 
-	if transactionType == "Income" {
-		origin.Total += amount
-	} else if transactionType == "Output" {
-		origin.Total -= amount
-	}
+	origin.ApplyTransaction(transactionType, amount)
 
 	_, err = ts.originRepo.UpdateOrigin(ctx, originId, origin)
 	if err != nil {
 		return domain.ErrInternal
 	}
 
-	return nil
+	return ts.snapshotService.RecordBalance(ctx, origin)
 }
 
 // DeleteTransaction reverts the transaction's effect on its origin balance
-// (if any) and deletes it atomically: either both writes commit or neither does.
+// (if any) and moves it to the trash atomically: either both writes commit or
+// neither does. Its attachments are kept until the transaction is purged.
 func (ts *TransactionService) DeleteTransaction(ctx context.Context, id string) error {
 
 	return ts.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
@@ -262,6 +518,14 @@ func (ts *TransactionService) DeleteTransaction(ctx context.Context, id string)
 			return err
 		}
 
+		if transaction.Reconciled {
+			return domain.ErrReconciledPeriod
+		}
+
+		if err := ts.checkUnlocked(txCtx, transaction.OriginId, transaction.CreatedAt); err != nil {
+			return err
+		}
+
 		if transaction.OriginId != nil && *transaction.OriginId != "" {
 
 			revertType := "Output"
@@ -274,6 +538,130 @@ func (ts *TransactionService) DeleteTransaction(ctx context.Context, id string)
 			}
 		}
 
-		return ts.transactionRepo.DeleteTransaction(txCtx, id)
+		if err := ts.ledgerService.ReverseTransaction(txCtx, transaction); err != nil {
+			return err
+		}
+
+		if err := ts.auditService.Record(txCtx, transaction.UserId, domain.AuditTransaction, id, domain.AuditDelete, transaction, nil); err != nil {
+			return err
+		}
+
+		if err := ts.transactionRepo.DeleteTransaction(txCtx, id); err != nil {
+			if err == domain.ErrDataNotFound {
+				return err
+			}
+			return domain.ErrInternal
+		}
+
+		return nil
 	})
 }
+
+// GetDeletedTransactions returns one page of the user's transactions in the
+// trash, most recently deleted first.
+func (ts *TransactionService) GetDeletedTransactions(ctx context.Context, userId string, page, limit uint64) ([]domain.Transaction, int64, int, error) {
+
+	transactions, totalDocuments, totalPages, err := ts.transactionRepo.GetDeletedTransactions(ctx, userId, page, limit)
+	if err != nil {
+		return nil, 0, 0, domain.ErrInternal
+	}
+
+	return transactions, totalDocuments, totalPages, nil
+}
+
+// RestoreTransaction takes the transaction out of the trash and applies it
+// again to its origin balance and the journal. Its origin must not be in the
+// trash itself, archived nor reconciled past the transaction's date.
+func (ts *TransactionService) RestoreTransaction(ctx context.Context, id string) (*domain.Transaction, error) {
+
+	var restored *domain.Transaction
+
+	err := ts.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+
+		transaction, err := ts.transactionRepo.GetDeletedTransactionById(txCtx, id)
+		if err != nil {
+			if err == domain.ErrDataNotFound {
+				return err
+			}
+			return domain.ErrInternal
+		}
+
+		if err := ts.checkUnlocked(txCtx, transaction.OriginId, transaction.CreatedAt); err != nil {
+			return err
+		}
+
+		if err := ts.checkNotArchived(txCtx, transaction.OriginId); err != nil {
+			return err
+		}
+
+		if err := ts.transactionRepo.RestoreTransaction(txCtx, id); err != nil {
+			if err == domain.ErrDataNotFound {
+				return err
+			}
+			return domain.ErrInternal
+		}
+
+		restored, err = ts.GetTransactionById(txCtx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := ts.auditService.Record(txCtx, restored.UserId, domain.AuditTransaction, id, domain.AuditRestore, transaction, restored); err != nil {
+			return err
+		}
+
+		if err := ts.ledgerService.PostTransaction(txCtx, restored); err != nil {
+			return err
+		}
+
+		if restored.OriginId != nil && *restored.OriginId != "" {
+			return ts.UpdateTotalOrigin(txCtx, *restored.OriginId, restored.Type, restored.Amount)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return restored, nil
+}
+
+// PurgeDeletedTransactions permanently deletes the transactions moved to the
+// trash before the given time, along with their attachments, and returns how
+// many were deleted. Each transaction is purged with its attachment records
+// atomically, so one that fails stays in the trash for the next run.
+func (ts *TransactionService) PurgeDeletedTransactions(ctx context.Context, before time.Time) (int, error) {
+
+	ids, err := ts.transactionRepo.GetPurgeableTransactionIds(ctx, before)
+	if err != nil {
+		return 0, domain.ErrInternal
+	}
+
+	purged := 0
+
+	for _, id := range ids {
+
+		err := ts.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+
+			if err := ts.transactionRepo.PurgeTransaction(txCtx, id, before); err != nil {
+				if err == domain.ErrDataNotFound {
+					return err
+				}
+				return domain.ErrInternal
+			}
+
+			return ts.attachmentService.DeleteAttachmentsByTransactionId(txCtx, id)
+		})
+		if err != nil {
+			if err == domain.ErrDataNotFound {
+				continue
+			}
+			return purged, err
+		}
+
+		purged++
+	}
+
+	return purged, nil
+}
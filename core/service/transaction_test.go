@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"testing"
+	"time"
 
 	"personal-finance/core/domain"
 )
@@ -10,8 +11,18 @@ import (
 // --- mocks ---
 
 type mockTransactionRepo struct {
-	getByIdFunc func(ctx context.Context, id string) (*domain.Transaction, error)
-	updateFunc  func(ctx context.Context, id string, tx *domain.Transaction) (*domain.Transaction, error)
+	getByIdFunc    func(ctx context.Context, id string) (*domain.Transaction, error)
+	updateFunc     func(ctx context.Context, id string, tx *domain.Transaction) (*domain.Transaction, error)
+	getDeletedFunc func(ctx context.Context, id string) (*domain.Transaction, error)
+	restored       []string
+	byOrigin       []domain.Transaction
+	reassigned     []string
+	tagFilter      []string
+	renamedTags    []string
+	removedTags    []string
+	purgeable      []string
+	purgeFunc      func(ctx context.Context, id string) error
+	purged         []string
 }
 
 func (m *mockTransactionRepo) GetTransactionsByUserId(ctx context.Context, page, limit uint64, userId string) ([]domain.Transaction, int64, int, error) {
@@ -26,6 +37,23 @@ func (m *mockTransactionRepo) GetTransactionsByType(ctx context.Context, userId
 	return nil, 0, 0, nil
 }
 
+func (m *mockTransactionRepo) GetTransactionsByTags(ctx context.Context, userId string, page, limit uint64, tags []string, matchAll bool) ([]domain.Transaction, int64, int, error) {
+	m.tagFilter = tags
+	return nil, 0, 0, nil
+}
+
+func (m *mockTransactionRepo) SearchTransactions(ctx context.Context, filter domain.TransactionFilter, page, limit uint64) ([]domain.Transaction, int64, int, error) {
+	return nil, 0, 0, nil
+}
+
+func (m *mockTransactionRepo) GetTransactionsByCursor(ctx context.Context, filter domain.TransactionFilter, cursor string, limit uint64) ([]domain.Transaction, int64, string, error) {
+	return m.byOrigin, int64(len(m.byOrigin)), "", nil
+}
+
+func (m *mockTransactionRepo) TextSearchTransactions(ctx context.Context, userId string, query string, page, limit uint64) ([]domain.TextSearchResult, int64, int, error) {
+	return nil, 0, 0, nil
+}
+
 func (m *mockTransactionRepo) GetTransactionById(ctx context.Context, id string) (*domain.Transaction, error) {
 	return m.getByIdFunc(ctx, id)
 }
@@ -38,10 +66,73 @@ func (m *mockTransactionRepo) UpdateTransaction(ctx context.Context, id string,
 	return m.updateFunc(ctx, id, tx)
 }
 
+func (m *mockTransactionRepo) RenameTag(ctx context.Context, userId string, oldName string, newName string) error {
+	m.renamedTags = append(m.renamedTags, oldName+"->"+newName)
+	return nil
+}
+
+func (m *mockTransactionRepo) RemoveTag(ctx context.Context, userId string, name string) error {
+	m.removedTags = append(m.removedTags, name)
+	return nil
+}
+
 func (m *mockTransactionRepo) DeleteTransaction(ctx context.Context, id string) error {
 	return nil
 }
 
+func (m *mockTransactionRepo) CountTransactionsByOriginId(ctx context.Context, originId string) (int64, error) {
+	var count int64
+	for _, tx := range m.byOrigin {
+		if tx.OriginId != nil && *tx.OriginId == originId {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *mockTransactionRepo) GetTransactionsByOriginId(ctx context.Context, originId string) ([]domain.Transaction, error) {
+	var transactions []domain.Transaction
+	for _, tx := range m.byOrigin {
+		if tx.OriginId != nil && *tx.OriginId == originId {
+			transactions = append(transactions, tx)
+		}
+	}
+	return transactions, nil
+}
+
+func (m *mockTransactionRepo) ReassignTransactions(ctx context.Context, fromOriginId string, toOriginId string) error {
+	m.reassigned = append(m.reassigned, fromOriginId+"->"+toOriginId)
+	return nil
+}
+
+func (m *mockTransactionRepo) GetDeletedTransactions(ctx context.Context, userId string, page, limit uint64) ([]domain.Transaction, int64, int, error) {
+	return nil, 0, 0, nil
+}
+
+func (m *mockTransactionRepo) GetDeletedTransactionById(ctx context.Context, id string) (*domain.Transaction, error) {
+	if m.getDeletedFunc == nil {
+		return nil, domain.ErrDataNotFound
+	}
+	return m.getDeletedFunc(ctx, id)
+}
+
+func (m *mockTransactionRepo) RestoreTransaction(ctx context.Context, id string) error {
+	m.restored = append(m.restored, id)
+	return nil
+}
+
+func (m *mockTransactionRepo) GetPurgeableTransactionIds(ctx context.Context, before time.Time) ([]string, error) {
+	return m.purgeable, nil
+}
+
+func (m *mockTransactionRepo) PurgeTransaction(ctx context.Context, id string, before time.Time) error {
+	if m.purgeFunc != nil {
+		return m.purgeFunc(ctx, id)
+	}
+	m.purged = append(m.purged, id)
+	return nil
+}
+
 func (m *mockTransactionRepo) DeleteTransactionsByUserId(ctx context.Context, id string) error {
 	return nil
 }
@@ -57,14 +148,21 @@ type mockOriginRepo struct {
 	origins   map[string]*domain.Origin
 	getErr    error
 	updateLog []originUpdateCall
+	deleted   []string
 }
 
 func newMockOriginRepo(origins map[string]*domain.Origin) *mockOriginRepo {
 	return &mockOriginRepo{origins: origins}
 }
 
-func (m *mockOriginRepo) GetOriginsByUserId(ctx context.Context, userId string) ([]domain.Origin, error) {
-	return nil, nil
+func (m *mockOriginRepo) GetOriginsByUserId(ctx context.Context, userId string, includeArchived bool) ([]domain.Origin, error) {
+	var origins []domain.Origin
+	for _, o := range m.origins {
+		if o.UserId == userId && (includeArchived || !o.IsArchived()) {
+			origins = append(origins, *o)
+		}
+	}
+	return origins, nil
 }
 
 func (m *mockOriginRepo) GetOriginById(ctx context.Context, id string) (*domain.Origin, error) {
@@ -88,10 +186,38 @@ func (m *mockOriginRepo) UpdateOrigin(ctx context.Context, id string, updated *d
 	return updated, nil
 }
 
+func (m *mockOriginRepo) ArchiveOrigin(ctx context.Context, id string) error {
+	now := time.Now()
+	m.origins[id].ArchivedAt = &now
+	return nil
+}
+
+func (m *mockOriginRepo) UnarchiveOrigin(ctx context.Context, id string) error {
+	m.origins[id].ArchivedAt = nil
+	return nil
+}
+
 func (m *mockOriginRepo) DeleteOrigin(ctx context.Context, id string) error {
+	m.deleted = append(m.deleted, id)
+	return nil
+}
+
+func (m *mockOriginRepo) GetDeletedOrigins(ctx context.Context, userId string) ([]domain.Origin, error) {
+	return nil, nil
+}
+
+func (m *mockOriginRepo) GetDeletedOriginById(ctx context.Context, id string) (*domain.Origin, error) {
+	return nil, domain.ErrDataNotFound
+}
+
+func (m *mockOriginRepo) RestoreOrigin(ctx context.Context, id string) error {
 	return nil
 }
 
+func (m *mockOriginRepo) PurgeOrigins(ctx context.Context, before time.Time) (int64, error) {
+	return 0, nil
+}
+
 // noopTxManager runs fn directly against the given ctx, without any real
 // transactional guarantees - sufficient for unit tests against in-memory mocks.
 type noopTxManager struct{}
@@ -105,7 +231,7 @@ func (noopTxManager) WithTransaction(ctx context.Context, fn func(ctx context.Co
 func strPtr(s string) *string { return &s }
 
 func newTransactionService(tRepo *mockTransactionRepo, oRepo *mockOriginRepo) *TransactionService {
-	return NewTransactionService(tRepo, oRepo, noopTxManager{})
+	return NewTransactionService(tRepo, oRepo, noopTxManager{}, nil, NewSnapshotService(newMockSnapshotRepo(), oRepo), NewLedgerService(&mockLedgerRepo{}, oRepo, tRepo, noopTxManager{}), NewAuditService(&mockAuditRepo{}))
 }
 
 // --- UpdateTotalOrigin ---
@@ -140,6 +266,27 @@ func TestUpdateTotalOrigin_Output(t *testing.T) {
 	}
 }
 
+func TestUpdateTotalOrigin_LiabilityOutputRaisesDebt(t *testing.T) {
+	oRepo := newMockOriginRepo(map[string]*domain.Origin{
+		"card": {ID: "card", Kind: domain.OriginCreditCard, Total: 200},
+	})
+	ts := newTransactionService(&mockTransactionRepo{}, oRepo)
+
+	if err := ts.UpdateTotalOrigin(context.Background(), "card", "Output", 50); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := oRepo.origins["card"].Total; got != 250 {
+		t.Errorf("expected owed 250 after a purchase, got %v", got)
+	}
+
+	if err := ts.UpdateTotalOrigin(context.Background(), "card", "Income", 100); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := oRepo.origins["card"].Total; got != 150 {
+		t.Errorf("expected owed 150 after a payment, got %v", got)
+	}
+}
+
 func TestUpdateTotalOrigin_OriginNotFound(t *testing.T) {
 	oRepo := newMockOriginRepo(map[string]*domain.Origin{})
 	ts := newTransactionService(&mockTransactionRepo{}, oRepo)
@@ -277,13 +424,7 @@ func TestUpdateTransaction_OriginChanges(t *testing.T) {
 	}
 }
 
-// TestUpdateTransaction_NilActualOriginId_Panics documents a bug in
-// reconcileOriginBalance: it dereferences actualTransaction.OriginId
-// (line "*actualTransaction.OriginId != *updatedTransaction.OriginId")
-// before the subsequent nil check, so a transaction loaded from storage
-// without an origin (OriginId == nil) crashes UpdateTransaction instead
-// of being handled by the "actual.OriginId == nil" branch below it.
-func TestUpdateTransaction_NilActualOriginId_Panics(t *testing.T) {
+func TestUpdateTransaction_NilActualOriginId(t *testing.T) {
 	actual := &domain.Transaction{OriginId: nil, Type: "Income", Amount: 100}
 	updated := &domain.Transaction{OriginId: strPtr("o1"), Type: "Income", Amount: 100}
 
@@ -298,11 +439,299 @@ func TestUpdateTransaction_NilActualOriginId_Panics(t *testing.T) {
 	}
 	ts := newTransactionService(tRepo, oRepo)
 
-	defer func() {
-		if r := recover(); r == nil {
-			t.Fatalf("expected a nil-pointer panic (known bug in reconcileOriginBalance); UpdateTransaction returned normally instead - the bug may have been fixed, update this test")
+	if _, err := ts.UpdateTransaction(context.Background(), "t1", updated); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// nothing to revert, o1 gets the +100 income => 0 + 100 = 100
+	if got := oRepo.origins["o1"].Total; got != 100 {
+		t.Errorf("expected o1 total 100, got %v", got)
+	}
+}
+
+func TestUpdateTransaction_RemovesOrigin(t *testing.T) {
+	actual := &domain.Transaction{OriginId: strPtr("o1"), Type: "Output", Amount: 40}
+	updated := &domain.Transaction{OriginId: nil, Type: "Output", Amount: 40}
+
+	oRepo := newMockOriginRepo(map[string]*domain.Origin{
+		"o1": {ID: "o1", Total: 60},
+	})
+	tRepo := &mockTransactionRepo{
+		getByIdFunc: func(ctx context.Context, id string) (*domain.Transaction, error) { return actual, nil },
+		updateFunc: func(ctx context.Context, id string, tx *domain.Transaction) (*domain.Transaction, error) {
+			return tx, nil
+		},
+	}
+	ts := newTransactionService(tRepo, oRepo)
+
+	if _, err := ts.UpdateTransaction(context.Background(), "t1", updated); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// o1 gets the -40 output reverted => 60 + 40 = 100
+	if got := oRepo.origins["o1"].Total; got != 100 {
+		t.Errorf("expected o1 total 100, got %v", got)
+	}
+}
+
+func TestRestoreTransaction_ReappliesBalance(t *testing.T) {
+	deletedAt := time.Now()
+	trashed := &domain.Transaction{ID: "t1", UserId: "u1", OriginId: strPtr("o1"), Type: "Output", Amount: 40, DeletedAt: &deletedAt}
+
+	oRepo := newMockOriginRepo(map[string]*domain.Origin{
+		"o1": {ID: "o1", Total: 100},
+	})
+	tRepo := &mockTransactionRepo{
+		getDeletedFunc: func(ctx context.Context, id string) (*domain.Transaction, error) {
+			copy := *trashed
+			return &copy, nil
+		},
+		getByIdFunc: func(ctx context.Context, id string) (*domain.Transaction, error) {
+			restored := *trashed
+			restored.DeletedAt = nil
+			return &restored, nil
+		},
+	}
+	ts := newTransactionService(tRepo, oRepo)
+
+	restored, err := ts.RestoreTransaction(context.Background(), "t1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if restored.DeletedAt != nil {
+		t.Errorf("expected the restored transaction out of the trash")
+	}
+	if len(tRepo.restored) != 1 || tRepo.restored[0] != "t1" {
+		t.Errorf("expected t1 to be restored, got %v", tRepo.restored)
+	}
+	if got := oRepo.origins["o1"].Total; got != 60 {
+		t.Errorf("expected total 60, got %v", got)
+	}
+}
+
+func TestRestoreTransaction_ReconciledPeriod(t *testing.T) {
+	through := time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)
+	deletedAt := time.Now()
+	trashed := &domain.Transaction{ID: "t1", OriginId: strPtr("o1"), Type: "Output", Amount: 40, CreatedAt: through.AddDate(0, 0, -5), DeletedAt: &deletedAt}
+
+	oRepo := newMockOriginRepo(map[string]*domain.Origin{
+		"o1": {ID: "o1", Total: 100, ReconciledThrough: &through},
+	})
+	tRepo := &mockTransactionRepo{
+		getDeletedFunc: func(ctx context.Context, id string) (*domain.Transaction, error) { return trashed, nil },
+	}
+	ts := newTransactionService(tRepo, oRepo)
+
+	if _, err := ts.RestoreTransaction(context.Background(), "t1"); err != domain.ErrReconciledPeriod {
+		t.Fatalf("expected ErrReconciledPeriod, got %v", err)
+	}
+	if len(tRepo.restored) != 0 {
+		t.Errorf("expected nothing restored, got %v", tRepo.restored)
+	}
+	if got := oRepo.origins["o1"].Total; got != 100 {
+		t.Errorf("expected total untouched at 100, got %v", got)
+	}
+}
+
+func TestRestoreTransaction_NotInTrash(t *testing.T) {
+	ts := newTransactionService(&mockTransactionRepo{}, newMockOriginRepo(map[string]*domain.Origin{}))
+
+	if _, err := ts.RestoreTransaction(context.Background(), "t1"); err != domain.ErrDataNotFound {
+		t.Fatalf("expected ErrDataNotFound, got %v", err)
+	}
+}
+
+func TestCreateTransaction_ArchivedOrigin(t *testing.T) {
+	archivedAt := time.Now()
+	oRepo := newMockOriginRepo(map[string]*domain.Origin{
+		"o1": {ID: "o1", Total: 100, ArchivedAt: &archivedAt},
+	})
+	ts := newTransactionService(&mockTransactionRepo{}, oRepo)
+
+	transaction := &domain.Transaction{UserId: "u1", OriginId: strPtr("o1"), Type: "Output", Amount: 40}
+	if _, err := ts.CreateTransaction(context.Background(), transaction); err != domain.ErrArchivedOrigin {
+		t.Fatalf("expected ErrArchivedOrigin, got %v", err)
+	}
+	if got := oRepo.origins["o1"].Total; got != 100 {
+		t.Errorf("expected total untouched at 100, got %v", got)
+	}
+}
+
+func TestUpdateTransaction_IntoArchivedOrigin(t *testing.T) {
+	archivedAt := time.Now()
+	actual := &domain.Transaction{OriginId: strPtr("o1"), Type: "Income", Amount: 100}
+	updated := &domain.Transaction{OriginId: strPtr("o2"), Type: "Income", Amount: 100}
+
+	oRepo := newMockOriginRepo(map[string]*domain.Origin{
+		"o1": {ID: "o1", Total: 300},
+		"o2": {ID: "o2", Total: 500, ArchivedAt: &archivedAt},
+	})
+	tRepo := &mockTransactionRepo{
+		getByIdFunc: func(ctx context.Context, id string) (*domain.Transaction, error) { return actual, nil },
+		updateFunc: func(ctx context.Context, id string, tx *domain.Transaction) (*domain.Transaction, error) {
+			t.Fatalf("expected no update")
+			return tx, nil
+		},
+	}
+	ts := newTransactionService(tRepo, oRepo)
+
+	if _, err := ts.UpdateTransaction(context.Background(), "t1", updated); err != domain.ErrArchivedOrigin {
+		t.Fatalf("expected ErrArchivedOrigin, got %v", err)
+	}
+}
+
+func TestUpdateTransaction_OnArchivedOriginKeepsHistoryEditable(t *testing.T) {
+	archivedAt := time.Now()
+	actual := &domain.Transaction{OriginId: strPtr("o1"), Type: "Output", Amount: 100}
+	updated := &domain.Transaction{OriginId: strPtr("o1"), Type: "Output", Amount: 100, Description: "Closing fee"}
+
+	oRepo := newMockOriginRepo(map[string]*domain.Origin{
+		"o1": {ID: "o1", Total: 0, ArchivedAt: &archivedAt},
+	})
+	tRepo := &mockTransactionRepo{
+		getByIdFunc: func(ctx context.Context, id string) (*domain.Transaction, error) { return actual, nil },
+		updateFunc: func(ctx context.Context, id string, tx *domain.Transaction) (*domain.Transaction, error) {
+			return tx, nil
+		},
+	}
+	ts := newTransactionService(tRepo, oRepo)
+
+	if _, err := ts.UpdateTransaction(context.Background(), "t1", updated); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestUpdateTransaction_DebtPaymentRefusesRebooking(t *testing.T) {
+	date := time.Date(2026, time.March, 5, 0, 0, 0, 0, time.UTC)
+	split := &domain.DebtPayment{DebtId: "d1", Principal: 80, Interest: 20}
+	actual := &domain.Transaction{OriginId: strPtr("o1"), Type: "Income", Amount: 80, DebtPayment: split, CreatedAt: date}
+
+	oRepo := newMockOriginRepo(map[string]*domain.Origin{
+		"o1": {ID: "o1", Kind: domain.OriginLoan, Total: 920},
+	})
+	tRepo := &mockTransactionRepo{
+		getByIdFunc: func(ctx context.Context, id string) (*domain.Transaction, error) { return actual, nil },
+		updateFunc: func(ctx context.Context, id string, tx *domain.Transaction) (*domain.Transaction, error) {
+			return tx, nil
+		},
+	}
+	ts := newTransactionService(tRepo, oRepo)
+
+	for name, updated := range map[string]*domain.Transaction{
+		"amount": {OriginId: strPtr("o1"), Type: "Income", Amount: 100, CreatedAt: date},
+		"date":   {OriginId: strPtr("o1"), Type: "Income", Amount: 80, CreatedAt: date.AddDate(0, 1, 0)},
+		"origin": {OriginId: strPtr("o2"), Type: "Income", Amount: 80, CreatedAt: date},
+	} {
+		if _, err := ts.UpdateTransaction(context.Background(), "t1", updated); err != domain.ErrDebtPaymentEdit {
+			t.Errorf("%s: expected ErrDebtPaymentEdit, got %v", name, err)
+		}
+	}
+	if got := oRepo.origins["o1"].Total; got != 920 {
+		t.Errorf("expected o1 total untouched at 920, got %v", got)
+	}
+
+	// a new description leaves the booking and the split alone
+	updated := &domain.Transaction{OriginId: strPtr("o1"), Type: "Income", Amount: 80, CreatedAt: date, Description: "March installment"}
+	if _, err := ts.UpdateTransaction(context.Background(), "t1", updated); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if updated.DebtPayment != split {
+		t.Errorf("expected the debt payment split kept, got %+v", updated.DebtPayment)
+	}
+}
+
+func TestUpdateTransaction_LockedPeriodRefusesBalanceEdits(t *testing.T) {
+	reconciledThrough := time.Date(2026, time.March, 31, 0, 0, 0, 0, time.UTC)
+	date := time.Date(2026, time.March, 10, 0, 0, 0, 0, time.UTC)
+	actual := &domain.Transaction{OriginId: strPtr("o1"), Type: "Output", Amount: 50, CreatedAt: date}
+
+	oRepo := newMockOriginRepo(map[string]*domain.Origin{
+		"o1": {ID: "o1", Total: 500, ReconciledThrough: &reconciledThrough},
+		"o2": {ID: "o2", Total: 0},
+	})
+	tRepo := &mockTransactionRepo{
+		getByIdFunc: func(ctx context.Context, id string) (*domain.Transaction, error) { return actual, nil },
+		updateFunc: func(ctx context.Context, id string, tx *domain.Transaction) (*domain.Transaction, error) {
+			return tx, nil
+		},
+	}
+	ts := newTransactionService(tRepo, oRepo)
+
+	for name, updated := range map[string]*domain.Transaction{
+		"amount":          {OriginId: strPtr("o1"), Type: "Output", Amount: 70, CreatedAt: date},
+		"type":            {OriginId: strPtr("o1"), Type: "Income", Amount: 50, CreatedAt: date},
+		"out of the lock": {OriginId: strPtr("o1"), Type: "Output", Amount: 50, CreatedAt: date.AddDate(0, 1, 0)},
+		"other origin":    {OriginId: strPtr("o2"), Type: "Output", Amount: 50, CreatedAt: date},
+	} {
+		if _, err := ts.UpdateTransaction(context.Background(), "t1", updated); err != domain.ErrReconciledPeriod {
+			t.Errorf("%s: expected ErrReconciledPeriod, got %v", name, err)
 		}
-	}()
+	}
+	if oRepo.origins["o1"].Total != 500 || oRepo.origins["o2"].Total != 0 {
+		t.Errorf("expected balances untouched, got o1 %v and o2 %v", oRepo.origins["o1"].Total, oRepo.origins["o2"].Total)
+	}
 
-	ts.UpdateTransaction(context.Background(), "t1", updated)
+	// a new description does not touch the reconciled balance
+	if _, err := ts.UpdateTransaction(context.Background(), "t1", &domain.Transaction{OriginId: strPtr("o1"), Type: "Output", Amount: 50, CreatedAt: date, Description: "Groceries"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestDeleteTransaction_LockedPeriod(t *testing.T) {
+	reconciledThrough := time.Date(2026, time.March, 31, 0, 0, 0, 0, time.UTC)
+	actual := &domain.Transaction{OriginId: strPtr("o1"), Type: "Output", Amount: 50, CreatedAt: time.Date(2026, time.March, 10, 0, 0, 0, 0, time.UTC)}
+
+	oRepo := newMockOriginRepo(map[string]*domain.Origin{
+		"o1": {ID: "o1", Total: 500, ReconciledThrough: &reconciledThrough},
+	})
+	tRepo := &mockTransactionRepo{
+		getByIdFunc: func(ctx context.Context, id string) (*domain.Transaction, error) { return actual, nil },
+	}
+	ts := newTransactionService(tRepo, oRepo)
+
+	if err := ts.DeleteTransaction(context.Background(), "t1"); err != domain.ErrReconciledPeriod {
+		t.Fatalf("expected ErrReconciledPeriod, got %v", err)
+	}
+	if got := oRepo.origins["o1"].Total; got != 500 {
+		t.Errorf("expected o1 total untouched at 500, got %v", got)
+	}
+}
+
+type failingAttachmentService struct {
+	mockAttachmentService
+	failFor string
+	deleted []string
+}
+
+func (m *failingAttachmentService) DeleteAttachmentsByTransactionId(ctx context.Context, transactionId string) error {
+	if transactionId == m.failFor {
+		return domain.ErrInternal
+	}
+	m.deleted = append(m.deleted, transactionId)
+	return nil
+}
+
+func TestPurgeDeletedTransactions_OneAtATime(t *testing.T) {
+	oRepo := newMockOriginRepo(map[string]*domain.Origin{})
+	tRepo := &mockTransactionRepo{purgeable: []string{"t1", "restored", "t2", "t3"}}
+	tRepo.purgeFunc = func(ctx context.Context, id string) error {
+		if id == "restored" {
+			return domain.ErrDataNotFound
+		}
+		tRepo.purged = append(tRepo.purged, id)
+		return nil
+	}
+	attachments := &failingAttachmentService{failFor: "t2"}
+	ts := NewTransactionService(tRepo, oRepo, noopTxManager{}, attachments, NewSnapshotService(newMockSnapshotRepo(), oRepo), NewLedgerService(&mockLedgerRepo{}, oRepo, tRepo, noopTxManager{}), NewAuditService(&mockAuditRepo{}))
+
+	purged, err := ts.PurgeDeletedTransactions(context.Background(), time.Now())
+	if err != domain.ErrInternal {
+		t.Fatalf("expected the attachment failure, got %v", err)
+	}
+
+	// t2 is rolled back in its own transaction and t3 is left for the next run.
+	if purged != 1 || len(attachments.deleted) != 1 || attachments.deleted[0] != "t1" {
+		t.Errorf("expected only t1 purged with its attachments, got %d and %v", purged, attachments.deleted)
+	}
 }